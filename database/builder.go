@@ -15,18 +15,35 @@ type Builder interface {
 	BuildCreateTable(vModel models.Model) (Result, *cd.Error)
 	BuildDropTable(vModel models.Model) (Result, *cd.Error)
 	BuildInsert(vModel models.Model) (Result, *cd.Error)
+	BuildBatchInsert(vModels []models.Model) (Result, *cd.Error)
 	BuildUpdate(vModel models.Model) (Result, *cd.Error)
+	BuildUpdateByFilter(vModel models.Model, vFilter models.Filter, fieldNames []string) (Result, *cd.Error)
+	BuildUpdateByExpr(vModel models.Model, vFilter models.Filter, exprs []models.UpdateExpr, guards []models.UpdateGuard) (Result, *cd.Error)
 	BuildDelete(vModel models.Model) (Result, *cd.Error)
+	BuildDeleteByFilter(vModel models.Model, vFilter models.Filter) (Result, *cd.Error)
+	BuildSoftDelete(vModel models.Model) (Result, *cd.Error)
+	BuildRestore(vModel models.Model) (Result, *cd.Error)
 	BuildQuery(vModel models.Model, vFilter models.Filter) (Result, *cd.Error)
 	BuildCount(vModel models.Model, vFilter models.Filter) (Result, *cd.Error)
 
 	BuildCreateRelationTable(vModel models.Model, vField models.Field) (Result, *cd.Error)
 	BuildDropRelationTable(vModel models.Model, vField models.Field) (Result, *cd.Error)
 	BuildInsertRelation(vModel models.Model, vField models.Field, rModel models.Model) (Result, *cd.Error)
+	BuildBatchInsertRelation(vModel models.Model, vField models.Field, leftVals, rightVals []any) (Result, *cd.Error)
 	BuildDeleteRelation(vModel models.Model, vField models.Field) (Result, Result, *cd.Error)
 	BuildDeleteRelationByRights(vModel models.Model, vField models.Field, rightIDs []any) (Result, *cd.Error)
+	BuildDeleteRelationByFilter(vModel models.Model, vField models.Field, vFilter models.Filter) (Result, *cd.Error)
 	BuildQueryRelation(vModel models.Model, vField models.Field) (Result, *cd.Error)
+	BuildQueryRelationByFilter(vModel models.Model, vField models.Field, vFilter models.Filter) (Result, *cd.Error)
 	BuildBatchQueryRelation(vModel models.Model, vField models.Field, leftIDs []any) (Result, *cd.Error)
+	BuildQueryRelationModels(vModel models.Model, vField models.Field, rModel models.Model, vFilter models.Filter) (Result, *cd.Error)
+	BuildCountRelationModels(vModel models.Model, vField models.Field, rModel models.Model, vFilter models.Filter) (Result, *cd.Error)
 
 	BuildModuleValueHolder(vModel models.Model) ([]any, *cd.Error)
+
+	// SetFilterPathDepth 设置过滤条件点路径允许跨越的关系层数，如 "group.name" 为1层，小于1时使用默认值
+	SetFilterPathDepth(depth int)
 }
+
+// DefaultFilterPathDepth 过滤条件点路径默认允许跨越的关系层数
+const DefaultFilterPathDepth = 3
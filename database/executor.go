@@ -2,6 +2,7 @@ package database
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	cd "github.com/muidea/magicCommon/def"
@@ -27,6 +28,12 @@ type Config interface {
 // Executor 数据库访问对象
 type Executor interface {
 	Release()
+	// SetStatementTimeout 设置单条SQL的执行超时，timeout<=0表示不限制，超时后正在执行的SQL会被取消
+	SetStatementTimeout(timeout time.Duration)
+	StatementTimeout() time.Duration
+	// SetContext 设置后续SQL执行基于的context，ctx为nil表示使用context.Background
+	SetContext(ctx context.Context)
+	Context() context.Context
 	BeginTransaction() *cd.Error
 	CommitTransaction() *cd.Error
 	RollbackTransaction() *cd.Error
@@ -37,6 +44,20 @@ type Executor interface {
 	Execute(sql string, args ...any) (rowsAffected int64, err *cd.Error)
 	ExecuteInsert(sql string, pkValOut any, args ...any) (err *cd.Error)
 	CheckTableExist(tableName string) (bool, *cd.Error)
+	// SwitchSchema 切换当前连接使用的schema，Release时恢复为连接配置的schema，schema为空表示恢复默认
+	SwitchSchema(schema string) *cd.Error
+}
+
+// RowSource 批量导入的行数据源
+type RowSource interface {
+	// Next 返回下一行各列的值，没有更多数据时ok为false
+	Next() (values []any, ok bool, err *cd.Error)
+}
+
+// BulkLoader 支持流式批量导入的Executor，PostgreSQL基于COPY FROM STDIN实现
+type BulkLoader interface {
+	// CopyIn 将source中的行导入tableName的columns列，返回导入的行数
+	CopyIn(tableName string, columns []string, source RowSource) (int64, *cd.Error)
 }
 
 type Pool interface {
@@ -45,6 +66,23 @@ type Pool interface {
 	GetExecutor(ctx context.Context) (Executor, *cd.Error)
 	GetStats() PoolStats
 	CheckConfig(config Config) *cd.Error
+	// SetRetryPolicy 设置获取连接与事务外只读查询的重试策略，需在Initialize之前设置才对初始连接生效
+	SetRetryPolicy(policy *RetryPolicy)
+	// HealthCheck Ping数据库并返回连接池状态
+	HealthCheck(ctx context.Context) HealthStatus
+	// Drain 停止分配Executor并等待已借出的Executor全部Release，超时返回cd.Timeout
+	Drain(timeout time.Duration) *cd.Error
+	// Replace 使用新配置替换当前连接，已借出的Executor继续使用旧连接，旧连接排空或drainTimeout超时后关闭，drainTimeout<=0表示一直等待
+	Replace(config Config, drainTimeout time.Duration) *cd.Error
 	IncReference() int
 	DecReference() int
 }
+
+// NewExecuteError 转换SQL执行错误，超时或者context deadline返回cd.Timeout，其他返回cd.Unexpected
+func NewExecuteError(ctx context.Context, err error) *cd.Error {
+	if errors.Is(err, context.DeadlineExceeded) || (ctx != nil && errors.Is(ctx.Err(), context.DeadlineExceeded)) {
+		return cd.NewError(cd.Timeout, err.Error())
+	}
+
+	return cd.NewError(cd.Unexpected, err.Error())
+}
@@ -52,6 +52,15 @@ func RecordDatabaseTransaction(databaseName string, txType string, success bool)
 	collector.RecordTransaction(databaseName, txType, success)
 }
 
+func RecordDatabaseRetry(databaseName string, operation string, outcome string) {
+	collector := metricsdb.GetDatabaseMetricsCollector()
+	if collector == nil {
+		return
+	}
+
+	collector.RecordRetry(databaseName, operation, outcome)
+}
+
 func UpdateDatabaseConnectionStats(databaseName string, dbHandle *sql.DB) {
 	collector := metricsdb.GetDatabaseMetricsCollector()
 	if collector == nil || dbHandle == nil {
@@ -2,6 +2,7 @@ package mysql
 
 import (
 	"fmt"
+	"strings"
 
 	cd "github.com/muidea/magicCommon/def"
 
@@ -17,23 +18,65 @@ import (
 type Builder struct {
 	modelProvider provider.Provider
 	buildCodec    codec.Codec
+	// filterPathDepth 过滤条件点路径允许跨越的关系层数
+	filterPathDepth int
 }
 
 // New create builder
 func NewBuilder(provider provider.Provider, codec codec.Codec) database.Builder {
 	return &Builder{
-		modelProvider: provider,
-		buildCodec:    codec,
+		modelProvider:   provider,
+		buildCodec:      codec,
+		filterPathDepth: database.DefaultFilterPathDepth,
 	}
 }
 
+// SetFilterPathDepth 设置过滤条件点路径允许跨越的关系层数，小于1时使用默认值
+func (s *Builder) SetFilterPathDepth(depth int) {
+	if depth < 1 {
+		depth = database.DefaultFilterPathDepth
+	}
+	s.filterPathDepth = depth
+}
+
 func (s *Builder) buildFilter(vModel models.Model, filter models.Filter, resultStackPtr *ResultStack) (ret string, err *cd.Error) {
 	if filter == nil {
+		ret = s.buildDeletedFilter(vModel, models.ExcludeDeleted)
 		return
 	}
 
 	filterSQL := ""
 	for _, field := range vModel.GetFields() {
+		if !models.IsBasicField(field) {
+			pathItems := filter.GetPathFilterItems(field.GetName())
+			if len(pathItems) > 0 {
+				pathSQL, pathErr := s.buildPathFilterItem(vModel, field, pathItems, 1, resultStackPtr)
+				if pathErr != nil {
+					err = pathErr
+					slog.Error("buildFilter failed", "operation", "s.buildPathFilterItem", "field", field.GetName(), "error", err.Error())
+					return
+				}
+
+				if filterSQL == "" {
+					filterSQL = pathSQL
+				} else {
+					filterSQL = fmt.Sprintf("%s AND %s", filterSQL, pathSQL)
+				}
+			}
+
+			existsItem := filter.GetExistsItem(field.GetName())
+			if existsItem != nil {
+				existsSQL, existsErr := s.buildExistsFilterItem(vModel, field, existsItem, resultStackPtr)
+				if existsErr != nil {
+					err = existsErr
+					slog.Error("buildFilter failed", "operation", "s.buildExistsFilterItem", "field", field.GetName(), "error", err.Error())
+					return
+				}
+
+				filterSQL = joinFilterSQL(filterSQL, existsSQL)
+			}
+		}
+
 		filterItem := filter.GetFilterItem(field.GetName())
 		if filterItem == nil {
 			continue
@@ -71,11 +114,42 @@ func (s *Builder) buildFilter(vModel models.Model, filter models.Filter, resultS
 		filterSQL = fmt.Sprintf("%s AND %s", filterSQL, relationSQL)
 	}
 
+	deletedSQL := s.buildDeletedFilter(vModel, filter.GetDeletedScope())
+	if deletedSQL != "" {
+		if filterSQL == "" {
+			filterSQL = deletedSQL
+		} else {
+			filterSQL = fmt.Sprintf("%s AND %s", filterSQL, deletedSQL)
+		}
+	}
+
 	ret = filterSQL
 	return
 }
 
+// buildDeletedFilter 声明了软删除字段的模型按scope过滤已删除的行
+func (s *Builder) buildDeletedFilter(vModel models.Model, scope models.DeletedScope) (ret string) {
+	deletedField := models.GetSoftDeleteField(vModel)
+	if deletedField == nil {
+		return
+	}
+
+	switch scope {
+	case models.IncludeDeleted:
+	case models.OnlyDeleted:
+		ret = fmt.Sprintf("`%s` IS NOT NULL", deletedField.GetName())
+	default:
+		ret = fmt.Sprintf("`%s` IS NULL", deletedField.GetName())
+	}
+	return
+}
+
 func (s *Builder) buildBasicFilterItem(vField models.Field, filterItem models.FilterItem, resultStackPtr *ResultStack) (ret string, err *cd.Error) {
+	if filterItem.OprCode() == models.MatchOpr && !models.IsFullTextField(vField) {
+		err = cd.NewError(cd.IllegalParam, fmt.Sprintf("match filter requires a fulltext field, field:%s", vField.GetName()))
+		return
+	}
+
 	oprValue := filterItem.OprValue()
 	oprFunc := getOprFunc(filterItem)
 	var fieldVal any
@@ -106,6 +180,11 @@ func (s *Builder) buildBasicFilterItem(vField models.Field, filterItem models.Fi
 }
 
 func (s *Builder) buildRelationFilterItem(vModel models.Model, vField models.Field, filterItem models.FilterItem, resultStackPtr *ResultStack) (ret string, err *cd.Error) {
+	if filterItem.OprCode() == models.MatchOpr {
+		err = cd.NewError(cd.IllegalParam, fmt.Sprintf("match filter requires a fulltext field, field:%s", vField.GetName()))
+		return
+	}
+
 	oprValue := filterItem.OprValue()
 	oprFunc := getOprFunc(filterItem)
 
@@ -148,26 +227,218 @@ func (s *Builder) buildRelationFilterItem(vModel models.Model, vField models.Fie
 	return
 }
 
-func (s *Builder) buildSorter(vModel models.Model, filter models.Sorter) (ret string, err *cd.Error) {
-	if filter == nil {
+// buildPathFilterItem 点路径过滤条件，经关系表与关联模型表逐层生成子查询
+//
+// "group.name" 生成 `id` IN (SELECT `left` FROM 关系表 WHERE `right` IN (SELECT `id` FROM 关联表 WHERE `name` = ?))，
+// depth为当前关系层数，超过 SetFilterPathDepth 设置的层数时返回IllegalParam。
+func (s *Builder) buildPathFilterItem(vModel models.Model, vField models.Field, pathItems map[string]models.FilterItem, depth int, resultStackPtr *ResultStack) (ret string, err *cd.Error) {
+	if depth > s.filterPathDepth {
+		err = cd.NewError(cd.IllegalParam, fmt.Sprintf("filter path exceeds max depth %d, field:%s", s.filterPathDepth, vField.GetName()))
+		return
+	}
+
+	rModel, rErr := s.modelProvider.GetTypeModel(vField.GetType().Elem())
+	if rErr != nil {
+		err = rErr
+		slog.Error("buildPathFilterItem failed", "field", vField.GetName(), "operation", "s.modelProvider.GetTypeModel", "error", err.Error())
+		return
+	}
+
+	subItems := map[string]map[string]models.FilterItem{}
+	for path, filterItem := range pathItems {
+		fieldName, subPath, _ := strings.Cut(path, models.FieldPathSeparator)
+		rField := rModel.GetField(fieldName)
+		if fieldName == "" || rField == nil || (subPath != "" && models.IsBasicField(rField)) {
+			err = cd.NewError(cd.IllegalParam, fmt.Sprintf("illegal filter path, field:%s, path:%s", vField.GetName(), path))
+			return
+		}
+		if subPath == "" {
+			continue
+		}
+		if subItems[fieldName] == nil {
+			subItems[fieldName] = map[string]models.FilterItem{}
+		}
+		subItems[fieldName][subPath] = filterItem
+	}
+
+	rFilterSQL := ""
+	for _, rField := range rModel.GetFields() {
+		itemSQL := ""
+		if filterItem, ok := pathItems[rField.GetName()]; ok {
+			if models.IsBasicField(rField) {
+				itemSQL, err = s.buildBasicFilterItem(rField, filterItem, resultStackPtr)
+			} else {
+				itemSQL, err = s.buildRelationFilterItem(rModel, rField, filterItem, resultStackPtr)
+			}
+			if err != nil {
+				slog.Error("buildPathFilterItem failed", "field", vField.GetName(), "path", rField.GetName(), "error", err.Error())
+				return
+			}
+			rFilterSQL = joinFilterSQL(rFilterSQL, itemSQL)
+		}
+		if items, ok := subItems[rField.GetName()]; ok {
+			itemSQL, err = s.buildPathFilterItem(rModel, rField, items, depth+1, resultStackPtr)
+			if err != nil {
+				return
+			}
+			rFilterSQL = joinFilterSQL(rFilterSQL, itemSQL)
+		}
+	}
+	rFilterSQL = joinFilterSQL(rFilterSQL, s.buildDeletedFilter(rModel, models.ExcludeDeleted))
+
+	relationTableName, relationErr := s.buildCodec.ConstructRelationTableName(vModel, vField)
+	if relationErr != nil {
+		err = relationErr
+		slog.Error("buildPathFilterItem failed", "field", vField.GetName(), "operation", "s.buildCodec.ConstructRelationTableName", "error", err.Error())
+		return
+	}
+
+	rightSQL := fmt.Sprintf("SELECT `%s` FROM `%s` WHERE %s", rModel.GetPrimaryField().GetName(), s.buildCodec.ConstructModelTableName(rModel), rFilterSQL)
+	ret = fmt.Sprintf("`%s` IN (SELECT `left` FROM `%s` WHERE `right` IN (%s))", vModel.GetPrimaryField().GetName(), relationTableName, rightSQL)
+	return
+}
+
+// buildExistsFilterItem 关系字段的存在性条件，生成以host主键关联关系表的相关子查询
+//
+// Exists("group", filter) 生成 EXISTS (SELECT 1 FROM 关系表 WHERE `left` = host.`id` AND `right` IN (SELECT `id` FROM 关联表 WHERE `name` = ?))，
+// 关联对象的条件与软删除范围由子filter决定，子filter为空时只排除已软删除的关联对象。
+func (s *Builder) buildExistsFilterItem(vModel models.Model, vField models.Field, existsItem models.ExistsItem, resultStackPtr *ResultStack) (ret string, err *cd.Error) {
+	rModel, rErr := s.modelProvider.GetTypeModel(vField.GetType().Elem())
+	if rErr != nil {
+		err = rErr
+		slog.Error("buildExistsFilterItem failed", "field", vField.GetName(), "operation", "s.modelProvider.GetTypeModel", "error", err.Error())
+		return
+	}
+
+	rFilterSQL, rFilterErr := s.buildFilter(rModel, existsItem.SubFilter(), resultStackPtr)
+	if rFilterErr != nil {
+		err = rFilterErr
+		slog.Error("buildExistsFilterItem failed", "field", vField.GetName(), "operation", "s.buildFilter", "error", err.Error())
+		return
+	}
+
+	relationTableName, relationErr := s.buildCodec.ConstructRelationTableName(vModel, vField)
+	if relationErr != nil {
+		err = relationErr
+		slog.Error("buildExistsFilterItem failed", "field", vField.GetName(), "operation", "s.buildCodec.ConstructRelationTableName", "error", err.Error())
+		return
+	}
+
+	existsSQL := fmt.Sprintf("SELECT 1 FROM `%s` WHERE `left` = `%s`.`%s`", relationTableName, s.buildCodec.ConstructModelTableName(vModel), vModel.GetPrimaryField().GetName())
+	if rFilterSQL != "" {
+		existsSQL = fmt.Sprintf("%s AND `right` IN (SELECT `%s` FROM `%s` WHERE %s)", existsSQL, rModel.GetPrimaryField().GetName(), s.buildCodec.ConstructModelTableName(rModel), rFilterSQL)
+	}
+
+	if existsItem.NotExists() {
+		ret = fmt.Sprintf("NOT EXISTS (%s)", existsSQL)
+		return
+	}
+
+	ret = fmt.Sprintf("EXISTS (%s)", existsSQL)
+	return
+}
+
+func joinFilterSQL(filterSQL, itemSQL string) string {
+	if itemSQL == "" {
+		return filterSQL
+	}
+	if filterSQL == "" {
+		return itemSQL
+	}
+
+	return fmt.Sprintf("%s AND %s", filterSQL, itemSQL)
+}
+
+// buildSorter 排序字段为 models.SortRelevance 时按Match条件的相关度排序，相关度表达式的参数压入resultStackPtr
+func (s *Builder) buildSorter(vModel models.Model, filter models.Filter, resultStackPtr *ResultStack) (ret string, err *cd.Error) {
+	sorter := filter.Sorter()
+	if sorter == nil {
+		return
+	}
+	if sorter.Name() == models.SortRelevance {
+		ret, err = s.buildRelevanceSorter(vModel, filter, sorter.AscSort(), resultStackPtr)
 		return
 	}
 
 	for _, field := range vModel.GetFields() {
-		if field.GetName() == filter.Name() {
-			ret = SortOpr(filter.Name(), filter.AscSort())
+		if field.GetName() == sorter.Name() {
+			ret = SortOpr(sorter.Name(), sorter.AscSort())
 			return
 		}
 	}
 
-	err = cd.NewError(cd.Unexpected, fmt.Sprintf("illegal sort field name:%s", filter.Name()))
+	err = cd.NewError(cd.Unexpected, fmt.Sprintf("illegal sort field name:%s", sorter.Name()))
 	slog.Error("buildSorter failed", "error", err.Error())
 	return
 }
 
+// buildRelevanceSorter 多个全文检索字段的相关度相加后排序
+func (s *Builder) buildRelevanceSorter(vModel models.Model, filter models.Filter, ascSort bool, resultStackPtr *ResultStack) (ret string, err *cd.Error) {
+	relevanceSQL := ""
+	for _, field := range vModel.GetFields() {
+		filterItem := filter.GetFilterItem(field.GetName())
+		if filterItem == nil || filterItem.OprCode() != models.MatchOpr || !models.IsBasicField(field) {
+			continue
+		}
+
+		fieldVal, fieldErr := s.modelProvider.EncodeValue(filterItem.OprValue().Get(), field.GetType())
+		if fieldErr != nil {
+			err = fieldErr
+			slog.Error("buildRelevanceSorter failed", "field", field.GetName(), "operation", "s.modelProvider.EncodeValue", "error", err.Error())
+			return
+		}
+
+		itemSQL := RelevanceOpr(field.GetName(), fieldVal, resultStackPtr)
+		if relevanceSQL == "" {
+			relevanceSQL = itemSQL
+		} else {
+			relevanceSQL = fmt.Sprintf("%s + %s", relevanceSQL, itemSQL)
+		}
+	}
+	if relevanceSQL == "" {
+		err = cd.NewError(cd.IllegalParam, "relevance sort requires a match filter")
+		slog.Error("buildRelevanceSorter failed", "error", err.Error())
+		return
+	}
+
+	if ascSort {
+		ret = fmt.Sprintf("%s ASC", relevanceSQL)
+		return
+	}
+
+	ret = fmt.Sprintf("%s DESC", relevanceSQL)
+	return
+}
+
 func (s *Builder) buildFieldFilter(vField models.Field, resultStackPtr *ResultStack) (ret string, err *cd.Error) {
 	fieldName := vField.GetName()
 	resultStackPtr.PushArgs(vField.GetValue().Get())
 	ret = fmt.Sprintf("`%s` = ?", fieldName)
 	return
 }
+
+// buildFilterWhere 生成filter对应的WHERE子句，filter为空时返回空串
+func (s *Builder) buildFilterWhere(vModel models.Model, filter models.Filter, resultStackPtr *ResultStack) (ret string, err *cd.Error) {
+	filterSQL, filterErr := s.buildFilter(vModel, filter, resultStackPtr)
+	if filterErr != nil {
+		err = filterErr
+		return
+	}
+
+	if filterSQL != "" {
+		ret = fmt.Sprintf(" WHERE %s", filterSQL)
+	}
+	return
+}
+
+// buildFilterKeys 满足filter的host主键子查询，用于定位关系表中的行
+func (s *Builder) buildFilterKeys(vModel models.Model, filter models.Filter, resultStackPtr *ResultStack) (ret string, err *cd.Error) {
+	whereSQL, whereErr := s.buildFilterWhere(vModel, filter, resultStackPtr)
+	if whereErr != nil {
+		err = whereErr
+		return
+	}
+
+	ret = fmt.Sprintf("SELECT `%s` FROM `%s`%s", vModel.GetPrimaryField().GetName(), s.buildCodec.ConstructModelTableName(vModel), whereSQL)
+	return
+}
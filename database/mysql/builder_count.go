@@ -13,19 +13,37 @@ import (
 
 // BuildCount build count
 func (s *Builder) BuildCount(vModel models.Model, filter models.Filter) (ret database.Result, err *cd.Error) {
+	ret, err = s.buildCount(vModel, filter, &ResultStack{}, "")
+	return
+}
+
+// BuildCountRelationModels 统计vModel的vField关系中满足filter的关联对象数量
+func (s *Builder) BuildCountRelationModels(vModel models.Model, vField models.Field, rModel models.Model, filter models.Filter) (ret database.Result, err *cd.Error) {
 	resultStackPtr := &ResultStack{}
+	ownerSQL, ownerErr := s.buildRelationOwnerFilter(vModel, vField, rModel, resultStackPtr)
+	if ownerErr != nil {
+		err = ownerErr
+		slog.Error("BuildCountRelationModels failed", "field", vField.GetName(), "operation", "s.buildRelationOwnerFilter", "error", err.Error())
+		return
+	}
+
+	ret, err = s.buildCount(rModel, filter, resultStackPtr, ownerSQL)
+	return
+}
+
+// buildCount ownerSQL非空时作为首个统计条件，其参数需已压入resultStackPtr
+func (s *Builder) buildCount(vModel models.Model, filter models.Filter, resultStackPtr *ResultStack, ownerSQL string) (ret database.Result, err *cd.Error) {
 	countSQL := fmt.Sprintf("SELECT COUNT(*) FROM `%s`", s.buildCodec.ConstructModelTableName(vModel))
-	if filter != nil {
-		filterSQL, filterErr := s.buildFilter(vModel, filter, resultStackPtr)
-		if filterErr != nil {
-			err = filterErr
-			slog.Error("BuildCount failed", "value", "s.buildFilter", "error", err.Error())
-			return
-		}
-
-		if filterSQL != "" {
-			countSQL = fmt.Sprintf("%s WHERE %s", countSQL, filterSQL)
-		}
+	filterSQL, filterErr := s.buildFilter(vModel, filter, resultStackPtr)
+	if filterErr != nil {
+		err = filterErr
+		slog.Error("BuildCount failed", "value", "s.buildFilter", "error", err.Error())
+		return
+	}
+
+	filterSQL = joinFilterSQL(ownerSQL, filterSQL)
+	if filterSQL != "" {
+		countSQL = fmt.Sprintf("%s WHERE %s", countSQL, filterSQL)
 	}
 
 	if traceSQL() {
@@ -36,6 +36,11 @@ func (s *Builder) BuildCreateTable(vModel models.Model) (ret database.Result, er
 
 	pkFieldName := vModel.GetPrimaryField().GetName()
 	createSQL = fmt.Sprintf("%s,\n\tPRIMARY KEY (`%s`)", createSQL, pkFieldName)
+	for _, field := range vModel.GetFields() {
+		if models.IsFullTextField(field) {
+			createSQL = fmt.Sprintf("%s,\n\tFULLTEXT INDEX `%s_fulltext` (`%s`)", createSQL, field.GetName(), field.GetName())
+		}
+	}
 
 	createSQL = fmt.Sprintf("CREATE TABLE IF NOT EXISTS `%s` (\n%s\n)\n", s.buildCodec.ConstructModelTableName(vModel), createSQL)
 	if traceSQL() {
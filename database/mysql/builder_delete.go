@@ -31,6 +31,26 @@ func (s *Builder) BuildDelete(vModel models.Model) (ret database.Result, err *cd
 	return
 }
 
+// BuildDeleteByFilter 按filter批量删除host表中的行
+func (s *Builder) BuildDeleteByFilter(vModel models.Model, vFilter models.Filter) (ret database.Result, err *cd.Error) {
+	resultStackPtr := &ResultStack{}
+	whereSQL, whereErr := s.buildFilterWhere(vModel, vFilter, resultStackPtr)
+	if whereErr != nil {
+		err = whereErr
+		slog.Error("BuildDeleteByFilter failed", "operation", "buildFilterWhere", "error", err.Error())
+		return
+	}
+
+	deleteSQL := fmt.Sprintf("DELETE FROM `%s`%s", s.buildCodec.ConstructModelTableName(vModel), whereSQL)
+	if traceSQL() {
+		slog.Info("[SQL] delete by filter", "sql", deleteSQL)
+	}
+
+	resultStackPtr.SetSQL(deleteSQL)
+	ret = resultStackPtr
+	return
+}
+
 // BuildDeleteRelation BuildDeleteRelation
 func (s *Builder) BuildDeleteRelation(vModel models.Model, vField models.Field) (delHost, delRelation database.Result, err *cd.Error) {
 	hostVal := vModel.GetPrimaryField().GetValue().Get()
@@ -102,3 +122,73 @@ func (s *Builder) BuildDeleteRelationByRights(vModel models.Model, vField models
 	ret = resultStackPtr
 	return
 }
+
+// BuildDeleteRelationByFilter 删除满足filter的host在关系表中的全部行，不删除关联实体
+//
+// filter可能包含对同一关系表的子查询，MySQL不允许DELETE的子查询直接引用目标表，这里包一层派生表
+func (s *Builder) BuildDeleteRelationByFilter(vModel models.Model, vField models.Field, vFilter models.Filter) (ret database.Result, err *cd.Error) {
+	relationTableName, relationErr := s.buildCodec.ConstructRelationTableName(vModel, vField)
+	if relationErr != nil {
+		err = relationErr
+		slog.Error("BuildDeleteRelationByFilter failed", "field", vField.GetName(), "operation", "ConstructRelationTableName", "error", err.Error())
+		return
+	}
+
+	resultStackPtr := &ResultStack{}
+	keysSQL, keysErr := s.buildFilterKeys(vModel, vFilter, resultStackPtr)
+	if keysErr != nil {
+		err = keysErr
+		slog.Error("BuildDeleteRelationByFilter failed", "field", vField.GetName(), "operation", "buildFilterKeys", "error", err.Error())
+		return
+	}
+
+	deleteSQL := fmt.Sprintf("DELETE FROM `%s` WHERE `left` IN (SELECT `%s` FROM (%s) AS `filterKeys`)",
+		relationTableName,
+		vModel.GetPrimaryField().GetName(),
+		keysSQL)
+	if traceSQL() {
+		slog.Info("[SQL] delete relation by filter", "sql", deleteSQL)
+	}
+
+	resultStackPtr.SetSQL(deleteSQL)
+	ret = resultStackPtr
+	return
+}
+
+// BuildSoftDelete 软删除，写入删除时间，已删除的行保持原删除时间
+func (s *Builder) BuildSoftDelete(vModel models.Model) (ret database.Result, err *cd.Error) {
+	deletedField := models.GetSoftDeleteField(vModel)
+	if deletedField == nil {
+		err = cd.NewError(cd.IllegalParam, fmt.Sprintf("model not declare soft delete field, model:%s", vModel.GetPkgKey()))
+		return
+	}
+
+	resultStackPtr := &ResultStack{}
+	encodeVal, encodeErr := s.encodeUpdateValue(deletedField)
+	if encodeErr != nil {
+		err = encodeErr
+		slog.Error("BuildSoftDelete failed", "operation", "encodeUpdateValue", "error", err.Error())
+		return
+	}
+	resultStackPtr.PushArgs(encodeVal)
+
+	filterStr, filterErr := s.buildFieldFilter(vModel.GetPrimaryField(), resultStackPtr)
+	if filterErr != nil {
+		err = filterErr
+		slog.Error("BuildSoftDelete failed", "operation", "buildFieldFilter", "error", err.Error())
+		return
+	}
+
+	deleteSQL := fmt.Sprintf("UPDATE `%s` SET `%s` = ? WHERE %s AND %s",
+		s.buildCodec.ConstructModelTableName(vModel),
+		deletedField.GetName(),
+		filterStr,
+		s.buildDeletedFilter(vModel, models.ExcludeDeleted))
+	if traceSQL() {
+		slog.Info("[SQL] soft delete", "sql", deleteSQL)
+	}
+
+	resultStackPtr.SetSQL(deleteSQL)
+	ret = resultStackPtr
+	return
+}
@@ -21,7 +21,8 @@ func (s *Builder) BuildUpdate(vModel models.Model) (ret database.Result, err *cd
 		slog.Error("BuildUpdate failed", "value", "s.buildFieldUpdateValues", "error", err.Error())
 		return
 	}
-	if updateStr == "" {
+	versionField := models.GetVersionField(vModel)
+	if updateStr == "" && versionField == nil {
 		err = cd.NewError(cd.IllegalParam, "no writable fields to update")
 		slog.Error("BuildUpdate failed", "value", "s.buildFieldUpdateValues", "error", err.Error())
 		return
@@ -32,6 +33,16 @@ func (s *Builder) BuildUpdate(vModel models.Model) (ret database.Result, err *cd
 		slog.Error("BuildUpdate failed", "value", "s.BuildModelFilter", "error", err.Error())
 		return
 	}
+	if versionField != nil {
+		updateStr = s.buildVersionIncrease(updateStr, versionField)
+		versionStr, versionErr := s.buildVersionFilter(versionField, resultStackPtr)
+		if versionErr != nil {
+			err = versionErr
+			slog.Error("BuildUpdate failed", "value", "s.buildVersionFilter", "error", err.Error())
+			return
+		}
+		filterStr = fmt.Sprintf("%s AND %s", filterStr, versionStr)
+	}
 
 	updateSQL := fmt.Sprintf("UPDATE `%s` SET %s WHERE %s", s.buildCodec.ConstructModelTableName(vModel), updateStr, filterStr)
 	if traceSQL() {
@@ -53,6 +64,10 @@ func (s *Builder) buildFieldUpdateValues(vModel models.Model, resultStackPtr *Re
 		if !models.IsBasicField(field) || !models.IsAssignedField(field) {
 			continue
 		}
+		// 版本字段由数据库递增，创建时间只在Insert时写入，均忽略调用方的赋值
+		if valueDeclare := field.GetSpec().GetValueDeclare(); models.IsVersionDeclare(valueDeclare) || models.IsCreatedAtDeclare(valueDeclare) {
+			continue
+		}
 		// Skip read-only fields in update
 		if spec := field.GetSpec(); spec != nil {
 			if constraints := spec.GetConstraints(); constraints != nil {
@@ -62,16 +77,11 @@ func (s *Builder) buildFieldUpdateValues(vModel models.Model, resultStackPtr *Re
 			}
 		}
 
-		fVal := field.GetValue()
-		encodeVal := any(nil)
-		if !utils.IsReallyNil(fVal.Get()) || !field.GetType().IsPtrType() {
-			var encodeErr *cd.Error
-			encodeVal, encodeErr = s.buildCodec.PackedBasicFieldValue(field, fVal)
-			if encodeErr != nil {
-				err = encodeErr
-				slog.Error("buildFieldUpdateValues failed", "field", field.GetName(), "operation", "encodeFieldValue", "error", err.Error())
-				return
-			}
+		encodeVal, encodeErr := s.encodeUpdateValue(field)
+		if encodeErr != nil {
+			err = encodeErr
+			slog.Error("buildFieldUpdateValues failed", "field", field.GetName(), "operation", "encodeFieldValue", "error", err.Error())
+			return
 		}
 
 		resultStackPtr.PushArgs(encodeVal)
@@ -85,3 +95,228 @@ func (s *Builder) buildFieldUpdateValues(vModel models.Model, resultStackPtr *Re
 	ret = str
 	return
 }
+
+// buildVersionIncrease 在SET中追加版本字段自增
+func (s *Builder) buildVersionIncrease(updateStr string, versionField models.Field) string {
+	increaseStr := fmt.Sprintf("`%s` = `%s` + 1", versionField.GetName(), versionField.GetName())
+	if updateStr == "" {
+		return increaseStr
+	}
+
+	return fmt.Sprintf("%s,%s", updateStr, increaseStr)
+}
+
+// buildVersionFilter 按调用方持有的版本值过滤，版本从1开始，零值表示调用方未提供版本
+func (s *Builder) buildVersionFilter(versionField models.Field, resultStackPtr *ResultStack) (ret string, err *cd.Error) {
+	fVal := versionField.GetValue()
+	if fVal == nil || utils.IsReallyNil(fVal.Get()) || fVal.IsZero() {
+		err = cd.NewError(cd.IllegalParam, fmt.Sprintf("update requires version value, field:%s", versionField.GetName()))
+		return
+	}
+
+	ret, err = s.buildFieldFilter(versionField, resultStackPtr)
+	return
+}
+
+// encodeUpdateValue 编码待更新的字段值，指针类型字段值为nil时写入NULL
+func (s *Builder) encodeUpdateValue(field models.Field) (ret any, err *cd.Error) {
+	fVal := field.GetValue()
+	if utils.IsReallyNil(fVal.Get()) && field.GetType().IsPtrType() {
+		return
+	}
+
+	ret, err = s.buildCodec.PackedBasicFieldValue(field, fVal)
+	return
+}
+
+// BuildUpdateByFilter 按filter批量更新，SET fieldNames指定的基础字段，字段值取自vModel
+func (s *Builder) BuildUpdateByFilter(vModel models.Model, vFilter models.Filter, fieldNames []string) (ret database.Result, err *cd.Error) {
+	if len(fieldNames) == 0 {
+		err = cd.NewError(cd.IllegalParam, "no fields to update")
+		return
+	}
+
+	resultStackPtr := &ResultStack{}
+	updateStr := ""
+	for _, fieldName := range fieldNames {
+		field := vModel.GetField(fieldName)
+		if field == nil || models.IsPrimaryField(field) || !models.IsBasicField(field) || models.IsVersionDeclare(field.GetSpec().GetValueDeclare()) {
+			err = cd.NewError(cd.IllegalParam, fmt.Sprintf("illegal update field, field:%s", fieldName))
+			slog.Error("BuildUpdateByFilter failed", "field", fieldName, "error", err.Error())
+			return
+		}
+
+		encodeVal, encodeErr := s.encodeUpdateValue(field)
+		if encodeErr != nil {
+			err = encodeErr
+			slog.Error("BuildUpdateByFilter failed", "field", fieldName, "operation", "encodeFieldValue", "error", err.Error())
+			return
+		}
+
+		resultStackPtr.PushArgs(encodeVal)
+		if updateStr == "" {
+			updateStr = fmt.Sprintf("`%s` = ?", fieldName)
+		} else {
+			updateStr = fmt.Sprintf("%s,`%s` = ?", updateStr, fieldName)
+		}
+	}
+
+	if versionField := models.GetVersionField(vModel); versionField != nil {
+		updateStr = s.buildVersionIncrease(updateStr, versionField)
+	}
+
+	whereSQL, whereErr := s.buildFilterWhere(vModel, vFilter, resultStackPtr)
+	if whereErr != nil {
+		err = whereErr
+		slog.Error("BuildUpdateByFilter failed", "operation", "s.buildFilterWhere", "error", err.Error())
+		return
+	}
+
+	updateSQL := fmt.Sprintf("UPDATE `%s` SET %s%s", s.buildCodec.ConstructModelTableName(vModel), updateStr, whereSQL)
+	if traceSQL() {
+		slog.Info("[SQL] update by filter", "sql", updateSQL)
+	}
+
+	resultStackPtr.SetSQL(updateSQL)
+	ret = resultStackPtr
+	return
+}
+
+// BuildRestore 恢复已软删除的行
+func (s *Builder) BuildRestore(vModel models.Model) (ret database.Result, err *cd.Error) {
+	deletedField := models.GetSoftDeleteField(vModel)
+	if deletedField == nil {
+		err = cd.NewError(cd.IllegalParam, fmt.Sprintf("model not declare soft delete field, model:%s", vModel.GetPkgKey()))
+		return
+	}
+
+	resultStackPtr := &ResultStack{}
+	filterStr, filterErr := s.buildFieldFilter(vModel.GetPrimaryField(), resultStackPtr)
+	if filterErr != nil {
+		err = filterErr
+		slog.Error("BuildRestore failed", "operation", "buildFieldFilter", "error", err.Error())
+		return
+	}
+
+	restoreSQL := fmt.Sprintf("UPDATE `%s` SET `%s` = NULL WHERE %s AND %s",
+		s.buildCodec.ConstructModelTableName(vModel),
+		deletedField.GetName(),
+		filterStr,
+		s.buildDeletedFilter(vModel, models.OnlyDeleted))
+	if traceSQL() {
+		slog.Info("[SQL] restore", "sql", restoreSQL)
+	}
+
+	resultStackPtr.SetSQL(restoreSQL)
+	ret = resultStackPtr
+	return
+}
+
+// BuildUpdateByExpr 按filter批量执行原子表达式更新，SET `f` = `f` <opr> value，guards 作为附加条件与filter以AND组合
+//
+// vModel 中已赋值的更新时间字段同时写入，声明了版本字段时版本同时递增。
+func (s *Builder) BuildUpdateByExpr(vModel models.Model, vFilter models.Filter, exprs []models.UpdateExpr, guards []models.UpdateGuard) (ret database.Result, err *cd.Error) {
+	if len(exprs) == 0 {
+		err = cd.NewError(cd.IllegalParam, "no update expressions")
+		return
+	}
+
+	resultStackPtr := &ResultStack{}
+	updateStr := ""
+	for _, expr := range exprs {
+		field, exprVal, exprErr := s.encodeExprValue(vModel, expr.Field, expr.Value)
+		if exprErr != nil {
+			err = exprErr
+			slog.Error("BuildUpdateByExpr failed", "field", expr.Field, "operation", "s.encodeExprValue", "error", err.Error())
+			return
+		}
+		valueType := field.GetType().GetValue()
+		if !(valueType.IsNumberValueType() || valueType.IsFloatValueType()) || field.GetType().IsPtrType() || models.IsVersionDeclare(field.GetSpec().GetValueDeclare()) || expr.Opr.Operator() == "" {
+			err = cd.NewError(cd.IllegalParam, fmt.Sprintf("illegal update expression, field:%s", expr.Field))
+			slog.Error("BuildUpdateByExpr failed", "field", expr.Field, "error", err.Error())
+			return
+		}
+
+		resultStackPtr.PushArgs(exprVal)
+		exprStr := fmt.Sprintf("`%s` = `%s` %s ?", expr.Field, expr.Field, expr.Opr.Operator())
+		if updateStr == "" {
+			updateStr = exprStr
+		} else {
+			updateStr = fmt.Sprintf("%s,%s", updateStr, exprStr)
+		}
+	}
+
+	if updatedField := models.GetUpdatedAtField(vModel); updatedField != nil && models.IsAssignedField(updatedField) {
+		encodeVal, encodeErr := s.encodeUpdateValue(updatedField)
+		if encodeErr != nil {
+			err = encodeErr
+			slog.Error("BuildUpdateByExpr failed", "field", updatedField.GetName(), "operation", "encodeFieldValue", "error", err.Error())
+			return
+		}
+
+		resultStackPtr.PushArgs(encodeVal)
+		updateStr = fmt.Sprintf("%s,`%s` = ?", updateStr, updatedField.GetName())
+	}
+	if versionField := models.GetVersionField(vModel); versionField != nil {
+		updateStr = s.buildVersionIncrease(updateStr, versionField)
+	}
+
+	whereSQL, whereErr := s.buildFilterWhere(vModel, vFilter, resultStackPtr)
+	if whereErr != nil {
+		err = whereErr
+		slog.Error("BuildUpdateByExpr failed", "operation", "s.buildFilterWhere", "error", err.Error())
+		return
+	}
+	for _, guard := range guards {
+		_, guardVal, guardErr := s.encodeExprValue(vModel, guard.Field, guard.Value)
+		if guardErr != nil {
+			err = guardErr
+			slog.Error("BuildUpdateByExpr failed", "field", guard.Field, "operation", "s.encodeExprValue", "error", err.Error())
+			return
+		}
+		if guard.Opr.Operator() == "" {
+			err = cd.NewError(cd.IllegalParam, fmt.Sprintf("illegal update guard, field:%s", guard.Field))
+			return
+		}
+
+		resultStackPtr.PushArgs(guardVal)
+		guardStr := fmt.Sprintf("`%s` %s ?", guard.Field, guard.Opr.Operator())
+		if whereSQL == "" {
+			whereSQL = fmt.Sprintf(" WHERE %s", guardStr)
+		} else {
+			whereSQL = fmt.Sprintf("%s AND %s", whereSQL, guardStr)
+		}
+	}
+
+	updateSQL := fmt.Sprintf("UPDATE `%s` SET %s%s", s.buildCodec.ConstructModelTableName(vModel), updateStr, whereSQL)
+	if traceSQL() {
+		slog.Info("[SQL] update by expression", "sql", updateSQL)
+	}
+
+	resultStackPtr.SetSQL(updateSQL)
+	ret = resultStackPtr
+	return
+}
+
+// encodeExprValue 按字段类型转换并编码表达式或条件中的值，字段必须是非主键的基础字段
+func (s *Builder) encodeExprValue(vModel models.Model, fieldName string, value any) (field models.Field, ret any, err *cd.Error) {
+	field = vModel.GetField(fieldName)
+	if field == nil || models.IsPrimaryField(field) || !models.IsBasicField(field) {
+		err = cd.NewError(cd.IllegalParam, fmt.Sprintf("illegal expression field, field:%s", fieldName))
+		return
+	}
+
+	if err = models.CheckIntegerValue(field.GetType().GetValue(), value); err != nil {
+		err = cd.NewError(cd.IllegalParam, fmt.Sprintf("illegal expression value, field:%s, error:%s", fieldName, err.Error()))
+		return
+	}
+
+	fieldVal, valErr := field.GetType().Interface(value)
+	if valErr != nil {
+		err = cd.NewError(cd.IllegalParam, fmt.Sprintf("illegal expression value, field:%s, error:%s", fieldName, valErr.Error()))
+		return
+	}
+
+	ret, err = s.buildCodec.PackedBasicFieldValue(field, fieldVal)
+	return
+}
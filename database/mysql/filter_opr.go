@@ -25,6 +25,8 @@ func getOprFunc(filterItem models.FilterItem) (ret OprFunc) {
 		return NotInOpr
 	case models.LikeOpr:
 		return LikeOpr
+	case models.MatchOpr:
+		return MatchOpr
 	}
 
 	return nil
@@ -100,6 +102,18 @@ func LikeOpr(name string, val any, resultStackPtr *ResultStack) string {
 	return fmt.Sprintf("`%s` LIKE ?", name)
 }
 
+// MatchOpr 全文检索，自然语言模式
+func MatchOpr(name string, val any, resultStackPtr *ResultStack) string {
+	resultStackPtr.PushArgs(val)
+	return fmt.Sprintf("MATCH(`%s`) AGAINST(?)", name)
+}
+
+// RelevanceOpr 全文检索的相关度
+func RelevanceOpr(name string, val any, resultStackPtr *ResultStack) string {
+	resultStackPtr.PushArgs(val)
+	return fmt.Sprintf("MATCH(`%s`) AGAINST(?)", name)
+}
+
 // SortOpr sort opr
 func SortOpr(name string, ascSort bool) string {
 	if ascSort {
@@ -0,0 +1,116 @@
+package database
+
+import (
+	"sync"
+	"time"
+
+	cd "github.com/muidea/magicCommon/def"
+)
+
+// HealthStatus 连接池健康状态
+type HealthStatus struct {
+	// Healthy Ping成功且未处于排空状态
+	Healthy bool
+	// Draining 连接池正在排空或已排空，不再分配Executor
+	Draining bool
+	// InUseExecutors 已借出尚未Release的Executor数量
+	InUseExecutors int
+	// Latency Ping耗时
+	Latency time.Duration
+	// Stats 连接池统计
+	Stats PoolStats
+	// Error Ping失败时的错误信息
+	Error string
+}
+
+// ExecutorTracker 记录连接池借出的Executor，用于排空连接池
+type ExecutorTracker struct {
+	mu       sync.Mutex
+	draining bool
+	inUse    int
+	idleCh   chan struct{}
+}
+
+// NewExecutorTracker new executor tracker
+func NewExecutorTracker() *ExecutorTracker {
+	return &ExecutorTracker{}
+}
+
+// Acquire 借出Executor前调用，排空中返回cd.ServiceUnavailable
+func (s *ExecutorTracker) Acquire() *cd.Error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.draining {
+		return cd.NewError(cd.ServiceUnavailable, "database pool is draining")
+	}
+
+	s.inUse++
+	return nil
+}
+
+// Done Executor Release时调用，与Acquire成对出现
+func (s *ExecutorTracker) Done() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.inUse > 0 {
+		s.inUse--
+	}
+	s.notifyIdle()
+}
+
+// Drain 停止分配Executor并等待已借出的Executor全部Release，超时返回cd.Timeout，timeout<=0表示一直等待
+func (s *ExecutorTracker) Drain(timeout time.Duration) *cd.Error {
+	s.mu.Lock()
+	s.draining = true
+	if s.idleCh == nil {
+		s.idleCh = make(chan struct{})
+		s.notifyIdle()
+	}
+	idleCh := s.idleCh
+	s.mu.Unlock()
+
+	if timeout <= 0 {
+		<-idleCh
+		return nil
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-idleCh:
+		return nil
+	case <-timer.C:
+		return cd.NewError(cd.Timeout, "drain database pool timeout, executors still in use")
+	}
+}
+
+// notifyIdle 排空中且没有借出的Executor时关闭idleCh，调用方需持有锁
+func (s *ExecutorTracker) notifyIdle() {
+	if !s.draining || s.inUse > 0 || s.idleCh == nil {
+		return
+	}
+
+	select {
+	case <-s.idleCh:
+	default:
+		close(s.idleCh)
+	}
+}
+
+// InUse 已借出尚未Release的Executor数量
+func (s *ExecutorTracker) InUse() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.inUse
+}
+
+// Draining 是否处于排空状态
+func (s *ExecutorTracker) Draining() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.draining
+}
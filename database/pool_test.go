@@ -0,0 +1,43 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	cd "github.com/muidea/magicCommon/def"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecutorTrackerDrain(t *testing.T) {
+	tracker := NewExecutorTracker()
+	assert.Nil(t, tracker.Acquire())
+	assert.Nil(t, tracker.Acquire())
+	assert.Equal(t, 2, tracker.InUse())
+
+	err := tracker.Drain(20 * time.Millisecond)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, cd.Code(cd.Timeout), err.Code)
+	}
+	assert.True(t, tracker.Draining())
+
+	err = tracker.Acquire()
+	if assert.NotNil(t, err) {
+		assert.Equal(t, cd.Code(cd.ServiceUnavailable), err.Code)
+	}
+
+	tracker.Done()
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		tracker.Done()
+	}()
+	assert.Nil(t, tracker.Drain(time.Second))
+	assert.Equal(t, 0, tracker.InUse())
+
+	// 已排空后再次调用立即返回
+	assert.Nil(t, tracker.Drain(0))
+}
+
+func TestExecutorTrackerDrainIdle(t *testing.T) {
+	tracker := NewExecutorTracker()
+	assert.Nil(t, tracker.Drain(time.Millisecond))
+}
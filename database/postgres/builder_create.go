@@ -37,7 +37,17 @@ func (s *Builder) BuildCreateTable(vModel models.Model) (ret database.Result, er
 	pkFieldName := vModel.GetPrimaryField().GetName()
 	createSQL = fmt.Sprintf("%s,\n\tPRIMARY KEY (\"%s\")", createSQL, pkFieldName)
 
-	createSQL = fmt.Sprintf("CREATE TABLE IF NOT EXISTS \"%s\" (\n%s\n)\n", s.buildCodec.ConstructModelTableName(vModel), createSQL)
+	tableName := s.buildCodec.ConstructModelTableName(vModel)
+	createSQL = fmt.Sprintf("CREATE TABLE IF NOT EXISTS \"%s\" (\n%s\n)", tableName, createSQL)
+	// 全文检索字段建立GIN表达式索引，表达式与MatchOpr一致
+	for _, field := range vModel.GetFields() {
+		if !models.IsFullTextField(field) {
+			continue
+		}
+
+		createSQL = fmt.Sprintf("%s;\nCREATE INDEX IF NOT EXISTS \"%s_%s_fulltext\" ON \"%s\" USING GIN (to_tsvector('%s', \"%s\"))", createSQL, tableName, field.GetName(), tableName, fullTextConfig, field.GetName())
+	}
+	createSQL = fmt.Sprintf("%s\n", createSQL)
 	if traceSQL() {
 		slog.Info("[SQL] create", "sql", createSQL)
 	}
@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"fmt"
+	"strings"
 
 	cd "github.com/muidea/magicCommon/def"
 
@@ -86,3 +87,127 @@ func (s *Builder) BuildInsertRelation(vModel models.Model, vField models.Field,
 	ret = resultStackPtr
 	return
 }
+
+// BuildBatchInsert Build multi-row insert, 所有模型必须是同一类型
+//
+// 列为各模型有效基础字段的并集，某行未赋值的列使用DEFAULT，返回的主键按VALUES顺序排列
+func (s *Builder) BuildBatchInsert(vModels []models.Model) (ret database.Result, err *cd.Error) {
+	if len(vModels) == 0 {
+		err = cd.NewError(cd.IllegalParam, "illegal batch insert models")
+		return
+	}
+
+	columns, pkName, columnErr := batchInsertColumns(vModels)
+	if columnErr != nil {
+		err = columnErr
+		slog.Error("BuildBatchInsert failed", "operation", "batchInsertColumns", "error", err.Error())
+		return
+	}
+
+	resultStackPtr := &ResultStack{}
+	fieldNames := make([]string, 0, len(columns))
+	for _, column := range columns {
+		fieldNames = append(fieldNames, fmt.Sprintf("\"%s\"", column))
+	}
+
+	rowValues := make([]string, 0, len(vModels))
+	for _, vModel := range vModels {
+		fieldValues := make([]string, 0, len(columns))
+		for _, column := range columns {
+			field := vModel.GetField(column)
+			if !models.IsValidField(field) {
+				fieldValues = append(fieldValues, "DEFAULT")
+				continue
+			}
+
+			encodeVal, encodeErr := s.buildCodec.PackedBasicFieldValue(field, field.GetValue())
+			if encodeErr != nil {
+				err = encodeErr
+				slog.Error("BuildBatchInsert failed", "field", field.GetName(), "operation", "encodeFieldValue", "error", err.Error())
+				return
+			}
+
+			resultStackPtr.PushArgs(encodeVal)
+			fieldValues = append(fieldValues, fmt.Sprintf("$%d", len(resultStackPtr.argsVal)))
+		}
+		rowValues = append(rowValues, fmt.Sprintf("(%s)", strings.Join(fieldValues, ",")))
+	}
+
+	insertSQL := fmt.Sprintf("INSERT INTO \"%s\" (%s) VALUES %s RETURNING \"%s\"", s.buildCodec.ConstructModelTableName(vModels[0]), strings.Join(fieldNames, ","), strings.Join(rowValues, ","), pkName)
+	if traceSQL() {
+		slog.Info("[SQL] batch insert", "sql", insertSQL, "rows", len(vModels))
+	}
+
+	resultStackPtr.SetSQL(insertSQL)
+	ret = resultStackPtr
+	return
+}
+
+// batchInsertColumns 批量插入的列，按模型字段顺序取各模型有效基础字段(不含自增字段)的并集
+func batchInsertColumns(vModels []models.Model) (ret []string, pkName string, err *cd.Error) {
+	pkgKey := vModels[0].GetPkgKey()
+	columnFlags := map[string]bool{}
+	for _, vModel := range vModels {
+		if vModel.GetPkgKey() != pkgKey {
+			err = cd.NewError(cd.IllegalParam, fmt.Sprintf("batch insert models must be the same type, %s != %s", vModel.GetPkgKey(), pkgKey))
+			return
+		}
+
+		for _, field := range vModel.GetFields() {
+			if !models.IsBasicField(field) || !models.IsValidField(field) {
+				continue
+			}
+			if field.GetSpec().GetValueDeclare() == models.AutoIncrement {
+				continue
+			}
+
+			columnFlags[field.GetName()] = true
+		}
+	}
+
+	for _, field := range vModels[0].GetFields() {
+		if models.IsPrimaryField(field) {
+			pkName = field.GetName()
+		}
+		if columnFlags[field.GetName()] {
+			ret = append(ret, field.GetName())
+		}
+	}
+
+	if len(ret) == 0 {
+		err = cd.NewError(cd.IllegalParam, "batch insert models have no insertable field")
+	}
+	return
+}
+
+// BuildBatchInsertRelation Build multi-row insert relation, leftVals与rightVals一一对应
+func (s *Builder) BuildBatchInsertRelation(vModel models.Model, vField models.Field, leftVals, rightVals []any) (ret database.Result, err *cd.Error) {
+	if len(leftVals) == 0 || len(leftVals) != len(rightVals) {
+		err = cd.NewError(cd.IllegalParam, "illegal batch insert relation values")
+		return
+	}
+
+	relationTableName, relationErr := s.buildCodec.ConstructRelationTableName(vModel, vField)
+	if relationErr != nil {
+		err = relationErr
+		slog.Error("BuildBatchInsertRelation failed", "field", vField.GetName(), "operation", "s.buildCodec.ConstructRelationTableName", "error", err.Error())
+		return
+	}
+
+	resultStackPtr := &ResultStack{}
+	rowValues := make([]string, 0, len(leftVals))
+	for idx := range leftVals {
+		resultStackPtr.PushArgs(leftVals[idx], rightVals[idx])
+		argsLen := len(resultStackPtr.argsVal)
+		rowValues = append(rowValues, fmt.Sprintf("($%d,$%d)", argsLen-1, argsLen))
+	}
+
+	insertRelationSQL := fmt.Sprintf("INSERT INTO \"%s\" (\"left\", \"right\") VALUES %s", relationTableName, strings.Join(rowValues, ","))
+	if traceSQL() {
+		slog.Info("[SQL] batch insert relation", "sql", insertRelationSQL, "rows", len(leftVals))
+	}
+
+	resultStackPtr.SetSQL(insertRelationSQL)
+	ret = resultStackPtr
+	return
+}
@@ -13,14 +13,33 @@ import (
 
 // BuildQuery build query sql
 func (s *Builder) BuildQuery(vModel models.Model, filter models.Filter) (ret database.Result, err *cd.Error) {
-	namesVal, nameErr := s.getFieldQueryNames(vModel)
+	ret, err = s.buildQuery(vModel, filter, &ResultStack{}, "")
+	return
+}
+
+// BuildQueryRelationModels 查询vModel的vField关系中满足filter的关联对象，rModel为关联对象的查询模型，filter的排序与分页同样生效
+func (s *Builder) BuildQueryRelationModels(vModel models.Model, vField models.Field, rModel models.Model, filter models.Filter) (ret database.Result, err *cd.Error) {
+	resultStackPtr := &ResultStack{}
+	ownerSQL, ownerErr := s.buildRelationOwnerFilter(vModel, vField, rModel, resultStackPtr)
+	if ownerErr != nil {
+		err = ownerErr
+		slog.Error("BuildQueryRelationModels failed", "field", vField.GetName(), "operation", "s.buildRelationOwnerFilter", "error", err.Error())
+		return
+	}
+
+	ret, err = s.buildQuery(rModel, filter, resultStackPtr, ownerSQL)
+	return
+}
+
+// buildQuery ownerSQL非空时作为首个查询条件，其参数需已压入resultStackPtr
+func (s *Builder) buildQuery(vModel models.Model, filter models.Filter, resultStackPtr *ResultStack, ownerSQL string) (ret database.Result, err *cd.Error) {
+	namesVal, nameErr := s.getFieldQueryNames(vModel, filter)
 	if nameErr != nil {
 		err = nameErr
 		slog.Error("BuildQuery failed", "operation", "s.getFieldQueryNames", "error", err.Error())
 		return
 	}
 
-	resultStackPtr := &ResultStack{}
 	querySQL := fmt.Sprintf("SELECT %s FROM \"%s\"", namesVal, s.buildCodec.ConstructModelTableName(vModel))
 	if filter != nil {
 		filterSQL, filterErr := s.buildFilter(vModel, filter, resultStackPtr)
@@ -30,11 +49,12 @@ func (s *Builder) BuildQuery(vModel models.Model, filter models.Filter) (ret dat
 			return
 		}
 
+		filterSQL = joinFilterSQL(ownerSQL, filterSQL)
 		if filterSQL != "" {
 			querySQL = fmt.Sprintf("%s WHERE %s", querySQL, filterSQL)
 		}
 
-		sortVal, sortErr := s.buildSorter(vModel, filter.Sorter())
+		sortVal, sortErr := s.buildSorter(vModel, filter, resultStackPtr)
 		if sortErr != nil {
 			err = sortErr
 			slog.Error("BuildQuery failed", "operation", "s.buildSorter", "error", err.Error())
@@ -50,6 +70,8 @@ func (s *Builder) BuildQuery(vModel models.Model, filter models.Filter) (ret dat
 			resultStackPtr.PushArgs(paginationer.Limit(), paginationer.Offset())
 			querySQL = fmt.Sprintf("%s LIMIT $%d OFFSET $%d", querySQL, len(resultStackPtr.argsVal)-1, len(resultStackPtr.argsVal))
 		}
+	} else if deletedSQL := joinFilterSQL(ownerSQL, s.buildDeletedFilter(vModel, models.ExcludeDeleted)); deletedSQL != "" {
+		querySQL = fmt.Sprintf("%s WHERE %s", querySQL, deletedSQL)
 	}
 	if traceSQL() {
 		slog.Info("[SQL] query", "sql", querySQL)
@@ -82,6 +104,46 @@ func (s *Builder) BuildQueryRelation(vModel models.Model, vField models.Field) (
 	return
 }
 
+// buildRelationOwnerFilter 关联对象属于vModel的vField关系的条件
+func (s *Builder) buildRelationOwnerFilter(vModel models.Model, vField models.Field, rModel models.Model, resultStackPtr *ResultStack) (ret string, err *cd.Error) {
+	relationTableName, relationErr := s.buildCodec.ConstructRelationTableName(vModel, vField)
+	if relationErr != nil {
+		err = relationErr
+		return
+	}
+
+	resultStackPtr.PushArgs(vModel.GetPrimaryField().GetValue().Get())
+	ret = fmt.Sprintf("\"%s\" IN (SELECT \"right\" FROM \"%s\" WHERE \"left\"= $%d)", rModel.GetPrimaryField().GetName(), relationTableName, len(resultStackPtr.argsVal))
+	return
+}
+
+// BuildQueryRelationByFilter 查询满足filter的host关联的全部right值
+func (s *Builder) BuildQueryRelationByFilter(vModel models.Model, vField models.Field, vFilter models.Filter) (ret database.Result, err *cd.Error) {
+	relationTableName, relationErr := s.buildCodec.ConstructRelationTableName(vModel, vField)
+	if relationErr != nil {
+		err = relationErr
+		slog.Error("BuildQueryRelationByFilter failed", "field", vField.GetName(), "operation", "s.buildCodec.ConstructRelationTableName", "error", err.Error())
+		return
+	}
+
+	resultStackPtr := &ResultStack{}
+	keysSQL, keysErr := s.buildFilterKeys(vModel, vFilter, resultStackPtr)
+	if keysErr != nil {
+		err = keysErr
+		slog.Error("BuildQueryRelationByFilter failed", "field", vField.GetName(), "operation", "s.buildFilterKeys", "error", err.Error())
+		return
+	}
+
+	queryRelationSQL := fmt.Sprintf("SELECT \"right\" FROM \"%s\" WHERE \"left\" IN (%s)", relationTableName, keysSQL)
+	if traceSQL() {
+		slog.Info("[SQL] query relation by filter", "sql", queryRelationSQL)
+	}
+
+	resultStackPtr.SetSQL(queryRelationSQL)
+	ret = resultStackPtr
+	return
+}
+
 func (s *Builder) BuildBatchQueryRelation(vModel models.Model, vField models.Field, leftIDs []any) (ret database.Result, err *cd.Error) {
 	if len(leftIDs) == 0 {
 		err = cd.NewError(cd.IllegalParam, "leftIDs is empty")
@@ -129,9 +191,18 @@ func (s *Builder) BuildBatchQueryRelation(vModel models.Model, vField models.Fie
 	return
 }
 
-func (s *Builder) getFieldQueryNames(vModel models.Model) (ret string, err *cd.Error) {
+// getFieldQueryNames 生成查询列，filter通过Select指定字段时只查询指定的列
+func (s *Builder) getFieldQueryNames(vModel models.Model, filter models.Filter) (ret string, err *cd.Error) {
+	var selectFields []string
+	if filter != nil {
+		selectFields = filter.GetSelectFields()
+	}
+
 	str := ""
 	for _, field := range vModel.GetFields() {
+		if !models.IsSelectedField(selectFields, field) {
+			continue
+		}
 		// 检查 wo 约束，这些字段在查询时应该被排除
 		fSpec := field.GetSpec()
 		constraints := fSpec.GetConstraints()
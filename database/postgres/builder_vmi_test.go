@@ -8,6 +8,7 @@ import (
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	cd "github.com/muidea/magicCommon/def"
 	"github.com/muidea/magicOrm/database/codec"
@@ -527,3 +528,618 @@ func TestBuilderVMIUpdateUsesAssignedBasicFieldsOnly(t *testing.T) {
 		t.Fatalf("unexpected partial update args: got=%#v want=%#v", updateResult.Args(), wantUpdateArgs)
 	}
 }
+
+func TestBuilderVMIBatchInsert(t *testing.T) {
+	_, builder, productModel := buildVMIProductValueModel(t)
+	secondModel := productModel.Copy(models.OriginView)
+
+	batchResult, err := builder.BuildBatchInsert([]models.Model{productModel, secondModel})
+	if err != nil {
+		t.Fatalf("BuildBatchInsert(product) failed: %v", err)
+	}
+	wantSQL := `INSERT INTO "tenant_Product" ("name","description","image","expire","tags","creater","createTime","modifyTime","namespace") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9),($10,$11,$12,$13,$14,$15,$16,$17,$18) RETURNING "id"`
+	if batchResult.SQL() != wantSQL {
+		t.Fatalf("unexpected batch insert sql: %s", batchResult.SQL())
+	}
+	if len(batchResult.Args()) != 18 {
+		t.Fatalf("unexpected batch insert args: %#v", batchResult.Args())
+	}
+
+	if _, err = builder.BuildBatchInsert(nil); err == nil {
+		t.Fatal("expected empty batch insert to fail")
+	}
+
+	relationResult, err := builder.BuildBatchInsertRelation(productModel, productModel.GetField("status"), []any{int64(1), int64(2)}, []any{int64(9), int64(10)})
+	if err != nil {
+		t.Fatalf("BuildBatchInsertRelation(status) failed: %v", err)
+	}
+	if relationResult.SQL() != `INSERT INTO "tenant_ProductStatus3Status" ("left", "right") VALUES ($1,$2),($3,$4)` {
+		t.Fatalf("unexpected batch insert relation sql: %s", relationResult.SQL())
+	}
+	if !reflect.DeepEqual(relationResult.Args(), []any{int64(1), int64(9), int64(2), int64(10)}) {
+		t.Fatalf("unexpected batch insert relation args: %#v", relationResult.Args())
+	}
+	if _, err = builder.BuildBatchInsertRelation(productModel, productModel.GetField("status"), []any{int64(1)}, nil); err == nil {
+		t.Fatal("expected mismatched relation values to fail")
+	}
+}
+
+func TestBuilderVMIUpdateAndDeleteByFilter(t *testing.T) {
+	_, builder, orderModel, filter, _, statusValue := buildVMIOrderFilter(t)
+	if err := filter.Equal("sn", "SO-1"); err != nil {
+		t.Fatalf("filter.Equal(sn) failed: %v", err)
+	}
+	if err := filter.Equal("status", statusValue); err != nil {
+		t.Fatalf("filter.Equal(status) failed: %v", err)
+	}
+	if err := orderModel.SetFieldValue("memo", "archived"); err != nil {
+		t.Fatalf("SetFieldValue(memo) failed: %v", err)
+	}
+
+	updateResult, err := builder.BuildUpdateByFilter(orderModel, filter, []string{"memo"})
+	if err != nil {
+		t.Fatalf("BuildUpdateByFilter failed: %v", err)
+	}
+	if updateResult.SQL() != `UPDATE "tenant_Order" SET "memo" = $1 WHERE "sn" = $2 AND "id" IN (SELECT DISTINCT("left") "id"  FROM "tenant_OrderStatus3Status" WHERE "right" = $3)` {
+		t.Fatalf("unexpected update by filter sql: %s", updateResult.SQL())
+	}
+	if !reflect.DeepEqual(updateResult.Args(), []any{"archived", "SO-1", int64(9)}) {
+		t.Fatalf("unexpected update by filter args: %#v", updateResult.Args())
+	}
+	if _, err = builder.BuildUpdateByFilter(orderModel, filter, []string{"goods"}); err == nil {
+		t.Fatal("expected relation field update by filter to fail")
+	}
+
+	deleteResult, err := builder.BuildDeleteByFilter(orderModel, filter)
+	if err != nil {
+		t.Fatalf("BuildDeleteByFilter failed: %v", err)
+	}
+	if deleteResult.SQL() != `DELETE FROM "tenant_Order" WHERE "sn" = $1 AND "id" IN (SELECT DISTINCT("left") "id"  FROM "tenant_OrderStatus3Status" WHERE "right" = $2)` {
+		t.Fatalf("unexpected delete by filter sql: %s", deleteResult.SQL())
+	}
+
+	relationResult, err := builder.BuildDeleteRelationByFilter(orderModel, orderModel.GetField("goods"), filter)
+	if err != nil {
+		t.Fatalf("BuildDeleteRelationByFilter(goods) failed: %v", err)
+	}
+	if !strings.HasPrefix(relationResult.SQL(), `DELETE FROM "tenant_OrderGoods2GoodsItem" WHERE "left" IN (SELECT "id" FROM "tenant_Order" WHERE "sn" = $1 AND `) {
+		t.Fatalf("unexpected delete relation by filter sql: %s", relationResult.SQL())
+	}
+
+	queryResult, err := builder.BuildQueryRelationByFilter(orderModel, orderModel.GetField("goods"), nil)
+	if err != nil {
+		t.Fatalf("BuildQueryRelationByFilter(goods) failed: %v", err)
+	}
+	if queryResult.SQL() != `SELECT "right" FROM "tenant_OrderGoods2GoodsItem" WHERE "left" IN (SELECT "id" FROM "tenant_Order")` {
+		t.Fatalf("unexpected query relation by filter sql: %s", queryResult.SQL())
+	}
+}
+
+func buildSoftDeleteNoteModel(t *testing.T) (provider.Provider, *Builder, models.Model) {
+	t.Helper()
+
+	noteObject := &remote.Object{
+		Name:    "note",
+		PkgPath: "/soft",
+		Fields: []*remote.Field{
+			{
+				Name: "id",
+				Type: &remote.TypeImpl{Name: "int64", Value: models.TypeBigIntegerValue},
+				Spec: &remote.SpecImpl{FieldName: "id", PrimaryKey: true, ViewDeclare: []models.ViewDeclare{models.DetailView}},
+			},
+			{
+				Name: "title",
+				Type: &remote.TypeImpl{Name: "string", Value: models.TypeStringValue},
+				Spec: &remote.SpecImpl{FieldName: "title", ViewDeclare: []models.ViewDeclare{models.DetailView}},
+			},
+			{
+				Name: "deletedAt",
+				Type: &remote.TypeImpl{Name: "time.Time", Value: models.TypeDateTimeValue, IsPtr: true},
+				Spec: &remote.SpecImpl{FieldName: "deletedAt", ValueDeclare: models.SoftDelete, ViewDeclare: []models.ViewDeclare{models.DetailView}},
+			},
+		},
+	}
+
+	remoteProvider := provider.NewRemoteProvider("tenant", nil)
+	if _, err := remoteProvider.RegisterModel(noteObject); err != nil {
+		t.Fatalf("RegisterModel(note) failed: %v", err)
+	}
+	noteModel, err := remoteProvider.GetEntityModel(&remote.ObjectValue{
+		Name:    "note",
+		PkgPath: "/soft",
+		Fields:  []*remote.FieldValue{{Name: "id", Value: int64(7)}},
+	}, true)
+	if err != nil {
+		t.Fatalf("GetEntityModel(note) failed: %v", err)
+	}
+
+	return remoteProvider, NewBuilder(remoteProvider, codec.New(remoteProvider, "tenant")), noteModel
+}
+
+func TestBuilderSoftDelete(t *testing.T) {
+	remoteProvider, builder, noteModel := buildSoftDeleteNoteModel(t)
+
+	countResult, err := builder.BuildCount(noteModel, nil)
+	if err != nil {
+		t.Fatalf("BuildCount failed: %v", err)
+	}
+	if countResult.SQL() != `SELECT COUNT(*) FROM "tenant_Note" WHERE "deletedAt" IS NULL` {
+		t.Fatalf("unexpected default count sql: %s", countResult.SQL())
+	}
+
+	scopeCases := []struct {
+		scope    models.DeletedScope
+		expected string
+	}{
+		{models.ExcludeDeleted, `SELECT COUNT(*) FROM "tenant_Note" WHERE "title" = $1 AND "deletedAt" IS NULL`},
+		{models.OnlyDeleted, `SELECT COUNT(*) FROM "tenant_Note" WHERE "title" = $1 AND "deletedAt" IS NOT NULL`},
+		{models.IncludeDeleted, `SELECT COUNT(*) FROM "tenant_Note" WHERE "title" = $1`},
+	}
+	for _, tc := range scopeCases {
+		filter, filterErr := remoteProvider.GetModelFilter(noteModel)
+		if filterErr != nil {
+			t.Fatalf("GetModelFilter failed: %v", filterErr)
+		}
+		if filterErr = filter.Equal("title", "memo"); filterErr != nil {
+			t.Fatalf("filter.Equal(title) failed: %v", filterErr)
+		}
+		filter.Deleted(tc.scope)
+
+		countResult, err = builder.BuildCount(noteModel, filter)
+		if err != nil {
+			t.Fatalf("BuildCount(scope %d) failed: %v", tc.scope, err)
+		}
+		if countResult.SQL() != tc.expected {
+			t.Fatalf("unexpected count sql for scope %d: %s", tc.scope, countResult.SQL())
+		}
+	}
+
+	deletedAt := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	if err = noteModel.SetFieldValue("deletedAt", deletedAt); err != nil {
+		t.Fatalf("SetFieldValue(deletedAt) failed: %v", err)
+	}
+	deleteResult, err := builder.BuildSoftDelete(noteModel)
+	if err != nil {
+		t.Fatalf("BuildSoftDelete failed: %v", err)
+	}
+	if deleteResult.SQL() != `UPDATE "tenant_Note" SET "deletedAt" = $1 WHERE "id" = $2 AND "deletedAt" IS NULL` {
+		t.Fatalf("unexpected soft delete sql: %s", deleteResult.SQL())
+	}
+	if len(deleteResult.Args()) != 2 || deleteResult.Args()[1] != int64(7) {
+		t.Fatalf("unexpected soft delete args: %#v", deleteResult.Args())
+	}
+
+	restoreResult, err := builder.BuildRestore(noteModel)
+	if err != nil {
+		t.Fatalf("BuildRestore failed: %v", err)
+	}
+	if restoreResult.SQL() != `UPDATE "tenant_Note" SET "deletedAt" = NULL WHERE "id" = $1 AND "deletedAt" IS NOT NULL` {
+		t.Fatalf("unexpected restore sql: %s", restoreResult.SQL())
+	}
+
+	_, _, productModel := buildVMIProductValueModel(t)
+	if _, err = builder.BuildSoftDelete(productModel); err == nil || err.Code != cd.IllegalParam {
+		t.Fatalf("expected soft delete without softdelete field to fail, got %v", err)
+	}
+}
+
+func buildVersionDocModel(t *testing.T) (provider.Provider, *Builder, models.Model) {
+	t.Helper()
+
+	docObject := &remote.Object{
+		Name:    "doc",
+		PkgPath: "/lock",
+		Fields: []*remote.Field{
+			{
+				Name: "id",
+				Type: &remote.TypeImpl{Name: "int64", Value: models.TypeBigIntegerValue},
+				Spec: &remote.SpecImpl{FieldName: "id", PrimaryKey: true, ViewDeclare: []models.ViewDeclare{models.DetailView}},
+			},
+			{
+				Name: "title",
+				Type: &remote.TypeImpl{Name: "string", Value: models.TypeStringValue},
+				Spec: &remote.SpecImpl{FieldName: "title", ViewDeclare: []models.ViewDeclare{models.DetailView}},
+			},
+			{
+				Name: "version",
+				Type: &remote.TypeImpl{Name: "int64", Value: models.TypeBigIntegerValue},
+				Spec: &remote.SpecImpl{FieldName: "version", ValueDeclare: models.Version, ViewDeclare: []models.ViewDeclare{models.DetailView}},
+			},
+		},
+	}
+
+	remoteProvider := provider.NewRemoteProvider("tenant", nil)
+	if _, err := remoteProvider.RegisterModel(docObject); err != nil {
+		t.Fatalf("RegisterModel(doc) failed: %v", err)
+	}
+	docModel, err := remoteProvider.GetEntityModel(&remote.ObjectValue{
+		Name:    "doc",
+		PkgPath: "/lock",
+		Fields: []*remote.FieldValue{
+			{Name: "id", Value: int64(7)},
+			{Name: "title", Value: "draft"},
+		},
+	}, true)
+	if err != nil {
+		t.Fatalf("GetEntityModel(doc) failed: %v", err)
+	}
+
+	return remoteProvider, NewBuilder(remoteProvider, codec.New(remoteProvider, "tenant")), docModel
+}
+
+func TestBuilderVersionUpdate(t *testing.T) {
+	remoteProvider, builder, docModel := buildVersionDocModel(t)
+
+	if _, err := builder.BuildUpdate(docModel); err == nil || err.Code != cd.IllegalParam {
+		t.Fatalf("expected update without version value to fail, got %v", err)
+	}
+
+	if err := docModel.SetFieldValue("version", int64(3)); err != nil {
+		t.Fatalf("SetFieldValue(version) failed: %v", err)
+	}
+	updateResult, err := builder.BuildUpdate(docModel)
+	if err != nil {
+		t.Fatalf("BuildUpdate failed: %v", err)
+	}
+	if updateResult.SQL() != `UPDATE "tenant_Doc" SET "title" = $1,"version" = "version" + 1 WHERE "id" = $2 AND "version" = $3` {
+		t.Fatalf("unexpected versioned update sql: %s", updateResult.SQL())
+	}
+	if !reflect.DeepEqual(updateResult.Args(), []any{"draft", int64(7), int64(3)}) {
+		t.Fatalf("unexpected versioned update args: %#v", updateResult.Args())
+	}
+
+	filter, filterErr := remoteProvider.GetModelFilter(docModel)
+	if filterErr != nil {
+		t.Fatalf("GetModelFilter failed: %v", filterErr)
+	}
+	if filterErr = filter.Equal("title", "draft"); filterErr != nil {
+		t.Fatalf("filter.Equal(title) failed: %v", filterErr)
+	}
+	updateResult, err = builder.BuildUpdateByFilter(docModel, filter, []string{"title"})
+	if err != nil {
+		t.Fatalf("BuildUpdateByFilter failed: %v", err)
+	}
+	if updateResult.SQL() != `UPDATE "tenant_Doc" SET "title" = $1,"version" = "version" + 1 WHERE "title" = $2` {
+		t.Fatalf("unexpected versioned update by filter sql: %s", updateResult.SQL())
+	}
+	if _, err = builder.BuildUpdateByFilter(docModel, filter, []string{"version"}); err == nil {
+		t.Fatal("expected version field update by filter to fail")
+	}
+}
+
+func buildStockModel(t *testing.T) (provider.Provider, *Builder, models.Model) {
+	t.Helper()
+
+	stockObject := &remote.Object{
+		Name:    "stock",
+		PkgPath: "/counter",
+		Fields: []*remote.Field{
+			{
+				Name: "id",
+				Type: &remote.TypeImpl{Name: "int64", Value: models.TypeBigIntegerValue},
+				Spec: &remote.SpecImpl{FieldName: "id", PrimaryKey: true, ViewDeclare: []models.ViewDeclare{models.DetailView}},
+			},
+			{
+				Name: "sku",
+				Type: &remote.TypeImpl{Name: "string", Value: models.TypeStringValue},
+				Spec: &remote.SpecImpl{FieldName: "sku", ViewDeclare: []models.ViewDeclare{models.DetailView}},
+			},
+			{
+				Name: "qty",
+				Type: &remote.TypeImpl{Name: "int64", Value: models.TypeBigIntegerValue},
+				Spec: &remote.SpecImpl{FieldName: "qty", ViewDeclare: []models.ViewDeclare{models.DetailView}},
+			},
+			{
+				Name: "version",
+				Type: &remote.TypeImpl{Name: "int64", Value: models.TypeBigIntegerValue},
+				Spec: &remote.SpecImpl{FieldName: "version", ValueDeclare: models.Version, ViewDeclare: []models.ViewDeclare{models.DetailView}},
+			},
+		},
+	}
+
+	remoteProvider := provider.NewRemoteProvider("tenant", nil)
+	if _, err := remoteProvider.RegisterModel(stockObject); err != nil {
+		t.Fatalf("RegisterModel(stock) failed: %v", err)
+	}
+	stockModel, err := remoteProvider.GetEntityModel(&remote.ObjectValue{Name: "stock", PkgPath: "/counter"}, true)
+	if err != nil {
+		t.Fatalf("GetEntityModel(stock) failed: %v", err)
+	}
+
+	return remoteProvider, NewBuilder(remoteProvider, codec.New(remoteProvider, "tenant")), stockModel
+}
+
+func TestBuilderUpdateByExpr(t *testing.T) {
+	remoteProvider, builder, stockModel := buildStockModel(t)
+
+	filter, filterErr := remoteProvider.GetModelFilter(stockModel)
+	if filterErr != nil {
+		t.Fatalf("GetModelFilter failed: %v", filterErr)
+	}
+	if filterErr = filter.Equal("sku", "A-1"); filterErr != nil {
+		t.Fatalf("filter.Equal(sku) failed: %v", filterErr)
+	}
+
+	updateResult, err := builder.BuildUpdateByExpr(stockModel, filter, []models.UpdateExpr{models.Decrease("qty", 2)}, []models.UpdateGuard{models.Guard("qty", models.AboveEqualGuard, 2)})
+	if err != nil {
+		t.Fatalf("BuildUpdateByExpr failed: %v", err)
+	}
+	if updateResult.SQL() != `UPDATE "tenant_Stock" SET "qty" = "qty" - $1,"version" = "version" + 1 WHERE "sku" = $2 AND "qty" >= $3` {
+		t.Fatalf("unexpected update by expr sql: %s", updateResult.SQL())
+	}
+	if !reflect.DeepEqual(updateResult.Args(), []any{int64(2), "A-1", int64(2)}) {
+		t.Fatalf("unexpected update by expr args: %#v", updateResult.Args())
+	}
+
+	emptyFilter, _ := remoteProvider.GetModelFilter(stockModel)
+	updateResult, err = builder.BuildUpdateByExpr(stockModel, emptyFilter, []models.UpdateExpr{models.Increase("qty", 1)}, []models.UpdateGuard{models.Guard("qty", models.BelowGuard, 100)})
+	if err != nil {
+		t.Fatalf("BuildUpdateByExpr(no filter) failed: %v", err)
+	}
+	if updateResult.SQL() != `UPDATE "tenant_Stock" SET "qty" = "qty" + $1,"version" = "version" + 1 WHERE "qty" < $2` {
+		t.Fatalf("unexpected guarded update by expr sql: %s", updateResult.SQL())
+	}
+
+	for _, exprs := range [][]models.UpdateExpr{
+		nil,
+		{models.Increase("missing", 1)},
+		{models.Increase("id", 1)},
+		{models.Increase("sku", 1)},
+		{models.Increase("version", 1)},
+		{models.Increase("qty", "many")},
+		{models.Multiply("qty", 1.5)},
+		{models.Increase("qty", 0.5)},
+		{models.Decrease("qty", -2.9)},
+		{models.Increase("qty", "0.5")},
+		{models.Multiply("qty", 1e30)},
+		{{Field: "qty", Opr: models.ExprOpr(9), Value: 1}},
+	} {
+		if _, err = builder.BuildUpdateByExpr(stockModel, filter, exprs, nil); err == nil || err.Code != cd.IllegalParam {
+			t.Fatalf("update expressions %#v should be rejected, got %v", exprs, err)
+		}
+	}
+
+	if _, err = builder.BuildUpdateByExpr(stockModel, filter, []models.UpdateExpr{models.Increase("qty", 1)}, []models.UpdateGuard{models.Guard("qty", models.AboveEqualGuard, 0.5)}); err == nil || err.Code != cd.IllegalParam {
+		t.Fatalf("fractional guard on integer field should be rejected, got %v", err)
+	}
+	updateResult, err = builder.BuildUpdateByExpr(stockModel, filter, []models.UpdateExpr{models.Multiply("qty", 3.0)}, nil)
+	if err != nil {
+		t.Fatalf("integral float factor should be accepted: %v", err)
+	}
+	if !reflect.DeepEqual(updateResult.Args()[0], int64(3)) {
+		t.Fatalf("unexpected multiply args: %#v", updateResult.Args())
+	}
+}
+
+func TestBuilderVMIFilterPath(t *testing.T) {
+	_, builder, orderModel, filter, _, _ := buildVMIOrderFilter(t)
+	if err := filter.Equal("sn", "SO-1"); err != nil {
+		t.Fatalf("filter.Equal(sn) failed: %v", err)
+	}
+	if err := filter.Like("customer.name", "ops"); err != nil {
+		t.Fatalf("filter.Like(customer.name) failed: %v", err)
+	}
+	if err := filter.Equal("customer.status.value", 2); err != nil {
+		t.Fatalf("filter.Equal(customer.status.value) failed: %v", err)
+	}
+
+	queryResult, err := builder.BuildQuery(orderModel, filter)
+	if err != nil {
+		t.Fatalf("BuildQuery failed: %v", err)
+	}
+	if !strings.HasSuffix(queryResult.SQL(), `FROM "tenant_Order" WHERE "sn" = $1 AND "id" IN (SELECT "left" FROM "tenant_OrderCustomer3Partner" WHERE "right" IN (SELECT "id" FROM "tenant_Partner" WHERE "name" LIKE $2 AND "id" IN (SELECT "left" FROM "tenant_PartnerStatus3Status" WHERE "right" IN (SELECT "id" FROM "tenant_Status" WHERE "value" = $3))))`) {
+		t.Fatalf("unexpected filter path sql: %s", queryResult.SQL())
+	}
+	if !reflect.DeepEqual(queryResult.Args(), []any{"SO-1", "ops", 2}) {
+		t.Fatalf("unexpected filter path args: %#v", queryResult.Args())
+	}
+
+	builder.SetFilterPathDepth(1)
+	if _, err = builder.BuildCount(orderModel, filter); err == nil || err.Code != cd.IllegalParam {
+		t.Fatalf("expected filter path deeper than limit to fail, got %v", err)
+	}
+
+	_, builder, orderModel, filter, _, _ = buildVMIOrderFilter(t)
+	if err = filter.Equal("customer.missing", 1); err != nil {
+		t.Fatalf("filter.Equal(customer.missing) failed: %v", err)
+	}
+	if _, err = builder.BuildQuery(orderModel, filter); err == nil || err.Code != cd.IllegalParam {
+		t.Fatalf("expected unknown filter path to fail, got %v", err)
+	}
+}
+
+func TestBuilderVMIQueryRelationModels(t *testing.T) {
+	remoteProvider, builder, orderModel, _, _, _ := buildVMIOrderFilter(t)
+	orderModel.SetPrimaryFieldValue(int64(1))
+	goodsField := orderModel.GetField("goods")
+	goodsModel, err := remoteProvider.GetTypeModel(goodsField.GetType().Elem())
+	if err != nil {
+		t.Fatalf("GetTypeModel(goodsItem) failed: %v", err)
+	}
+	goodsFilter, err := remoteProvider.GetModelFilter(goodsModel)
+	if err != nil {
+		t.Fatalf("GetModelFilter(goodsItem) failed: %v", err)
+	}
+	if err = goodsFilter.Above("count", 2); err != nil {
+		t.Fatalf("filter.Above(count) failed: %v", err)
+	}
+	goodsFilter.Sort("price", false)
+	goodsFilter.Pagination(2, 10)
+
+	queryResult, err := builder.BuildQueryRelationModels(orderModel, goodsField, goodsModel, goodsFilter)
+	if err != nil {
+		t.Fatalf("BuildQueryRelationModels failed: %v", err)
+	}
+	if !strings.HasSuffix(queryResult.SQL(), `FROM "tenant_GoodsItem" WHERE "id" IN (SELECT "right" FROM "tenant_OrderGoods2GoodsItem" WHERE "left"= $1) AND "count" > $2 ORDER BY "price" DESC LIMIT $3 OFFSET $4`) {
+		t.Fatalf("unexpected query relation models sql: %s", queryResult.SQL())
+	}
+	if !reflect.DeepEqual(queryResult.Args(), []any{int64(1), 2, int64(10), int64(10)}) {
+		t.Fatalf("unexpected query relation models args: %#v", queryResult.Args())
+	}
+
+	countResult, err := builder.BuildCountRelationModels(orderModel, goodsField, goodsModel, goodsFilter)
+	if err != nil {
+		t.Fatalf("BuildCountRelationModels failed: %v", err)
+	}
+	if countResult.SQL() != `SELECT COUNT(*) FROM "tenant_GoodsItem" WHERE "id" IN (SELECT "right" FROM "tenant_OrderGoods2GoodsItem" WHERE "left"= $1) AND "count" > $2` {
+		t.Fatalf("unexpected count relation models sql: %s", countResult.SQL())
+	}
+}
+
+func TestBuilderVMISelectFields(t *testing.T) {
+	_, builder, orderModel, filter, _, _ := buildVMIOrderFilter(t)
+	if err := filter.Select("sn"); err != nil {
+		t.Fatalf("filter.Select(sn) failed: %v", err)
+	}
+
+	queryResult, err := builder.BuildQuery(orderModel, filter)
+	if err != nil {
+		t.Fatalf("BuildQuery failed: %v", err)
+	}
+	if queryResult.SQL() != `SELECT "id","sn" FROM "tenant_Order"` {
+		t.Fatalf("unexpected select fields sql: %s", queryResult.SQL())
+	}
+}
+
+func TestBuilderVMIExistsFilter(t *testing.T) {
+	remoteProvider, builder, orderModel, filter, _, _ := buildVMIOrderFilter(t)
+	goodsModel, err := remoteProvider.GetTypeModel(orderModel.GetField("goods").GetType().Elem())
+	if err != nil {
+		t.Fatalf("GetTypeModel(goodsItem) failed: %v", err)
+	}
+	goodsFilter, err := remoteProvider.GetModelFilter(goodsModel)
+	if err != nil {
+		t.Fatalf("GetModelFilter(goodsItem) failed: %v", err)
+	}
+	if err = goodsFilter.Above("count", 2); err != nil {
+		t.Fatalf("filter.Above(count) failed: %v", err)
+	}
+	if err = filter.Exists("goods", goodsFilter); err != nil {
+		t.Fatalf("filter.Exists(goods) failed: %v", err)
+	}
+	if err = filter.NotExists("customer", nil); err != nil {
+		t.Fatalf("filter.NotExists(customer) failed: %v", err)
+	}
+	if err = filter.Exists("customer", goodsFilter); err == nil || err.Code != cd.IllegalParam {
+		t.Fatalf("expected mismatched exists filter to fail, got %v", err)
+	}
+	if err = filter.Exists("sn", nil); err == nil || err.Code != cd.IllegalParam {
+		t.Fatalf("expected basic exists field to fail, got %v", err)
+	}
+
+	expectSQL := `FROM "tenant_Order" WHERE NOT EXISTS (SELECT 1 FROM "tenant_OrderCustomer3Partner" WHERE "left" = "tenant_Order"."id") AND EXISTS (SELECT 1 FROM "tenant_OrderGoods2GoodsItem" WHERE "left" = "tenant_Order"."id" AND "right" IN (SELECT "id" FROM "tenant_GoodsItem" WHERE "count" > $1))`
+	queryResult, err := builder.BuildQuery(orderModel, filter)
+	if err != nil {
+		t.Fatalf("BuildQuery failed: %v", err)
+	}
+	if !strings.HasSuffix(queryResult.SQL(), expectSQL) || !reflect.DeepEqual(queryResult.Args(), []any{2}) {
+		t.Fatalf("unexpected exists filter sql: %s, args: %#v", queryResult.SQL(), queryResult.Args())
+	}
+
+	filterData, jsonErr := json.Marshal(filter)
+	if jsonErr != nil {
+		t.Fatalf("json.Marshal(filter) failed: %v", jsonErr)
+	}
+	decodeFilter := remote.NewFilter(orderModel)
+	if jsonErr = json.Unmarshal(filterData, decodeFilter); jsonErr != nil {
+		t.Fatalf("json.Unmarshal(filter) failed: %v", jsonErr)
+	}
+	queryResult, err = builder.BuildQuery(orderModel, decodeFilter)
+	if err != nil {
+		t.Fatalf("BuildQuery(decoded filter) failed: %v", err)
+	}
+	if !strings.HasSuffix(queryResult.SQL(), expectSQL) {
+		t.Fatalf("unexpected decoded exists filter sql: %s", queryResult.SQL())
+	}
+}
+
+func buildFullTextArticleModel(t *testing.T) (provider.Provider, *Builder, models.Model) {
+	t.Helper()
+
+	articleObject := &remote.Object{
+		Name:    "article",
+		PkgPath: "/fulltext",
+		Fields: []*remote.Field{
+			{
+				Name: "id",
+				Type: &remote.TypeImpl{Name: "int64", Value: models.TypeBigIntegerValue},
+				Spec: &remote.SpecImpl{FieldName: "id", PrimaryKey: true, ViewDeclare: []models.ViewDeclare{models.DetailView}},
+			},
+			{
+				Name: "title",
+				Type: &remote.TypeImpl{Name: "string", Value: models.TypeStringValue},
+				Spec: &remote.SpecImpl{FieldName: "title", ValueDeclare: models.FullText, ViewDeclare: []models.ViewDeclare{models.DetailView}},
+			},
+			{
+				Name: "body",
+				Type: &remote.TypeImpl{Name: "string", Value: models.TypeStringValue},
+				Spec: &remote.SpecImpl{FieldName: "body", ValueDeclare: models.FullText, ViewDeclare: []models.ViewDeclare{models.DetailView}},
+			},
+			{
+				Name: "author",
+				Type: &remote.TypeImpl{Name: "string", Value: models.TypeStringValue},
+				Spec: &remote.SpecImpl{FieldName: "author", ViewDeclare: []models.ViewDeclare{models.DetailView}},
+			},
+		},
+	}
+
+	remoteProvider := provider.NewRemoteProvider("tenant", nil)
+	if _, err := remoteProvider.RegisterModel(articleObject); err != nil {
+		t.Fatalf("RegisterModel(article) failed: %v", err)
+	}
+	articleModel, err := remoteProvider.GetEntityModel(&remote.ObjectValue{Name: "article", PkgPath: "/fulltext"}, true)
+	if err != nil {
+		t.Fatalf("GetEntityModel(article) failed: %v", err)
+	}
+
+	return remoteProvider, NewBuilder(remoteProvider, codec.New(remoteProvider, "tenant")), articleModel
+}
+
+func TestBuilderFullTextSearch(t *testing.T) {
+	remoteProvider, builder, articleModel := buildFullTextArticleModel(t)
+
+	createResult, err := builder.BuildCreateTable(articleModel)
+	if err != nil {
+		t.Fatalf("BuildCreateTable failed: %v", err)
+	}
+	expectIndexSQL := ");\nCREATE INDEX IF NOT EXISTS \"tenant_Article_title_fulltext\" ON \"tenant_Article\" USING GIN (to_tsvector('simple', \"title\"));\nCREATE INDEX IF NOT EXISTS \"tenant_Article_body_fulltext\" ON \"tenant_Article\" USING GIN (to_tsvector('simple', \"body\"))\n"
+	if !strings.HasSuffix(createResult.SQL(), expectIndexSQL) {
+		t.Fatalf("unexpected fulltext create sql: %s", createResult.SQL())
+	}
+
+	filter, err := remoteProvider.GetModelFilter(articleModel)
+	if err != nil {
+		t.Fatalf("GetModelFilter(article) failed: %v", err)
+	}
+	if err = filter.Match("title", "go orm"); err != nil {
+		t.Fatalf("filter.Match(title) failed: %v", err)
+	}
+	if err = filter.Match("body", "orm"); err != nil {
+		t.Fatalf("filter.Match(body) failed: %v", err)
+	}
+	filter.Sort(models.SortRelevance, false)
+	filter.Pagination(1, 10)
+
+	queryResult, err := builder.BuildQuery(articleModel, filter)
+	if err != nil {
+		t.Fatalf("BuildQuery failed: %v", err)
+	}
+	if !strings.HasSuffix(queryResult.SQL(), `FROM "tenant_Article" WHERE to_tsvector('simple', "title") @@ websearch_to_tsquery('simple', $1) AND to_tsvector('simple', "body") @@ websearch_to_tsquery('simple', $2) ORDER BY ts_rank(to_tsvector('simple', "title"), websearch_to_tsquery('simple', $3)) + ts_rank(to_tsvector('simple', "body"), websearch_to_tsquery('simple', $4)) DESC LIMIT $5 OFFSET $6`) {
+		t.Fatalf("unexpected fulltext query sql: %s", queryResult.SQL())
+	}
+	if !reflect.DeepEqual(queryResult.Args(), []any{"go orm", "orm", "go orm", "orm", int64(10), int64(0)}) {
+		t.Fatalf("unexpected fulltext query args: %#v", queryResult.Args())
+	}
+
+	filter, _ = remoteProvider.GetModelFilter(articleModel)
+	filter.Sort(models.SortRelevance, false)
+	if _, err = builder.BuildQuery(articleModel, filter); err == nil || err.Code != cd.IllegalParam {
+		t.Fatalf("expected relevance sort without match to fail, got %v", err)
+	}
+	if err = filter.Match("author", "alice"); err != nil {
+		t.Fatalf("filter.Match(author) failed: %v", err)
+	}
+	if _, err = builder.BuildCount(articleModel, filter); err == nil || err.Code != cd.IllegalParam {
+		t.Fatalf("expected match on plain field to fail, got %v", err)
+	}
+}
@@ -3,11 +3,16 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"errors"
 	"fmt"
+	"net"
+	"net/url"
+	"sync"
 	"sync/atomic"
 	"time"
 
-	_ "github.com/lib/pq" //引入PostgreSQL驱动
+	"github.com/lib/pq" //引入PostgreSQL驱动
 
 	"log/slog"
 
@@ -15,14 +20,15 @@ import (
 	"github.com/muidea/magicOrm/database"
 )
 
-const defaultSSLMode = "disable"
+const defaultSSLMode = database.SSLModeDisable
+const defaultPort = "5432"
 
 type Config struct {
-	dbServer string
-	dbName   string
-	username string
-	password string
-	sslMode  string
+	dbServer  string
+	dbName    string
+	username  string
+	password  string
+	tlsConfig database.TLSConfig
 }
 
 func (s *Config) Server() string {
@@ -42,21 +48,46 @@ func (s *Config) Password() string {
 }
 
 func (s *Config) SSLMode() string {
-	if s.sslMode == "" {
+	if s.tlsConfig.SSLMode == "" {
 		return defaultSSLMode
 	}
 
-	return s.sslMode
+	return s.tlsConfig.SSLMode
+}
+
+// TLS 返回TLS配置
+func (s *Config) TLS() *database.TLSConfig {
+	return &s.tlsConfig
+}
+
+// WithTLS 设置TLS配置，tlsConfig为nil时保持默认的disable
+func (s *Config) WithTLS(tlsConfig *database.TLSConfig) *Config {
+	if tlsConfig != nil {
+		s.tlsConfig = *tlsConfig
+	}
+
+	return s
+}
+
+// Validate 校验配置
+func (s *Config) Validate() *cd.Error {
+	return s.tlsConfig.Validate()
 }
 
 func (s *Config) Same(cfg *Config) bool {
 	return s.dbServer == cfg.dbServer &&
 		s.dbName == cfg.dbName &&
 		s.username == cfg.username &&
-		s.password == cfg.password
+		s.password == cfg.password &&
+		s.tlsConfig.Same(&cfg.tlsConfig)
 }
 
 func (s *Config) GetDsn() string {
+	return s.getDsn(s.Server())
+}
+
+// getDsn 以指定的host构造dsn，配置ServerName时host需要替换成ServerName，驱动据此校验服务端证书
+func (s *Config) getDsn(server string) string {
 	// dbName在实际转递时会存在databaseName/schemaName这种情况，其中schemaName是可选
 	// 所以在这里转换成dsn字符串时，进行区分处理databaseName/schemaName,databaseName这两种情况
 	dbName := s.Database()
@@ -75,410 +106,240 @@ func (s *Config) GetDsn() string {
 		}
 	}
 
-	return fmt.Sprintf("postgres://%s:%s@%s/%s?sslmode=%s&options=-c%%20search_path=%s",
-		s.Username(), s.Password(), s.Server(), dbName, s.SSLMode(), schemaName)
+	dsn := fmt.Sprintf("postgres://%s:%s@%s/%s?sslmode=%s&options=-c%%20search_path=%s",
+		s.Username(), s.Password(), server, dbName, s.SSLMode(), schemaName)
+	if s.tlsConfig.RootCert != "" {
+		dsn = fmt.Sprintf("%s&sslrootcert=%s", dsn, url.QueryEscape(s.tlsConfig.RootCert))
+	}
+	if s.tlsConfig.ClientCert != "" {
+		dsn = fmt.Sprintf("%s&sslcert=%s&sslkey=%s", dsn, url.QueryEscape(s.tlsConfig.ClientCert), url.QueryEscape(s.tlsConfig.ClientKey))
+	}
+
+	return dsn
 }
 
 func NewConfig(dbServer, dbName, username, password string) *Config {
-	return &Config{dbServer: dbServer, dbName: dbName, username: username, password: password, sslMode: "disable"}
+	return &Config{dbServer: dbServer, dbName: dbName, username: username, password: password, tlsConfig: database.TLSConfig{SSLMode: defaultSSLMode}}
 }
 
-// NewExecutor 新建一个数据访问对象
-func NewExecutor(configPtr database.Config) (ret *HostExecutor, err *cd.Error) {
-	dsn := configPtr.GetDsn()
-	dbHandle, dbErr := sql.Open("postgres", dsn)
-	if dbErr != nil {
-		err = cd.NewError(cd.Unexpected, dbErr.Error())
-		slog.Error("open database exception", "dsn", dsn, "error", err.Error())
-		return
-	}
+// isTransientError 瞬时连接错误，包括连接异常类(08)以及服务端关闭、重启中的错误
+func isTransientError(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "57P01", "57P02", "57P03":
+			return true
+		}
 
-	ret = &HostExecutor{
-		executeContetxt: context.Background(),
-		dbHandle:        dbHandle,
-		ownDBHandle:     true,
+		return pqErr.Code.Class() == "08"
 	}
 
-	return
+	return database.IsTransientError(err)
 }
 
-// ConnExecutor ConnExecutor
-type ConnExecutor struct {
-	executeContetxt context.Context
-	dbConnPtr       *sql.Conn
-	dbTxCount       int32
-	dbTx            *sql.Tx
-	rowsHandle      *sql.Rows
+// serverDialer 配置了ServerName时，dsn中的host为ServerName，实际连接时需要拨号到原始的数据库地址
+type serverDialer struct {
+	address string
+	dialer  net.Dialer
 }
 
-func (s *ConnExecutor) Release() {
-	if s.rowsHandle != nil {
-		if err := s.rowsHandle.Close(); err != nil {
-			slog.Warn("Failed to close rows handle", "error", err.Error())
-		}
-	}
-	if s.dbTx != nil {
-		if err := s.dbTx.Rollback(); err != nil && err != sql.ErrTxDone {
-			slog.Warn("Failed to rollback transaction", "error", err.Error())
-		}
-	}
-	if s.dbConnPtr != nil {
-		if err := s.dbConnPtr.Close(); err != nil {
-			slog.Warn("Failed to close database connection", "error", err.Error())
-		}
-	}
+func (s *serverDialer) Dial(network, _ string) (net.Conn, error) {
+	return s.dialer.Dial(network, s.address)
 }
 
-func (s *ConnExecutor) BeginTransaction() (err *cd.Error) {
-	defer func() {
-		database.RecordDatabaseTransaction(database.DatabasePostgreSQL, "begin", err == nil)
-	}()
-
-	atomic.AddInt32(&s.dbTxCount, 1)
-	if s.dbTx == nil && s.dbTxCount == 1 {
-		if s.rowsHandle != nil {
-			_ = s.rowsHandle.Close()
-		}
-		s.rowsHandle = nil
-
-		tx, txErr := s.dbConnPtr.BeginTx(s.executeContetxt, nil)
-		if txErr != nil {
-			err = cd.NewError(cd.Unexpected, txErr.Error())
-			slog.Error("BeginTransaction failed", "value", "s.dbHandle.Begin", "error", err.Error())
-			return
-		}
-
-		s.dbTx = tx
-		//log.Print("BeginTransaction")
-	}
-
-	return
+func (s *serverDialer) DialTimeout(network, _ string, timeout time.Duration) (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return s.dialer.DialContext(ctx, network, s.address)
 }
 
-func (s *ConnExecutor) CommitTransaction() (err *cd.Error) {
-	defer func() {
-		database.RecordDatabaseTransaction(database.DatabasePostgreSQL, "commit", err == nil)
-	}()
+func (s *serverDialer) DialContext(ctx context.Context, network, _ string) (net.Conn, error) {
+	return s.dialer.DialContext(ctx, network, s.address)
+}
 
-	atomic.AddInt32(&s.dbTxCount, -1)
-	if s.dbTx != nil && s.dbTxCount == 0 {
-		dbErr := s.dbTx.Commit()
-		if dbErr != nil {
-			s.dbTx = nil
-			err = cd.NewError(cd.Unexpected, dbErr.Error())
-			slog.Error("CommitTransaction failed", "value", "s.dbTx.Commit", "error", err.Error())
+// openDatabase 打开数据库，配置了ServerName时通过Connector指定实际的拨号地址
+func openDatabase(configPtr database.Config) (ret *sql.DB, err *cd.Error) {
+	cfgPtr, ok := configPtr.(*Config)
+	if ok {
+		err = cfgPtr.Validate()
+		if err != nil {
+			slog.Error("open database exception, illegal config", "server", cfgPtr.Server(), "error", err.Error())
 			return
 		}
-
-		s.dbTx = nil
-		//log.Print("Commit")
 	}
 
-	return
-}
-
-func (s *ConnExecutor) RollbackTransaction() (err *cd.Error) {
-	defer func() {
-		database.RecordDatabaseTransaction(database.DatabasePostgreSQL, "rollback", err == nil)
-	}()
-
-	atomic.AddInt32(&s.dbTxCount, -1)
-	if s.dbTx != nil && s.dbTxCount == 0 {
-		dbErr := s.dbTx.Rollback()
+	if !ok || !cfgPtr.tlsConfig.Enabled() || cfgPtr.tlsConfig.ServerName == "" {
+		dbHandle, dbErr := sql.Open("postgres", configPtr.GetDsn())
 		if dbErr != nil {
-			s.dbTx = nil
 			err = cd.NewError(cd.Unexpected, dbErr.Error())
-			slog.Error("RollbackTransaction failed", "value", "s.dbTx.Rollback", "error", err.Error())
+			slog.Error("open database exception", "server", configPtr.Server(), "error", err.Error())
 			return
 		}
 
-		s.dbTx = nil
-		//log.Print("Rollback")
+		ret = dbHandle
+		return
 	}
 
-	return
-}
-
-func (s *ConnExecutor) Query(sql string, needCols bool, args ...any) (ret []string, err *cd.Error) {
-	//slog.Info("message")
-	startTime := time.Now()
-	defer func() {
-		endTime := time.Now()
-		elapse := endTime.Sub(startTime)
-		database.RecordDatabaseQuery(database.DatabasePostgreSQL, sql, elapse, err)
-		if err != nil {
-			slog.Error("Query failed", "execute_time", startTime.Local().String(), "elapse", elapse, "sql", sql, "error", err.Error())
-			return
-		}
-
-		if traceSQL() {
-			slog.Info("Query ok", "execute_time", startTime.Local().String(), "elapse", elapse, "sql", sql)
-		}
-	}()
-
-	if s.dbTx == nil {
-		if s.dbConnPtr == nil {
-			panic("dbHandle is nil")
-		}
-		if s.rowsHandle != nil {
-			_ = s.rowsHandle.Close()
-			s.rowsHandle = nil
-		}
-
-		rows, rowErr := s.dbConnPtr.QueryContext(s.executeContetxt, sql, args...)
-		if rowErr != nil {
-			err = cd.NewError(cd.Unexpected, rowErr.Error())
-			slog.Error("Query failed", "sql", sql, "args", args, "error", rowErr.Error())
-			return
-		}
-		if needCols {
-			cols, colsErr := rows.Columns()
-			if colsErr != nil {
-				err = cd.NewError(cd.Unexpected, colsErr.Error())
-				slog.Error("Query failed", "sql", sql, "operation", "rows.Columns", "error", colsErr.Error())
-				return
-			}
-
-			ret = cols
-		}
-		s.rowsHandle = rows
+	serverAddress := cfgPtr.Server()
+	port := defaultPort
+	if _, serverPort, splitErr := net.SplitHostPort(serverAddress); splitErr == nil {
+		port = serverPort
 	} else {
-		if s.rowsHandle != nil {
-			_ = s.rowsHandle.Close()
-			s.rowsHandle = nil
-		}
-
-		rows, rowErr := s.dbTx.Query(sql, args...)
-		if rowErr != nil {
-			err = cd.NewError(cd.Unexpected, rowErr.Error())
-			slog.Error("Query failed", "sql", sql, "operation", "s.dbTx.Query", "error", rowErr.Error())
-			return
-		}
-		if needCols {
-			cols, colsErr := rows.Columns()
-			if colsErr != nil {
-				err = cd.NewError(cd.Unexpected, colsErr.Error())
-				slog.Error("Query failed", "sql", sql, "operation", "rows.Columns", "error", colsErr.Error())
-				return
-			}
+		serverAddress = net.JoinHostPort(serverAddress, port)
+	}
 
-			ret = cols
-		}
-		s.rowsHandle = rows
+	connector, connectorErr := pq.NewConnector(cfgPtr.getDsn(net.JoinHostPort(cfgPtr.tlsConfig.ServerName, port)))
+	if connectorErr != nil {
+		err = cd.NewError(cd.Unexpected, connectorErr.Error())
+		slog.Error("open database exception", "server", cfgPtr.Server(), "error", err.Error())
+		return
 	}
+	connector.Dialer(&serverDialer{address: serverAddress})
 
+	ret = sql.OpenDB(connector)
 	return
 }
 
-func (s *ConnExecutor) Next() bool {
-	if s.rowsHandle == nil {
-		panic("rowsHandle is nil")
+// NewExecutor 新建一个数据访问对象
+func NewExecutor(configPtr database.Config) (ret *HostExecutor, err *cd.Error) {
+	dbHandle, dbErr := openDatabase(configPtr)
+	if dbErr != nil {
+		err = dbErr
+		return
 	}
 
-	ret := s.rowsHandle.Next()
-	if !ret {
-		//log.Print("Next, close rows")
-		_ = s.rowsHandle.Close()
-		s.rowsHandle = nil
+	ret = &HostExecutor{
+		baseExecutor: baseExecutor{
+			executeContetxt: context.Background(),
+			connHandle:      dbHandle,
+			beginTx:         dbHandle.BeginTx,
+		},
+		dbHandle:    dbHandle,
+		ownDBHandle: true,
 	}
 
-	return ret
+	return
 }
 
-func (s *ConnExecutor) Finish() {
-	if s.rowsHandle != nil {
-		_ = s.rowsHandle.Close()
-		s.rowsHandle = nil
-	}
+// sqlHandle *sql.DB、*sql.Conn、*sql.Tx 公共的语句执行接口
+type sqlHandle interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
 }
 
-func (s *ConnExecutor) GetField(value ...any) (err *cd.Error) {
-	if s.rowsHandle == nil {
-		panic("rowsHandle is nil")
-	}
+// baseExecutor ConnExecutor和HostExecutor公共实现
+//
+// 每条SQL都基于executeContetxt派生独立的context执行，设置了statementTimeout时附带超时，
+// context取消或者超时会中断正在执行的SQL
+type baseExecutor struct {
+	executeContetxt  context.Context
+	statementTimeout time.Duration
+	connHandle       sqlHandle
+	beginTx          func(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+	retryPolicy      *database.RetryPolicy
+	// reconnect 连接失效时重新获取连接，为nil时由database/sql自行处理
+	reconnect  func(ctx context.Context) error
+	dbTxCount  int32
+	dbTx       *sql.Tx
+	rowsHandle *sql.Rows
+	rowsCancel context.CancelFunc
+}
 
-	dbErr := s.rowsHandle.Scan(value...)
-	if dbErr != nil {
-		err = cd.NewError(cd.Unexpected, dbErr.Error())
-		slog.Error("GetField failed", "value", "s.rowsHandle.Scan", "error", err.Error())
+func (s *baseExecutor) SetStatementTimeout(timeout time.Duration) {
+	if timeout < 0 {
+		timeout = 0
 	}
 
-	return
+	s.statementTimeout = timeout
 }
 
-func (s *ConnExecutor) Execute(sql string, args ...any) (rowsAffected int64, err *cd.Error) {
-	startTime := time.Now()
-	defer func() {
-		endTime := time.Now()
-		elapse := endTime.Sub(startTime)
-		database.RecordDatabaseExecution(database.DatabasePostgreSQL, sql, err == nil)
-		if err != nil {
-			slog.Error("Execute failed", "execute_time", startTime.Local().String(), "elapse", elapse, "sql", sql, "error", err.Error())
-			return
-		}
-
-		if traceSQL() {
-			slog.Info("Execute ok", "execute_time", startTime.Local().String(), "elapse", elapse, "sql", sql)
-		}
-	}()
-
-	if s.rowsHandle != nil {
-		_ = s.rowsHandle.Close()
-	}
-	s.rowsHandle = nil
-
-	if s.dbTx == nil {
-		if s.dbConnPtr == nil {
-			panic("dbHandle is nil")
-		}
+func (s *baseExecutor) StatementTimeout() time.Duration {
+	return s.statementTimeout
+}
 
-		result, resultErr := s.dbConnPtr.ExecContext(s.executeContetxt, sql, args...)
-		if resultErr != nil {
-			err = cd.NewError(cd.Unexpected, resultErr.Error())
-			slog.Error("Execute failed", "value", "s.dbHandle.Exec", "error", resultErr.Error())
-			return
-		}
+func (s *baseExecutor) SetContext(ctx context.Context) {
+	s.executeContetxt = ctx
+}
 
-		rowsAffected, _ = result.RowsAffected()
-		return
-	}
+func (s *baseExecutor) Context() context.Context {
+	return s.executeContetxt
+}
 
-	result, resultErr := s.dbTx.Exec(sql, args...)
-	if resultErr != nil {
-		err = cd.NewError(cd.Unexpected, resultErr.Error())
-		slog.Error("Execute failed", "value", "s.dbTx.Exec", "error", resultErr.Error())
-		return
+func (s *baseExecutor) getContext() context.Context {
+	if s.executeContetxt == nil {
+		return context.Background()
 	}
 
-	rowsAffected, _ = result.RowsAffected()
-	return
+	return s.executeContetxt
 }
 
-func (s *ConnExecutor) ExecuteInsert(sql string, pkValOut any, args ...any) (err *cd.Error) {
-	startTime := time.Now()
-	defer func() {
-		endTime := time.Now()
-		elapse := endTime.Sub(startTime)
-		database.RecordDatabaseExecution(database.DatabasePostgreSQL, sql, err == nil)
-		if err != nil {
-			slog.Error("ExecuteInsert failed", "execute_time", startTime.Local().String(), "elapse", elapse, "sql", sql, "error", err.Error())
-			return
-		}
-
-		if traceSQL() {
-			slog.Info("ExecuteInsert ok", "execute_time", startTime.Local().String(), "elapse", elapse, "sql", sql)
-		}
-	}()
-
-	if s.rowsHandle != nil {
-		_ = s.rowsHandle.Close()
+// statementContext 单条SQL执行使用的context
+func (s *baseExecutor) statementContext() (context.Context, context.CancelFunc) {
+	if s.statementTimeout > 0 {
+		return context.WithTimeout(s.getContext(), s.statementTimeout)
 	}
-	s.rowsHandle = nil
-
-	if s.dbTx == nil {
-		if s.dbConnPtr == nil {
-			panic("dbHandle is nil")
-		}
-
-		rowPtr := s.dbConnPtr.QueryRowContext(s.executeContetxt, sql, args...)
-		if qErr := rowPtr.Err(); qErr != nil {
-			err = cd.NewError(cd.Unexpected, qErr.Error())
-			slog.Error("ExecuteInsert failed", "value", "rowPtr.Err", "error", qErr.Error())
-			return
-		}
 
-		if rErr := rowPtr.Scan(pkValOut); rErr != nil {
-			err = cd.NewError(cd.Unexpected, rErr.Error())
-			slog.Error("ExecuteInsert failed", "value", "rowPtr.Scan", "error", rErr.Error())
-			return
-		}
-
-		return
-	}
+	return context.WithCancel(s.getContext())
+}
 
-	rowPtr := s.dbTx.QueryRowContext(s.executeContetxt, sql, args...)
-	if qErr := rowPtr.Err(); qErr != nil {
-		err = cd.NewError(cd.Unexpected, qErr.Error())
-		slog.Error("ExecuteInsert failed", "value", "rowPtr.Err", "error", qErr.Error())
-		return
+// currentHandle 开启事务时在事务内执行，否则直接在连接上执行
+func (s *baseExecutor) currentHandle() sqlHandle {
+	if s.dbTx != nil {
+		return s.dbTx
 	}
-
-	if rErr := rowPtr.Scan(pkValOut); rErr != nil {
-		err = cd.NewError(cd.Unexpected, rErr.Error())
-		slog.Error("ExecuteInsert failed", "value", "rowPtr.Scan", "error", rErr.Error())
-		return
+	if s.connHandle == nil {
+		panic("dbHandle is nil")
 	}
 
-	return
+	return s.connHandle
 }
 
-// CheckTableExist Check Table Exist
-func (s *ConnExecutor) CheckTableExist(tableName string) (ret bool, err *cd.Error) {
-	strSQL := "SELECT tablename FROM pg_tables WHERE tablename = $1 AND schemaname = 'public'"
-	_, err = s.Query(strSQL, false, tableName)
-	if err != nil {
-		slog.Error("CheckTableExist failed", "value", "s.Query", "error", err.Error())
+// renewConnection 事务外遇到瞬时连接错误时重新获取连接，便于后续重试
+func (s *baseExecutor) renewConnection(err error) {
+	if s.dbTx != nil || s.reconnect == nil || !s.retryPolicy.Enabled() || !isTransientError(err) {
 		return
 	}
 
-	if s.Next() {
-		ret = true
+	if connErr := s.reconnect(s.getContext()); connErr != nil {
+		slog.Warn("renew database connection failed", "error", connErr.Error())
 	}
-
-	s.Finish()
-
-	return
 }
 
-// ConnExecutor ConnExecutor
-type HostExecutor struct {
-	executeContetxt context.Context
-	dbHandle        *sql.DB
-	dbTxCount       int32
-	dbTx            *sql.Tx
-	rowsHandle      *sql.Rows
-	ownDBHandle     bool
-}
-
-func (s *HostExecutor) Release() {
+func (s *baseExecutor) closeRows() {
 	if s.rowsHandle != nil {
-		if err := s.rowsHandle.Close(); err != nil {
-			slog.Warn("Failed to close rows handle", "error", err.Error())
-		}
+		_ = s.rowsHandle.Close()
+		s.rowsHandle = nil
+	}
+	if s.rowsCancel != nil {
+		s.rowsCancel()
+		s.rowsCancel = nil
 	}
+}
+
+func (s *baseExecutor) release() {
+	s.closeRows()
 	if s.dbTx != nil {
 		if err := s.dbTx.Rollback(); err != nil && err != sql.ErrTxDone {
 			slog.Warn("Failed to rollback transaction", "error", err.Error())
 		}
-	}
-	if s.dbHandle != nil && s.ownDBHandle {
-		if err := s.dbHandle.Close(); err != nil {
-			slog.Warn("Failed to close database handle", "error", err.Error())
-		}
+		s.dbTx = nil
 	}
 }
 
-func (s *HostExecutor) BeginTransaction() (err *cd.Error) {
+func (s *baseExecutor) BeginTransaction() (err *cd.Error) {
 	defer func() {
 		database.RecordDatabaseTransaction(database.DatabasePostgreSQL, "begin", err == nil)
 	}()
 
 	atomic.AddInt32(&s.dbTxCount, 1)
 	if s.dbTx == nil && s.dbTxCount == 1 {
-		if s.rowsHandle != nil {
-			_ = s.rowsHandle.Close()
-		}
-		s.rowsHandle = nil
+		s.closeRows()
 
-		var tx *sql.Tx
-		var txErr error
-		if s.executeContetxt != nil {
-			tx, txErr = s.dbHandle.BeginTx(s.executeContetxt, nil)
-		} else {
-			tx, txErr = s.dbHandle.Begin()
-		}
+		// 事务的生命周期由executeContetxt控制，不受单条SQL超时限制
+		tx, txErr := s.beginTx(s.getContext(), nil)
 		if txErr != nil {
-			err = cd.NewError(cd.Unexpected, txErr.Error())
+			err = database.NewExecuteError(s.getContext(), txErr)
 			slog.Error("BeginTransaction failed", "value", "s.dbHandle.Begin", "error", err.Error())
 			return
 		}
@@ -490,17 +351,19 @@ func (s *HostExecutor) BeginTransaction() (err *cd.Error) {
 	return
 }
 
-func (s *HostExecutor) CommitTransaction() (err *cd.Error) {
+func (s *baseExecutor) CommitTransaction() (err *cd.Error) {
 	defer func() {
 		database.RecordDatabaseTransaction(database.DatabasePostgreSQL, "commit", err == nil)
 	}()
 
 	atomic.AddInt32(&s.dbTxCount, -1)
 	if s.dbTx != nil && s.dbTxCount == 0 {
+		s.closeRows()
+
 		dbErr := s.dbTx.Commit()
 		if dbErr != nil {
 			s.dbTx = nil
-			err = cd.NewError(cd.Unexpected, dbErr.Error())
+			err = database.NewExecuteError(s.getContext(), dbErr)
 			slog.Error("CommitTransaction failed", "value", "s.dbTx.Commit", "error", err.Error())
 			return
 		}
@@ -512,15 +375,17 @@ func (s *HostExecutor) CommitTransaction() (err *cd.Error) {
 	return
 }
 
-func (s *HostExecutor) RollbackTransaction() (err *cd.Error) {
+func (s *baseExecutor) RollbackTransaction() (err *cd.Error) {
 	defer func() {
 		database.RecordDatabaseTransaction(database.DatabasePostgreSQL, "rollback", err == nil)
 	}()
 
 	atomic.AddInt32(&s.dbTxCount, -1)
 	if s.dbTx != nil && s.dbTxCount == 0 {
+		s.closeRows()
+
 		dbErr := s.dbTx.Rollback()
-		if dbErr != nil {
+		if dbErr != nil && dbErr != sql.ErrTxDone {
 			s.dbTx = nil
 			err = cd.NewError(cd.Unexpected, dbErr.Error())
 			slog.Error("RollbackTransaction failed", "value", "s.dbTx.Rollback", "error", err.Error())
@@ -534,7 +399,7 @@ func (s *HostExecutor) RollbackTransaction() (err *cd.Error) {
 	return
 }
 
-func (s *HostExecutor) Query(sql string, needCols bool, args ...any) (ret []string, err *cd.Error) {
+func (s *baseExecutor) Query(sql string, needCols bool, args ...any) (ret []string, err *cd.Error) {
 	//slog.Info("message")
 	startTime := time.Now()
 	defer func() {
@@ -551,81 +416,51 @@ func (s *HostExecutor) Query(sql string, needCols bool, args ...any) (ret []stri
 		}
 	}()
 
-	if s.dbTx == nil {
-		if s.dbHandle == nil {
-			panic("dbHandle is nil")
-		}
-		if s.rowsHandle != nil {
-			_ = s.rowsHandle.Close()
-			s.rowsHandle = nil
-		}
+	s.closeRows()
 
-		if s.executeContetxt != nil {
-			rows, rowErr := s.dbHandle.QueryContext(s.executeContetxt, sql, args...)
-			if rowErr != nil {
-				err = cd.NewError(cd.Unexpected, rowErr.Error())
-				slog.Error("Query failed", "sql", sql, "args", args, "error", rowErr.Error())
-				return
-			}
-			if needCols {
-				cols, colsErr := rows.Columns()
-				if colsErr != nil {
-					err = cd.NewError(cd.Unexpected, colsErr.Error())
-					slog.Error("Query failed", "sql", sql, "operation", "rows.Columns", "error", colsErr.Error())
-					return
-				}
-
-				ret = cols
-			}
-			s.rowsHandle = rows
-			return
-		}
-		rows, rowErr := s.dbHandle.Query(sql, args...)
+	// rows未关闭前context需要保持有效，由closeRows负责释放
+	var ctx context.Context
+	var cancel context.CancelFunc
+	queryFunc := func() (rowErr error) {
+		ctx, cancel = s.statementContext()
+		s.rowsHandle, rowErr = s.currentHandle().QueryContext(ctx, sql, args...)
 		if rowErr != nil {
-			err = cd.NewError(cd.Unexpected, rowErr.Error())
-			slog.Error("Query failed", "sql", sql, "args", args, "error", rowErr.Error())
-			return
+			cancel()
+			s.renewConnection(rowErr)
 		}
-		if needCols {
-			cols, colsErr := rows.Columns()
-			if colsErr != nil {
-				err = cd.NewError(cd.Unexpected, colsErr.Error())
-				slog.Error("Query failed", "sql", sql, "operation", "rows.Columns", "error", colsErr.Error())
-				return
-			}
+		return
+	}
 
-			ret = cols
-		}
-		s.rowsHandle = rows
+	var rowErr error
+	if s.dbTx == nil && database.IsReadStatement(sql) {
+		rowErr = database.Retry(s.getContext(), s.retryPolicy, database.DatabasePostgreSQL, "query", isTransientError, queryFunc)
 	} else {
-		if s.rowsHandle != nil {
-			_ = s.rowsHandle.Close()
-			s.rowsHandle = nil
-		}
-
-		rows, rowErr := s.dbTx.Query(sql, args...)
-		if rowErr != nil {
-			err = cd.NewError(cd.Unexpected, rowErr.Error())
-			slog.Error("Query failed", "sql", sql, "operation", "s.dbTx.Query", "error", rowErr.Error())
+		rowErr = queryFunc()
+	}
+	if rowErr != nil {
+		err = database.NewExecuteError(ctx, rowErr)
+		slog.Error("Query failed", "sql", sql, "args", args, "error", rowErr.Error())
+		return
+	}
+	rows := s.rowsHandle
+	s.rowsCancel = cancel
+
+	if needCols {
+		cols, colsErr := rows.Columns()
+		if colsErr != nil {
+			err = database.NewExecuteError(ctx, colsErr)
+			s.closeRows()
+			slog.Error("Query failed", "sql", sql, "operation", "rows.Columns", "error", colsErr.Error())
 			return
 		}
-		if needCols {
-			cols, colsErr := rows.Columns()
-			if colsErr != nil {
-				err = cd.NewError(cd.Unexpected, colsErr.Error())
-				slog.Error("Query failed", "sql", sql, "operation", "rows.Columns", "error", colsErr.Error())
-				return
-			}
 
-			ret = cols
-		}
-		s.rowsHandle = rows
+		ret = cols
 	}
 
 	return
 }
 
-func (s *HostExecutor) Next() bool {
+func (s *baseExecutor) Next() bool {
 	if s.rowsHandle == nil {
 		panic("rowsHandle is nil")
 	}
@@ -633,35 +468,34 @@ func (s *HostExecutor) Next() bool {
 	ret := s.rowsHandle.Next()
 	if !ret {
 		//log.Print("Next, close rows")
-		_ = s.rowsHandle.Close()
-		s.rowsHandle = nil
+		if rowsErr := s.rowsHandle.Err(); rowsErr != nil {
+			slog.Error("Next failed", "value", "s.rowsHandle.Err", "error", rowsErr.Error())
+		}
+		s.closeRows()
 	}
 
 	return ret
 }
 
-func (s *HostExecutor) Finish() {
-	if s.rowsHandle != nil {
-		_ = s.rowsHandle.Close()
-		s.rowsHandle = nil
-	}
+func (s *baseExecutor) Finish() {
+	s.closeRows()
 }
 
-func (s *HostExecutor) GetField(value ...any) (err *cd.Error) {
+func (s *baseExecutor) GetField(value ...any) (err *cd.Error) {
 	if s.rowsHandle == nil {
 		panic("rowsHandle is nil")
 	}
 
 	dbErr := s.rowsHandle.Scan(value...)
 	if dbErr != nil {
-		err = cd.NewError(cd.Unexpected, dbErr.Error())
+		err = database.NewExecuteError(s.getContext(), dbErr)
 		slog.Error("GetField failed", "value", "s.rowsHandle.Scan", "error", err.Error())
 	}
 
 	return
 }
 
-func (s *HostExecutor) Execute(sql string, args ...any) (rowsAffected int64, err *cd.Error) {
+func (s *baseExecutor) Execute(sql string, args ...any) (rowsAffected int64, err *cd.Error) {
 	startTime := time.Now()
 	defer func() {
 		endTime := time.Now()
@@ -677,41 +511,16 @@ func (s *HostExecutor) Execute(sql string, args ...any) (rowsAffected int64, err
 		}
 	}()
 
-	if s.rowsHandle != nil {
-		_ = s.rowsHandle.Close()
-	}
-	s.rowsHandle = nil
-
-	if s.dbTx == nil {
-		if s.dbHandle == nil {
-			panic("dbHandle is nil")
-		}
+	handle := s.currentHandle()
+	s.closeRows()
 
-		if s.executeContetxt != nil {
-			result, resultErr := s.dbHandle.ExecContext(s.executeContetxt, sql, args...)
-			if resultErr != nil {
-				err = cd.NewError(cd.Unexpected, resultErr.Error())
-				slog.Error("Execute failed", "value", "s.dbHandle.Exec", "error", resultErr.Error())
-				return
-			}
+	ctx, cancel := s.statementContext()
+	defer cancel()
 
-			rowsAffected, _ = result.RowsAffected()
-			return
-		}
-		result, resultErr := s.dbHandle.Exec(sql, args...)
-		if resultErr != nil {
-			err = cd.NewError(cd.Unexpected, resultErr.Error())
-			slog.Error("Execute failed", "value", "s.dbHandle.Exec", "error", resultErr.Error())
-			return
-		}
-		rowsAffected, _ = result.RowsAffected()
-		return
-	}
-
-	result, resultErr := s.dbTx.Exec(sql, args...)
+	result, resultErr := handle.ExecContext(ctx, sql, args...)
 	if resultErr != nil {
-		err = cd.NewError(cd.Unexpected, resultErr.Error())
-		slog.Error("Execute failed", "value", "s.dbTx.Exec", "error", resultErr.Error())
+		err = database.NewExecuteError(ctx, resultErr)
+		slog.Error("Execute failed", "value", "handle.ExecContext", "error", resultErr.Error())
 		return
 	}
 
@@ -719,7 +528,7 @@ func (s *HostExecutor) Execute(sql string, args ...any) (rowsAffected int64, err
 	return
 }
 
-func (s *HostExecutor) ExecuteInsert(sql string, pkValOut any, args ...any) (err *cd.Error) {
+func (s *baseExecutor) ExecuteInsert(sql string, pkValOut any, args ...any) (err *cd.Error) {
 	startTime := time.Now()
 	defer func() {
 		endTime := time.Now()
@@ -735,65 +544,103 @@ func (s *HostExecutor) ExecuteInsert(sql string, pkValOut any, args ...any) (err
 		}
 	}()
 
-	if s.rowsHandle != nil {
-		_ = s.rowsHandle.Close()
+	handle := s.currentHandle()
+	s.closeRows()
+
+	ctx, cancel := s.statementContext()
+	defer cancel()
+
+	rowPtr := handle.QueryRowContext(ctx, sql, args...)
+	if qErr := rowPtr.Err(); qErr != nil {
+		err = database.NewExecuteError(ctx, qErr)
+		slog.Error("ExecuteInsert failed", "value", "rowPtr.Err", "error", qErr.Error())
+		return
 	}
-	s.rowsHandle = nil
 
-	if s.dbTx == nil {
-		if s.dbHandle == nil {
-			panic("dbHandle is nil")
+	if rErr := rowPtr.Scan(pkValOut); rErr != nil {
+		err = database.NewExecuteError(ctx, rErr)
+		slog.Error("ExecuteInsert failed", "value", "rowPtr.Scan", "error", rErr.Error())
+		return
+	}
+
+	return
+}
+
+// CopyIn 使用COPY FROM STDIN导入数据，未开启事务时在独立事务中执行
+//
+// 整个导入过程为一条语句，受statementTimeout限制
+func (s *baseExecutor) CopyIn(tableName string, columns []string, source database.RowSource) (ret int64, err *cd.Error) {
+	copySQL := pq.CopyIn(tableName, columns...)
+	startTime := time.Now()
+	defer func() {
+		elapse := time.Since(startTime)
+		database.RecordDatabaseExecution(database.DatabasePostgreSQL, copySQL, err == nil)
+		if err != nil {
+			slog.Error("CopyIn failed", "execute_time", startTime.Local().String(), "elapse", elapse, "sql", copySQL, "rows", ret, "error", err.Error())
+			return
 		}
 
-		if s.executeContetxt != nil {
-			rowPtr := s.dbHandle.QueryRowContext(s.executeContetxt, sql, args...)
-			if qErr := rowPtr.Err(); qErr != nil {
-				err = cd.NewError(cd.Unexpected, qErr.Error())
-				slog.Error("ExecuteInsert failed", "value", "rowPtr.Err", "error", qErr.Error())
-				return
-			}
+		if traceSQL() {
+			slog.Info("CopyIn ok", "execute_time", startTime.Local().String(), "elapse", elapse, "sql", copySQL, "rows", ret)
+		}
+	}()
 
-			if rErr := rowPtr.Scan(pkValOut); rErr != nil {
-				err = cd.NewError(cd.Unexpected, rErr.Error())
-				slog.Error("ExecuteInsert failed", "value", "rowPtr.Scan", "error", rErr.Error())
+	s.closeRows()
+	ownTx := s.dbTx == nil
+	if ownTx {
+		if err = s.BeginTransaction(); err != nil {
+			return
+		}
+		defer func() {
+			if err != nil {
+				_ = s.RollbackTransaction()
 				return
 			}
+			err = s.CommitTransaction()
+		}()
+	}
+
+	ctx, cancel := s.statementContext()
+	defer cancel()
+
+	stmt, stmtErr := s.dbTx.PrepareContext(ctx, copySQL)
+	if stmtErr != nil {
+		err = database.NewExecuteError(ctx, stmtErr)
+		return
+	}
+	defer stmt.Close()
 
+	var rowCount int64
+	for {
+		values, ok, valueErr := source.Next()
+		if valueErr != nil {
+			err = valueErr
 			return
 		}
-		rowPtr := s.dbHandle.QueryRow(sql, args...)
-		if qErr := rowPtr.Err(); qErr != nil {
-			err = cd.NewError(cd.Unexpected, qErr.Error())
-			slog.Error("ExecuteInsert failed", "value", "rowPtr.Err", "error", qErr.Error())
-			return
+		if !ok {
+			break
 		}
-		if rErr := rowPtr.Scan(pkValOut); rErr != nil {
-			err = cd.NewError(cd.Unexpected, rErr.Error())
-			slog.Error("ExecuteInsert failed", "value", "rowPtr.Scan", "error", rErr.Error())
+
+		if _, execErr := stmt.ExecContext(ctx, values...); execErr != nil {
+			err = database.NewExecuteError(ctx, execErr)
 			return
 		}
-		return
+		rowCount++
 	}
 
-	rowPtr := s.dbTx.QueryRow(sql, args...)
-	if qErr := rowPtr.Err(); qErr != nil {
-		err = cd.NewError(cd.Unexpected, qErr.Error())
-		slog.Error("ExecuteInsert failed", "value", "rowPtr.Err", "error", qErr.Error())
-		return
-	}
-
-	if rErr := rowPtr.Scan(pkValOut); rErr != nil {
-		err = cd.NewError(cd.Unexpected, rErr.Error())
-		slog.Error("ExecuteInsert failed", "value", "rowPtr.Scan", "error", rErr.Error())
+	// 不带参数的Exec结束COPY并提交缓冲的数据
+	if _, execErr := stmt.ExecContext(ctx); execErr != nil {
+		err = database.NewExecuteError(ctx, execErr)
 		return
 	}
 
+	ret = rowCount
 	return
 }
 
 // CheckTableExist Check Table Exist
-func (s *HostExecutor) CheckTableExist(tableName string) (ret bool, err *cd.Error) {
-	strSQL := "SELECT tablename FROM pg_tables WHERE tablename = $1 AND schemaname = 'public'"
+func (s *baseExecutor) CheckTableExist(tableName string) (ret bool, err *cd.Error) {
+	strSQL := "SELECT tablename FROM pg_tables WHERE tablename = $1 AND schemaname = current_schema()"
 	_, err = s.Query(strSQL, false, tableName)
 	if err != nil {
 		slog.Error("CheckTableExist failed", "value", "s.Query", "error", err.Error())
@@ -809,11 +656,157 @@ func (s *HostExecutor) CheckTableExist(tableName string) (ret bool, err *cd.Erro
 	return
 }
 
+// ConnExecutor 从连接池中获取的单个连接
+type ConnExecutor struct {
+	baseExecutor
+	dbConnPtr  *sql.Conn
+	poolHandle *sql.DB
+	// releaseFunc Release时通知连接池，只调用一次
+	releaseFunc func()
+	// schema 通过SwitchSchema切换的schema，为空表示使用连接配置的schema
+	schema string
+}
+
+func newConnExecutor(ctx context.Context, poolHandle *sql.DB, connPtr *sql.Conn, retryPolicy *database.RetryPolicy) *ConnExecutor {
+	ret := &ConnExecutor{
+		baseExecutor: baseExecutor{
+			executeContetxt: ctx,
+			connHandle:      connPtr,
+			beginTx:         connPtr.BeginTx,
+			retryPolicy:     retryPolicy,
+		},
+		dbConnPtr:  connPtr,
+		poolHandle: poolHandle,
+	}
+	ret.reconnect = ret.renewConn
+	return ret
+}
+
+// renewConn 从连接池重新获取连接替换当前失效的连接
+func (s *ConnExecutor) renewConn(ctx context.Context) error {
+	if s.poolHandle == nil {
+		return nil
+	}
+
+	connPtr, connErr := s.poolHandle.Conn(ctx)
+	if connErr != nil {
+		return connErr
+	}
+	if s.schema != "" {
+		if schemaErr := s.applySchema(ctx, connPtr, s.schema); schemaErr != nil {
+			_ = connPtr.Close()
+			return schemaErr
+		}
+	}
+
+	if s.dbConnPtr != nil {
+		_ = s.dbConnPtr.Close()
+	}
+	s.dbConnPtr = connPtr
+	s.connHandle = connPtr
+	s.beginTx = connPtr.BeginTx
+	return nil
+}
+
+func (s *ConnExecutor) Release() {
+	s.release()
+	if s.dbConnPtr != nil && s.schema != "" {
+		s.restoreSchema()
+	}
+	if s.dbConnPtr != nil {
+		if err := s.dbConnPtr.Close(); err != nil {
+			slog.Warn("Failed to close database connection", "error", err.Error())
+		}
+		s.dbConnPtr = nil
+	}
+	if s.releaseFunc != nil {
+		s.releaseFunc()
+		s.releaseFunc = nil
+	}
+}
+
+// SwitchSchema 切换当前连接使用的schema，不能在事务中切换
+func (s *ConnExecutor) SwitchSchema(schema string) (err *cd.Error) {
+	err = database.ValidateSchemaName(schema)
+	if err != nil {
+		return
+	}
+	if s.dbTx != nil {
+		err = cd.NewError(cd.Unexpected, "can't switch schema in transaction")
+		return
+	}
+	if schema == s.schema {
+		return
+	}
+
+	s.closeRows()
+	ctx, cancel := s.statementContext()
+	defer cancel()
+	if schemaErr := s.applySchema(ctx, s.dbConnPtr, schema); schemaErr != nil {
+		err = database.NewExecuteError(ctx, schemaErr)
+		slog.Error("SwitchSchema failed", "schema", schema, "error", err.Error())
+		return
+	}
+
+	s.schema = schema
+	return
+}
+
+// applySchema 设置连接的search_path，schema为空时恢复为连接参数中的search_path
+func (s *ConnExecutor) applySchema(ctx context.Context, connPtr *sql.Conn, schema string) error {
+	sqlText := "RESET search_path"
+	if schema != "" {
+		sqlText = fmt.Sprintf("SET search_path TO \"%s\"", schema)
+	}
+	_, execErr := connPtr.ExecContext(ctx, sqlText)
+	return execErr
+}
+
+// restoreSchema 归还连接前恢复schema，恢复失败时丢弃该连接，避免其他请求使用到错误的schema
+func (s *ConnExecutor) restoreSchema() {
+	if schemaErr := s.applySchema(context.Background(), s.dbConnPtr, ""); schemaErr != nil {
+		slog.Warn("Failed to restore connection schema, discard connection", "schema", s.schema, "error", schemaErr.Error())
+		_ = s.dbConnPtr.Raw(func(any) error {
+			return driver.ErrBadConn
+		})
+	}
+	s.schema = ""
+}
+
+// HostExecutor 直接持有数据库句柄
+type HostExecutor struct {
+	baseExecutor
+	dbHandle    *sql.DB
+	ownDBHandle bool
+}
+
+// SwitchSchema HostExecutor的SQL可能在不同的连接上执行，不支持切换schema
+func (s *HostExecutor) SwitchSchema(schema string) *cd.Error {
+	if schema == "" {
+		return nil
+	}
+
+	return cd.NewError(cd.Unexpected, "switch schema is only supported by executor acquired from pool")
+}
+
+func (s *HostExecutor) Release() {
+	s.release()
+	if s.dbHandle != nil && s.ownDBHandle {
+		if err := s.dbHandle.Close(); err != nil {
+			slog.Warn("Failed to close database handle", "error", err.Error())
+		}
+	}
+}
+
 // Pool executorPool
 type Pool struct {
-	dbDSN          string
+	mu             sync.RWMutex
+	config         database.Config
 	dbHandle       *sql.DB
+	maxConnNum     int
+	tracker        *database.ExecutorTracker
 	referenceCount int
+	retryPolicy    *database.RetryPolicy
 }
 
 // NewPool new pool
@@ -823,70 +816,118 @@ func NewPool() *Pool {
 
 // Initialize initialize executor pool
 func (s *Pool) Initialize(maxConnNum int, config database.Config) (err *cd.Error) {
-	if err = s.connect(config.GetDsn(), maxConnNum); err != nil {
+	dbHandle, dbErr := s.connect(config, maxConnNum)
+	if dbErr != nil {
+		err = dbErr
 		return
 	}
 
+	s.mu.Lock()
+	s.config = config
+	s.dbHandle = dbHandle
+	s.maxConnNum = maxConnNum
+	s.tracker = database.NewExecutorTracker()
+	s.mu.Unlock()
+	database.UpdateDatabaseConnectionStats(database.DatabasePostgreSQL, dbHandle)
 	return
 }
 
-func (s *Pool) connect(dsn string, maxConnNum int) (err *cd.Error) {
-	dbHandle, dbErr := sql.Open("postgres", dsn)
-	if dbErr != nil {
-		err = cd.NewError(cd.Unexpected, dbErr.Error())
-		slog.Error("Pool connect open database exception", "dsn", dsn, "error", err.Error())
+func (s *Pool) connect(config database.Config, maxConnNum int) (ret *sql.DB, err *cd.Error) {
+	dbHandle, openErr := openDatabase(config)
+	if openErr != nil {
+		err = openErr
+		slog.Error("Pool connect open database exception", "server", config.Server(), "error", err.Error())
 		return
 	}
 
 	dbHandle.SetMaxOpenConns(maxConnNum)
 
-	//log.Print("open database connection...")
-	s.dbHandle = dbHandle
-
-	dbErr = dbHandle.Ping()
+	dbErr := database.Retry(context.Background(), s.getRetryPolicy(), database.DatabasePostgreSQL, "connect", isTransientError, dbHandle.Ping)
 	if dbErr != nil {
+		_ = dbHandle.Close()
 		err = cd.NewError(cd.Unexpected, dbErr.Error())
-		slog.Error("Pool connect ping database failed", "dsn", dsn, "error", err.Error())
+		slog.Error("Pool connect ping database failed", "server", config.Server(), "error", err.Error())
 		return
 	}
 
-	s.dbDSN = dsn
-	s.dbHandle = dbHandle
-	database.UpdateDatabaseConnectionStats(database.DatabasePostgreSQL, s.dbHandle)
+	ret = dbHandle
 	return
 }
 
 // Uninitialized uninitialized executor pool
 func (s *Pool) Uninitialized() {
-	if s.dbHandle != nil {
-		database.UpdateDatabaseConnectionStats(database.DatabasePostgreSQL, s.dbHandle)
-		_ = s.dbHandle.Close()
-		s.dbHandle = nil
+	s.mu.Lock()
+	dbHandle := s.dbHandle
+	s.dbHandle = nil
+	s.mu.Unlock()
+
+	if dbHandle != nil {
+		database.UpdateDatabaseConnectionStats(database.DatabasePostgreSQL, dbHandle)
+		_ = dbHandle.Close()
 	}
 }
 
 func (s *Pool) GetExecutor(ctx context.Context) (ret database.Executor, err *cd.Error) {
-	connPtr, connErr := s.dbHandle.Conn(ctx)
+	s.mu.RLock()
+	dbHandle := s.dbHandle
+	tracker := s.tracker
+	retryPolicy := s.retryPolicy
+	s.mu.RUnlock()
+	if dbHandle == nil || tracker == nil {
+		err = cd.NewError(cd.Unexpected, "database pool is not initialized")
+		return
+	}
+
+	err = tracker.Acquire()
+	if err != nil {
+		return
+	}
+
+	var connPtr *sql.Conn
+	connErr := database.Retry(ctx, retryPolicy, database.DatabasePostgreSQL, "acquire", isTransientError, func() (connErr error) {
+		connPtr, connErr = dbHandle.Conn(ctx)
+		return
+	})
 	if connErr != nil {
+		tracker.Done()
 		err = cd.NewError(cd.DatabaseError, connErr.Error())
 		return
 	}
 
-	database.UpdateDatabaseConnectionStats(database.DatabasePostgreSQL, s.dbHandle)
+	database.UpdateDatabaseConnectionStats(database.DatabasePostgreSQL, dbHandle)
 
-	ret = &ConnExecutor{
-		executeContetxt: ctx,
-		dbConnPtr:       connPtr,
-	}
+	executorPtr := newConnExecutor(ctx, dbHandle, connPtr, retryPolicy)
+	executorPtr.releaseFunc = tracker.Done
+	ret = executorPtr
 	return
 }
 
+// SetRetryPolicy 设置获取连接与事务外只读查询的重试策略，policy为nil表示不重试
+func (s *Pool) SetRetryPolicy(policy *database.RetryPolicy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.retryPolicy = policy
+}
+
+func (s *Pool) getRetryPolicy() *database.RetryPolicy {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.retryPolicy
+}
+
 func (s *Pool) GetStats() database.PoolStats {
-	if s == nil || s.dbHandle == nil {
+	if s == nil {
 		return database.PoolStats{}
 	}
 
-	stats := s.dbHandle.Stats()
+	s.mu.RLock()
+	dbHandle := s.dbHandle
+	s.mu.RUnlock()
+	if dbHandle == nil {
+		return database.PoolStats{}
+	}
+
+	stats := dbHandle.Stats()
 	return database.PoolStats{
 		MaxOpenConnections: stats.MaxOpenConnections,
 		OpenConnections:    stats.OpenConnections,
@@ -897,9 +938,115 @@ func (s *Pool) GetStats() database.PoolStats {
 	}
 }
 
+// HealthCheck Ping数据库并返回连接池状态
+func (s *Pool) HealthCheck(ctx context.Context) (ret database.HealthStatus) {
+	s.mu.RLock()
+	dbHandle := s.dbHandle
+	tracker := s.tracker
+	s.mu.RUnlock()
+
+	ret.Stats = s.GetStats()
+	if tracker != nil {
+		ret.Draining = tracker.Draining()
+		ret.InUseExecutors = tracker.InUse()
+	}
+	if dbHandle == nil {
+		ret.Error = "database pool is not initialized"
+		return
+	}
+
+	startTime := time.Now()
+	pingErr := dbHandle.PingContext(ctx)
+	ret.Latency = time.Since(startTime)
+	if pingErr != nil {
+		ret.Error = pingErr.Error()
+		return
+	}
+
+	ret.Healthy = !ret.Draining
+	return
+}
+
+// Drain 停止分配Executor并等待已借出的Executor全部Release，不关闭数据库连接
+func (s *Pool) Drain(timeout time.Duration) *cd.Error {
+	s.mu.RLock()
+	tracker := s.tracker
+	s.mu.RUnlock()
+	if tracker == nil {
+		return cd.NewError(cd.Unexpected, "database pool is not initialized")
+	}
+
+	return tracker.Drain(timeout)
+}
+
+// Replace 使用新配置建立连接并替换当前连接，之后的GetExecutor使用新连接；
+// 旧连接在已借出的Executor全部Release或者drainTimeout超时后关闭
+func (s *Pool) Replace(config database.Config, drainTimeout time.Duration) *cd.Error {
+	if cfgPtr, ok := config.(*Config); ok {
+		if err := cfgPtr.Validate(); err != nil {
+			return err
+		}
+	}
+
+	s.mu.RLock()
+	maxConnNum := s.maxConnNum
+	s.mu.RUnlock()
+
+	dbHandle, dbErr := s.connect(config, maxConnNum)
+	if dbErr != nil {
+		return dbErr
+	}
+
+	s.mu.Lock()
+	oldHandle := s.dbHandle
+	oldTracker := s.tracker
+	s.config = config
+	s.dbHandle = dbHandle
+	s.tracker = database.NewExecutorTracker()
+	s.mu.Unlock()
+	database.UpdateDatabaseConnectionStats(database.DatabasePostgreSQL, dbHandle)
+
+	if oldHandle != nil {
+		go retireDatabase(config.Server(), oldHandle, oldTracker, drainTimeout)
+	}
+	return nil
+}
+
+func retireDatabase(server string, dbHandle *sql.DB, tracker *database.ExecutorTracker, drainTimeout time.Duration) {
+	if tracker != nil {
+		if err := tracker.Drain(drainTimeout); err != nil {
+			slog.Warn("Pool retire database drain timeout", "server", server, "in_use", tracker.InUse(), "error", err.Error())
+		}
+	}
+
+	_ = dbHandle.Close()
+}
+
 func (s *Pool) CheckConfig(cfgPtr database.Config) *cd.Error {
-	newDsn := cfgPtr.GetDsn()
-	if newDsn == s.dbDSN {
+	newCfg, newOK := cfgPtr.(*Config)
+	if newOK {
+		if err := newCfg.Validate(); err != nil {
+			return err
+		}
+	}
+
+	s.mu.RLock()
+	curConfig := s.config
+	s.mu.RUnlock()
+	if curConfig == nil {
+		return cd.NewError(cd.Unexpected, "database pool is not initialized")
+	}
+
+	preCfg, preOK := curConfig.(*Config)
+	if newOK && preOK {
+		if newCfg.Same(preCfg) {
+			return nil
+		}
+
+		return cd.NewError(cd.Unexpected, "mismatch database config")
+	}
+
+	if cfgPtr.GetDsn() == curConfig.GetDsn() {
 		return nil
 	}
 
@@ -907,11 +1054,15 @@ func (s *Pool) CheckConfig(cfgPtr database.Config) *cd.Error {
 }
 
 func (s *Pool) IncReference() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.referenceCount++
 	return s.referenceCount
 }
 
 func (s *Pool) DecReference() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.referenceCount--
 	if s.referenceCount < 0 {
 		s.referenceCount = 0
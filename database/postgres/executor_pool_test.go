@@ -0,0 +1,132 @@
+//go:build !mysql
+// +build !mysql
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	cd "github.com/muidea/magicCommon/def"
+	"github.com/muidea/magicOrm/database"
+)
+
+func newTestPool(t *testing.T) *Pool {
+	t.Helper()
+
+	dbHandle, dbErr := sql.Open("magicorm_flaky", "")
+	if dbErr != nil {
+		t.Fatalf("open flaky driver failed: %v", dbErr)
+	}
+	t.Cleanup(func() { _ = dbHandle.Close() })
+
+	return &Pool{dbHandle: dbHandle, maxConnNum: 2, tracker: database.NewExecutorTracker()}
+}
+
+func TestPoolDrain(t *testing.T) {
+	testFlakyDriver.failTimes = 0
+	pool := newTestPool(t)
+
+	executor, err := pool.GetExecutor(context.Background())
+	if err != nil {
+		t.Fatalf("GetExecutor failed: %v", err)
+	}
+
+	status := pool.HealthCheck(context.Background())
+	if !status.Healthy || status.InUseExecutors != 1 {
+		t.Fatalf("unexpected health status: %+v", status)
+	}
+
+	if err = pool.Drain(20 * time.Millisecond); err == nil || err.Code != cd.Timeout {
+		t.Fatalf("expected drain timeout, got %v", err)
+	}
+
+	if _, err = pool.GetExecutor(context.Background()); err == nil || err.Code != cd.ServiceUnavailable {
+		t.Fatalf("expected draining pool to reject executor, got %v", err)
+	}
+
+	status = pool.HealthCheck(context.Background())
+	if status.Healthy || !status.Draining {
+		t.Fatalf("unexpected health status while draining: %+v", status)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		executor.Release()
+		// 重复Release不影响计数
+		executor.Release()
+	}()
+	if err = pool.Drain(time.Second); err != nil {
+		t.Fatalf("expected drain to finish, got %v", err)
+	}
+	if status = pool.HealthCheck(context.Background()); status.InUseExecutors != 0 {
+		t.Fatalf("unexpected in use executors: %+v", status)
+	}
+}
+
+func TestPoolHealthCheckUninitialized(t *testing.T) {
+	pool := NewPool()
+	status := pool.HealthCheck(context.Background())
+	if status.Healthy || status.Error == "" {
+		t.Fatalf("unexpected health status: %+v", status)
+	}
+
+	if _, err := pool.GetExecutor(context.Background()); err == nil {
+		t.Fatal("expected uninitialized pool to reject executor")
+	}
+}
+
+func TestConnExecutorSwitchSchema(t *testing.T) {
+	testFlakyDriver.failTimes = 0
+	testFlakyDriver.execCalls = 0
+	testFlakyDriver.execSQLs = nil
+	pool := newTestPool(t)
+
+	executor, err := pool.GetExecutor(context.Background())
+	if err != nil {
+		t.Fatalf("GetExecutor failed: %v", err)
+	}
+
+	if err = executor.SwitchSchema("tenant-a"); err == nil || err.Code != cd.IllegalParam {
+		t.Fatalf("expected illegal schema name, got %v", err)
+	}
+	if err = executor.SwitchSchema("tenant_a"); err != nil {
+		t.Fatalf("SwitchSchema failed: %v", err)
+	}
+	// 相同schema不重复设置
+	if err = executor.SwitchSchema("tenant_a"); err != nil {
+		t.Fatalf("SwitchSchema failed: %v", err)
+	}
+
+	if err = executor.BeginTransaction(); err != nil {
+		t.Fatalf("BeginTransaction failed: %v", err)
+	}
+	if err = executor.SwitchSchema("tenant_b"); err == nil {
+		t.Fatal("expected switch schema in transaction to fail")
+	}
+	_ = executor.RollbackTransaction()
+
+	executor.Release()
+
+	expectSQLs := []string{`SET search_path TO "tenant_a"`, "RESET search_path"}
+	if len(testFlakyDriver.execSQLs) != len(expectSQLs) {
+		t.Fatalf("unexpected exec sql: %v", testFlakyDriver.execSQLs)
+	}
+	for idx, sqlText := range expectSQLs {
+		if testFlakyDriver.execSQLs[idx] != sqlText {
+			t.Fatalf("unexpected exec sql: %v", testFlakyDriver.execSQLs)
+		}
+	}
+}
+
+func TestHostExecutorSwitchSchema(t *testing.T) {
+	executor := &HostExecutor{}
+	if err := executor.SwitchSchema(""); err != nil {
+		t.Fatalf("expected default schema to be accepted, got %v", err)
+	}
+	if err := executor.SwitchSchema("tenant_a"); err == nil {
+		t.Fatal("expected host executor to reject schema switching")
+	}
+}
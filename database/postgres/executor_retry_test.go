@@ -0,0 +1,110 @@
+//go:build !mysql
+// +build !mysql
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/muidea/magicOrm/database"
+)
+
+// flakyDriver 前failTimes次查询返回连接重置错误
+type flakyDriver struct {
+	failTimes  int
+	queryCalls int
+	execCalls  int
+	execSQLs   []string
+}
+
+func (d *flakyDriver) Open(string) (driver.Conn, error) { return &flakyConn{driver: d}, nil }
+
+type flakyConn struct {
+	driver *flakyDriver
+}
+
+func (c *flakyConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *flakyConn) Close() error                        { return nil }
+func (c *flakyConn) Begin() (driver.Tx, error)           { return blockingTx{}, nil }
+
+func (c *flakyConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.driver.execCalls++
+	c.driver.execSQLs = append(c.driver.execSQLs, query)
+	if c.driver.execCalls <= c.driver.failTimes {
+		return nil, syscall.ECONNRESET
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c *flakyConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	c.driver.queryCalls++
+	if c.driver.queryCalls <= c.driver.failTimes {
+		return nil, syscall.ECONNRESET
+	}
+	return &emptyRows{}, nil
+}
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string         { return []string{"id"} }
+func (emptyRows) Close() error              { return nil }
+func (emptyRows) Next([]driver.Value) error { return io.EOF }
+
+var testFlakyDriver = &flakyDriver{}
+
+func init() {
+	sql.Register("magicorm_flaky", testFlakyDriver)
+}
+
+func TestExecutorRetryIdempotentRead(t *testing.T) {
+	testFlakyDriver.failTimes = 2
+	testFlakyDriver.queryCalls = 0
+	testFlakyDriver.execCalls = 0
+
+	dbHandle, dbErr := sql.Open("magicorm_flaky", "")
+	if dbErr != nil {
+		t.Fatalf("open flaky driver failed: %v", dbErr)
+	}
+	defer dbHandle.Close()
+
+	connPtr, connErr := dbHandle.Conn(context.Background())
+	if connErr != nil {
+		t.Fatalf("get connection failed: %v", connErr)
+	}
+	executor := newConnExecutor(context.Background(), dbHandle, connPtr, &database.RetryPolicy{MaxRetries: 3, InitialBackoff: time.Millisecond})
+	defer executor.Release()
+
+	if _, err := executor.Query(`SELECT "id" FROM "demo"`, false); err != nil {
+		t.Fatalf("expected read to be retried, got %v", err)
+	}
+	executor.Finish()
+	if testFlakyDriver.queryCalls != 3 {
+		t.Fatalf("unexpected query calls: %d", testFlakyDriver.queryCalls)
+	}
+
+	if _, err := executor.Execute(`DELETE FROM "demo"`); err == nil {
+		t.Fatal("expected write not to be retried")
+	}
+	if testFlakyDriver.execCalls != 1 {
+		t.Fatalf("unexpected exec calls: %d", testFlakyDriver.execCalls)
+	}
+
+	testFlakyDriver.queryCalls = 0
+	if err := executor.BeginTransaction(); err != nil {
+		t.Fatalf("BeginTransaction failed: %v", err)
+	}
+	if _, err := executor.Query(`SELECT "id" FROM "demo"`, false); err == nil {
+		t.Fatal("expected read in transaction not to be retried")
+	}
+	_ = executor.RollbackTransaction()
+	if testFlakyDriver.queryCalls != 1 {
+		t.Fatalf("unexpected query calls in transaction: %d", testFlakyDriver.queryCalls)
+	}
+}
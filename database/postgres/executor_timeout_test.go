@@ -0,0 +1,115 @@
+//go:build !mysql
+// +build !mysql
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	cd "github.com/muidea/magicCommon/def"
+)
+
+// blockingDriver 所有语句阻塞直到context结束，用于验证语句超时
+type blockingDriver struct{}
+
+func (blockingDriver) Open(string) (driver.Conn, error) { return &blockingConn{}, nil }
+
+type blockingConn struct{}
+
+func (c *blockingConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *blockingConn) Close() error                        { return nil }
+func (c *blockingConn) Begin() (driver.Tx, error)           { return blockingTx{}, nil }
+
+func (c *blockingConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	return blockingTx{}, nil
+}
+
+func (c *blockingConn) ExecContext(ctx context.Context, _ string, _ []driver.NamedValue) (driver.Result, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (c *blockingConn) QueryContext(ctx context.Context, _ string, _ []driver.NamedValue) (driver.Rows, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+type blockingTx struct{}
+
+func (blockingTx) Commit() error   { return nil }
+func (blockingTx) Rollback() error { return nil }
+
+func init() {
+	sql.Register("magicorm_blocking", blockingDriver{})
+}
+
+func TestExecutorStatementTimeout(t *testing.T) {
+	dbHandle, dbErr := sql.Open("magicorm_blocking", "")
+	if dbErr != nil {
+		t.Fatalf("open blocking driver failed: %v", dbErr)
+	}
+	defer dbHandle.Close()
+
+	connPtr, connErr := dbHandle.Conn(context.Background())
+	if connErr != nil {
+		t.Fatalf("get connection failed: %v", connErr)
+	}
+
+	executor := &ConnExecutor{
+		baseExecutor: baseExecutor{
+			executeContetxt: context.Background(),
+			connHandle:      connPtr,
+			beginTx:         connPtr.BeginTx,
+		},
+		dbConnPtr: connPtr,
+	}
+	defer executor.Release()
+
+	executor.SetStatementTimeout(20 * time.Millisecond)
+	if _, err := executor.Execute("UPDATE demo SET name = $1", "a"); err == nil || err.Code != cd.Timeout {
+		t.Fatalf("expected execute timeout, got %v", err)
+	}
+
+	if err := executor.BeginTransaction(); err != nil {
+		t.Fatalf("BeginTransaction failed: %v", err)
+	}
+	if _, err := executor.Query("SELECT id FROM demo", false); err == nil || err.Code != cd.Timeout {
+		t.Fatalf("expected query timeout in transaction, got %v", err)
+	}
+	if _, err := executor.Execute("DELETE FROM demo"); err == nil || err.Code != cd.Timeout {
+		t.Fatalf("expected execute timeout in transaction, got %v", err)
+	}
+	if err := executor.RollbackTransaction(); err != nil {
+		t.Fatalf("RollbackTransaction failed: %v", err)
+	}
+}
+
+func TestExecutorContextCancel(t *testing.T) {
+	dbHandle, dbErr := sql.Open("magicorm_blocking", "")
+	if dbErr != nil {
+		t.Fatalf("open blocking driver failed: %v", dbErr)
+	}
+	defer dbHandle.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	executor := &HostExecutor{
+		baseExecutor: baseExecutor{
+			executeContetxt: ctx,
+			connHandle:      dbHandle,
+			beginTx:         dbHandle.BeginTx,
+		},
+		dbHandle: dbHandle,
+	}
+	defer executor.Release()
+
+	time.AfterFunc(20*time.Millisecond, cancel)
+	_, err := executor.Execute("UPDATE demo SET name = $1", "a")
+	if err == nil || err.Code != cd.Unexpected {
+		t.Fatalf("expected cancelled execute, got %v", err)
+	}
+}
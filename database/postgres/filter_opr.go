@@ -7,6 +7,9 @@ import (
 	"github.com/muidea/magicOrm/models"
 )
 
+// fullTextConfig 全文检索使用的文本检索配置，查询条件与GIN索引的表达式必须一致才能使用索引
+const fullTextConfig = "simple"
+
 type OprFunc func(string, any, *ResultStack) string
 
 func getOprFunc(filterItem models.FilterItem) (ret OprFunc) {
@@ -25,6 +28,8 @@ func getOprFunc(filterItem models.FilterItem) (ret OprFunc) {
 		return NotInOpr
 	case models.LikeOpr:
 		return LikeOpr
+	case models.MatchOpr:
+		return MatchOpr
 	}
 
 	return nil
@@ -100,6 +105,18 @@ func LikeOpr(name string, val any, resultStackPtr *ResultStack) string {
 	return fmt.Sprintf("\"%s\" LIKE $%d", name, len(resultStackPtr.argsVal))
 }
 
+// MatchOpr 全文检索，val为普通检索文本，由websearch_to_tsquery解析，任意输入都不会产生语法错误
+func MatchOpr(name string, val any, resultStackPtr *ResultStack) string {
+	resultStackPtr.PushArgs(val)
+	return fmt.Sprintf("to_tsvector('%s', \"%s\") @@ websearch_to_tsquery('%s', $%d)", fullTextConfig, name, fullTextConfig, len(resultStackPtr.argsVal))
+}
+
+// RelevanceOpr 全文检索的相关度
+func RelevanceOpr(name string, val any, resultStackPtr *ResultStack) string {
+	resultStackPtr.PushArgs(val)
+	return fmt.Sprintf("ts_rank(to_tsvector('%s', \"%s\"), websearch_to_tsquery('%s', $%d))", fullTextConfig, name, fullTextConfig, len(resultStackPtr.argsVal))
+}
+
 // SortOpr sort opr
 func SortOpr(name string, ascSort bool) string {
 	if ascSort {
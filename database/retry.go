@@ -0,0 +1,144 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultRetryInitialBackoff = 50 * time.Millisecond
+	defaultRetryMaxBackoff     = 2 * time.Second
+	defaultRetryMultiplier     = 2.0
+)
+
+// 重试结果，用于metricsdb计数
+const (
+	RetryOutcomeRetry     = "retry"
+	RetryOutcomeRecovered = "recovered"
+	RetryOutcomeExhausted = "exhausted"
+)
+
+// RetryPolicy 瞬时连接错误的重试策略
+//
+// 只作用于获取连接以及事务外的只读查询，事务内的语句以及写操作不会重试
+type RetryPolicy struct {
+	// MaxRetries 最大重试次数，<=0 表示不重试
+	MaxRetries int
+	// InitialBackoff 首次重试前的等待时间，默认50ms
+	InitialBackoff time.Duration
+	// MaxBackoff 单次等待时间上限，默认2s
+	MaxBackoff time.Duration
+	// Multiplier 每次重试等待时间的增长倍数，默认2
+	Multiplier float64
+}
+
+// Enabled 是否启用重试
+func (s *RetryPolicy) Enabled() bool {
+	return s != nil && s.MaxRetries > 0
+}
+
+// Backoff 第attempt次重试(从1开始)前的等待时间
+func (s *RetryPolicy) Backoff(attempt int) time.Duration {
+	initialBackoff := s.InitialBackoff
+	if initialBackoff <= 0 {
+		initialBackoff = defaultRetryInitialBackoff
+	}
+	maxBackoff := s.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultRetryMaxBackoff
+	}
+	multiplier := s.Multiplier
+	if multiplier < 1 {
+		multiplier = defaultRetryMultiplier
+	}
+
+	backoff := float64(initialBackoff)
+	for idx := 1; idx < attempt; idx++ {
+		backoff *= multiplier
+		if backoff >= float64(maxBackoff) {
+			return maxBackoff
+		}
+	}
+
+	return time.Duration(backoff)
+}
+
+// IsTransientError 判断是否为通用的瞬时连接错误，context取消或超时不视为瞬时错误
+func IsTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) {
+		return true
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	errMsg := strings.ToLower(err.Error())
+	return strings.Contains(errMsg, "connection refused") ||
+		strings.Contains(errMsg, "connection reset") ||
+		strings.Contains(errMsg, "broken pipe")
+}
+
+// IsReadStatement 判断是否为只读查询，只读查询在事务外可以安全重试
+func IsReadStatement(sqlText string) bool {
+	switch cachedDatabaseOperation(sqlText, "") {
+	case "select", "show", "describe", "explain":
+		return !strings.Contains(strings.ToLower(sqlText), " for update")
+	default:
+		return false
+	}
+}
+
+// Retry 执行fn，返回瞬时错误时按策略退避重试，ctx结束后不再重试
+func Retry(ctx context.Context, policy *RetryPolicy, databaseName, operation string, isTransient func(error) bool, fn func() error) (err error) {
+	err = fn()
+	if err == nil || !policy.Enabled() {
+		return
+	}
+
+	retried := false
+	for attempt := 1; attempt <= policy.MaxRetries && isTransient(err); attempt++ {
+		retried = true
+		RecordDatabaseRetry(databaseName, operation, RetryOutcomeRetry)
+
+		timer := time.NewTimer(policy.Backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			RecordDatabaseRetry(databaseName, operation, RetryOutcomeExhausted)
+			return
+		case <-timer.C:
+		}
+
+		err = fn()
+		if err == nil {
+			RecordDatabaseRetry(databaseName, operation, RetryOutcomeRecovered)
+			return
+		}
+	}
+
+	if retried {
+		RecordDatabaseRetry(databaseName, operation, RetryOutcomeExhausted)
+	}
+	return
+}
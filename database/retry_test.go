@@ -0,0 +1,107 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/muidea/magicOrm/metrics"
+	"github.com/muidea/magicOrm/metrics/metricsdb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := &RetryPolicy{MaxRetries: 5, InitialBackoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond, Multiplier: 2}
+	assert.Equal(t, 10*time.Millisecond, policy.Backoff(1))
+	assert.Equal(t, 20*time.Millisecond, policy.Backoff(2))
+	assert.Equal(t, 40*time.Millisecond, policy.Backoff(3))
+	assert.Equal(t, 50*time.Millisecond, policy.Backoff(4))
+
+	var nilPolicy *RetryPolicy
+	assert.False(t, nilPolicy.Enabled())
+	assert.False(t, (&RetryPolicy{}).Enabled())
+}
+
+func TestIsTransientError(t *testing.T) {
+	assert.True(t, IsTransientError(driver.ErrBadConn))
+	assert.True(t, IsTransientError(fmt.Errorf("read: %w", syscall.ECONNRESET)))
+	assert.True(t, IsTransientError(errors.New("dial tcp 127.0.0.1:5432: connect: connection refused")))
+	assert.False(t, IsTransientError(context.DeadlineExceeded))
+	assert.False(t, IsTransientError(errors.New("syntax error at or near")))
+	assert.False(t, IsTransientError(nil))
+}
+
+func TestIsReadStatement(t *testing.T) {
+	assert.True(t, IsReadStatement(`SELECT "id" FROM "demo"`))
+	assert.True(t, IsReadStatement(`WITH cte AS (SELECT 1) SELECT * FROM cte`))
+	assert.False(t, IsReadStatement(`SELECT "id" FROM "demo" FOR UPDATE`))
+	assert.False(t, IsReadStatement(`INSERT INTO "demo" VALUES ($1) RETURNING "id"`))
+	assert.False(t, IsReadStatement(`DELETE FROM "demo"`))
+}
+
+func TestRetry(t *testing.T) {
+	oldCollector := metricsdb.GetDatabaseMetricsCollector()
+	collector := metricsdb.NewDatabaseMetricsCollector()
+	metricsdb.SetDatabaseMetricsCollectorForTest(collector)
+	defer metricsdb.SetDatabaseMetricsCollectorForTest(oldCollector)
+
+	policy := &RetryPolicy{MaxRetries: 3, InitialBackoff: time.Millisecond}
+
+	calls := 0
+	err := Retry(context.Background(), policy, DatabasePostgreSQL, "acquire", IsTransientError, func() error {
+		calls++
+		if calls < 3 {
+			return driver.ErrBadConn
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+	err = Retry(context.Background(), policy, DatabasePostgreSQL, "query", IsTransientError, func() error {
+		calls++
+		return driver.ErrBadConn
+	})
+	assert.ErrorIs(t, err, driver.ErrBadConn)
+	assert.Equal(t, 4, calls)
+
+	calls = 0
+	err = Retry(context.Background(), policy, DatabasePostgreSQL, "query", IsTransientError, func() error {
+		calls++
+		return errors.New("syntax error")
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, calls)
+
+	calls = 0
+	err = Retry(context.Background(), nil, DatabasePostgreSQL, "query", IsTransientError, func() error {
+		calls++
+		return driver.ErrBadConn
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, calls)
+
+	counters := collector.GetRetryCounters()
+	assert.Equal(t, int64(2), counters[metrics.BuildKey(DatabasePostgreSQL, "acquire", RetryOutcomeRetry)])
+	assert.Equal(t, int64(1), counters[metrics.BuildKey(DatabasePostgreSQL, "acquire", RetryOutcomeRecovered)])
+	assert.Equal(t, int64(3), counters[metrics.BuildKey(DatabasePostgreSQL, "query", RetryOutcomeRetry)])
+	assert.Equal(t, int64(1), counters[metrics.BuildKey(DatabasePostgreSQL, "query", RetryOutcomeExhausted)])
+}
+
+func TestRetryStopsWhenContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := 0
+	err := Retry(ctx, &RetryPolicy{MaxRetries: 3, InitialBackoff: time.Second}, DatabaseMySQL, "acquire", IsTransientError, func() error {
+		calls++
+		return driver.ErrBadConn
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, calls)
+}
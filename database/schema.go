@@ -0,0 +1,25 @@
+package database
+
+import (
+	"fmt"
+	"regexp"
+
+	cd "github.com/muidea/magicCommon/def"
+)
+
+const maxSchemaNameLength = 63
+
+var schemaNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ValidateSchemaName 校验schema名称，只允许字母、数字和下划线且不以数字开头，空字符串表示默认schema
+func ValidateSchemaName(schema string) *cd.Error {
+	if schema == "" {
+		return nil
+	}
+
+	if len(schema) > maxSchemaNameLength || !schemaNamePattern.MatchString(schema) {
+		return cd.NewError(cd.IllegalParam, fmt.Sprintf("illegal schema name %s", schema))
+	}
+
+	return nil
+}
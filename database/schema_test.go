@@ -0,0 +1,21 @@
+package database
+
+import (
+	"testing"
+
+	cd "github.com/muidea/magicCommon/def"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSchemaName(t *testing.T) {
+	assert.Nil(t, ValidateSchemaName(""))
+	assert.Nil(t, ValidateSchemaName("tenant_01"))
+	assert.Nil(t, ValidateSchemaName("_Tenant"))
+
+	for _, schema := range []string{"1tenant", "tenant-01", `tenant"; DROP TABLE x; --`, "tenant.public", string(make([]byte, 64))} {
+		err := ValidateSchemaName(schema)
+		if assert.NotNil(t, err, schema) {
+			assert.Equal(t, cd.Code(cd.IllegalParam), err.Code, schema)
+		}
+	}
+}
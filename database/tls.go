@@ -0,0 +1,205 @@
+package database
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+
+	cd "github.com/muidea/magicCommon/def"
+)
+
+// SSL模式，取值与PostgreSQL sslmode保持一致，MySQL按相同语义进行映射
+const (
+	SSLModeDisable    = "disable"
+	SSLModeRequire    = "require"
+	SSLModeVerifyCA   = "verify-ca"
+	SSLModeVerifyFull = "verify-full"
+)
+
+// TLSConfig 数据库连接TLS配置
+type TLSConfig struct {
+	// SSLMode 连接模式，为空时等同于disable
+	SSLMode string
+	// RootCert 用于校验服务端证书的CA证书文件
+	RootCert string
+	// ClientCert 客户端证书文件，需要与ClientKey同时配置
+	ClientCert string
+	// ClientKey 客户端私钥文件，需要与ClientCert同时配置
+	ClientKey string
+	// ServerName 校验服务端证书时使用的主机名，为空时使用连接地址中的主机名
+	ServerName string
+}
+
+// Mode 返回生效的SSL模式
+func (s *TLSConfig) Mode() string {
+	if s == nil || s.SSLMode == "" {
+		return SSLModeDisable
+	}
+
+	return s.SSLMode
+}
+
+// Enabled 是否启用TLS
+func (s *TLSConfig) Enabled() bool {
+	return s.Mode() != SSLModeDisable
+}
+
+func (s *TLSConfig) Same(cfg *TLSConfig) bool {
+	if s == nil || cfg == nil {
+		return s.Mode() == cfg.Mode() && s.isEmpty() && cfg.isEmpty()
+	}
+
+	return s.Mode() == cfg.Mode() &&
+		s.RootCert == cfg.RootCert &&
+		s.ClientCert == cfg.ClientCert &&
+		s.ClientKey == cfg.ClientKey &&
+		s.ServerName == cfg.ServerName
+}
+
+func (s *TLSConfig) isEmpty() bool {
+	return s == nil || (s.RootCert == "" && s.ClientCert == "" && s.ClientKey == "" && s.ServerName == "")
+}
+
+// Validate 校验TLS配置，包括模式取值、证书文件是否存在以及能否正确解析
+func (s *TLSConfig) Validate() *cd.Error {
+	switch s.Mode() {
+	case SSLModeDisable:
+		if !s.isEmpty() {
+			return cd.NewError(cd.IllegalParam, "tls certificate or server name is configured while sslmode is disable")
+		}
+		return nil
+	case SSLModeRequire, SSLModeVerifyCA, SSLModeVerifyFull:
+	default:
+		return cd.NewError(cd.IllegalParam, fmt.Sprintf("illegal sslmode %s", s.SSLMode))
+	}
+
+	if (s.ClientCert == "") != (s.ClientKey == "") {
+		return cd.NewError(cd.IllegalParam, "tls client cert and client key must be configured together")
+	}
+
+	if s.RootCert != "" {
+		if _, err := s.loadRootCert(); err != nil {
+			return err
+		}
+	}
+
+	if s.ClientCert != "" {
+		if _, err := s.loadClientCert(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *TLSConfig) loadRootCert() (ret *x509.CertPool, err *cd.Error) {
+	pemData, pemErr := os.ReadFile(s.RootCert)
+	if pemErr != nil {
+		err = cd.NewError(cd.IllegalParam, fmt.Sprintf("read tls root cert failed, %s", pemErr.Error()))
+		return
+	}
+
+	certPool := x509.NewCertPool()
+	if !certPool.AppendCertsFromPEM(pemData) {
+		err = cd.NewError(cd.IllegalParam, fmt.Sprintf("illegal tls root cert %s", s.RootCert))
+		return
+	}
+
+	ret = certPool
+	return
+}
+
+func (s *TLSConfig) loadClientCert() (ret tls.Certificate, err *cd.Error) {
+	certVal, certErr := tls.LoadX509KeyPair(s.ClientCert, s.ClientKey)
+	if certErr != nil {
+		err = cd.NewError(cd.IllegalParam, fmt.Sprintf("load tls client cert failed, %s", certErr.Error()))
+		return
+	}
+
+	ret = certVal
+	return
+}
+
+// BuildTLSConfig 根据配置构造tls.Config，serverAddr为数据库连接地址(host:port)
+//
+// require 只加密，未指定RootCert时不校验证书，指定RootCert时同verify-ca校验证书链；verify-ca 校验证书链但不校验主机名；verify-full 同时校验证书链和主机名
+func (s *TLSConfig) BuildTLSConfig(serverAddr string) (ret *tls.Config, err *cd.Error) {
+	err = s.Validate()
+	if err != nil || !s.Enabled() {
+		return
+	}
+
+	serverName := s.ServerName
+	if serverName == "" {
+		serverName = serverAddr
+		if host, _, splitErr := net.SplitHostPort(serverAddr); splitErr == nil {
+			serverName = host
+		}
+	}
+
+	tlsConfig := &tls.Config{ServerName: serverName}
+	if s.RootCert != "" {
+		tlsConfig.RootCAs, err = s.loadRootCert()
+		if err != nil {
+			return
+		}
+	}
+	if s.ClientCert != "" {
+		certVal, certErr := s.loadClientCert()
+		if certErr != nil {
+			err = certErr
+			return
+		}
+		tlsConfig.Certificates = []tls.Certificate{certVal}
+	}
+
+	switch s.Mode() {
+	case SSLModeRequire:
+		tlsConfig.InsecureSkipVerify = true
+		// 与libpq一致，require 指定了RootCert时按verify-ca校验证书链
+		if tlsConfig.RootCAs != nil {
+			tlsConfig.VerifyPeerCertificate = newChainVerifier(tlsConfig.RootCAs)
+		}
+	case SSLModeVerifyCA:
+		// 跳过默认的主机名校验，改为只校验证书链
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.VerifyPeerCertificate = newChainVerifier(tlsConfig.RootCAs)
+	}
+
+	ret = tlsConfig
+	return
+}
+
+func newChainVerifier(rootCAs *x509.CertPool) func([][]byte, [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		return verifyCertificateChain(rawCerts, rootCAs)
+	}
+}
+
+func verifyCertificateChain(rawCerts [][]byte, rootCAs *x509.CertPool) error {
+	if len(rawCerts) == 0 {
+		return fmt.Errorf("server did not present a certificate")
+	}
+
+	certs := make([]*x509.Certificate, 0, len(rawCerts))
+	for _, rawCert := range rawCerts {
+		certVal, certErr := x509.ParseCertificate(rawCert)
+		if certErr != nil {
+			return certErr
+		}
+		certs = append(certs, certVal)
+	}
+
+	opts := x509.VerifyOptions{
+		Roots:         rootCAs,
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, certVal := range certs[1:] {
+		opts.Intermediates.AddCert(certVal)
+	}
+
+	_, verifyErr := certs[0].Verify(opts)
+	return verifyErr
+}
@@ -0,0 +1,150 @@
+package database
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cd "github.com/muidea/magicCommon/def"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestCertificate(t *testing.T, dir, name string) (certFile, keyFile string) {
+	t.Helper()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key failed: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		DNSNames:              []string{name},
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		t.Fatalf("create certificate failed: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(privateKey)
+	if err != nil {
+		t.Fatalf("marshal key failed: %v", err)
+	}
+
+	certFile = filepath.Join(dir, name+".crt")
+	keyFile = filepath.Join(dir, name+".key")
+	if err = os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}), 0600); err != nil {
+		t.Fatalf("write certificate failed: %v", err)
+	}
+	if err = os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatalf("write key failed: %v", err)
+	}
+
+	return
+}
+
+func readTestCertificate(t *testing.T, certFile string) []byte {
+	t.Helper()
+
+	pemData, err := os.ReadFile(certFile)
+	if err != nil {
+		t.Fatalf("read certificate failed: %v", err)
+	}
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		t.Fatalf("decode certificate failed: %s", certFile)
+	}
+	return block.Bytes
+}
+
+func TestTLSConfigValidate(t *testing.T) {
+	dir := t.TempDir()
+	caFile, _ := writeTestCertificate(t, dir, "db.example.com")
+	clientCert, clientKey := writeTestCertificate(t, dir, "client")
+
+	var nilConfig *TLSConfig
+	assert.Nil(t, nilConfig.Validate())
+	assert.False(t, nilConfig.Enabled())
+
+	cases := []struct {
+		name   string
+		config *TLSConfig
+		valid  bool
+	}{
+		{name: "disable", config: &TLSConfig{SSLMode: SSLModeDisable}, valid: true},
+		{name: "illegal mode", config: &TLSConfig{SSLMode: "prefer-ish"}, valid: false},
+		{name: "cert while disable", config: &TLSConfig{RootCert: caFile}, valid: false},
+		{name: "verify full", config: &TLSConfig{SSLMode: SSLModeVerifyFull, RootCert: caFile, ClientCert: clientCert, ClientKey: clientKey, ServerName: "db.example.com"}, valid: true},
+		{name: "missing key", config: &TLSConfig{SSLMode: SSLModeRequire, ClientCert: clientCert}, valid: false},
+		{name: "missing root cert", config: &TLSConfig{SSLMode: SSLModeVerifyCA, RootCert: filepath.Join(dir, "missing.crt")}, valid: false},
+		{name: "illegal root cert", config: &TLSConfig{SSLMode: SSLModeVerifyCA, RootCert: clientKey}, valid: false},
+	}
+
+	for _, tc := range cases {
+		err := tc.config.Validate()
+		if tc.valid {
+			assert.Nil(t, err, tc.name)
+			continue
+		}
+
+		if assert.NotNil(t, err, tc.name) {
+			assert.Equal(t, cd.Code(cd.IllegalParam), err.Code, tc.name)
+		}
+	}
+}
+
+func TestTLSConfigBuildTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	caFile, _ := writeTestCertificate(t, dir, "db.example.com")
+
+	ret, err := (&TLSConfig{SSLMode: SSLModeDisable}).BuildTLSConfig("127.0.0.1:3306")
+	assert.Nil(t, err)
+	assert.Nil(t, ret)
+
+	ret, err = (&TLSConfig{SSLMode: SSLModeRequire}).BuildTLSConfig("127.0.0.1:3306")
+	assert.Nil(t, err)
+	assert.True(t, ret.InsecureSkipVerify)
+	assert.Nil(t, ret.VerifyPeerCertificate)
+	assert.Equal(t, "127.0.0.1", ret.ServerName)
+
+	ret, err = (&TLSConfig{SSLMode: SSLModeRequire, RootCert: caFile}).BuildTLSConfig("127.0.0.1:3306")
+	assert.Nil(t, err)
+	assert.True(t, ret.InsecureSkipVerify)
+	if assert.NotNil(t, ret.VerifyPeerCertificate) {
+		assert.Nil(t, ret.VerifyPeerCertificate([][]byte{readTestCertificate(t, caFile)}, nil))
+		otherFile, _ := writeTestCertificate(t, dir, "other.example.com")
+		assert.NotNil(t, ret.VerifyPeerCertificate([][]byte{readTestCertificate(t, otherFile)}, nil))
+	}
+
+	ret, err = (&TLSConfig{SSLMode: SSLModeVerifyCA, RootCert: caFile}).BuildTLSConfig("127.0.0.1:3306")
+	assert.Nil(t, err)
+	assert.True(t, ret.InsecureSkipVerify)
+	assert.NotNil(t, ret.VerifyPeerCertificate)
+	assert.NotNil(t, ret.RootCAs)
+
+	ret, err = (&TLSConfig{SSLMode: SSLModeVerifyFull, RootCert: caFile, ServerName: "db.example.com"}).BuildTLSConfig("10.0.0.1:3306")
+	assert.Nil(t, err)
+	assert.False(t, ret.InsecureSkipVerify)
+	assert.Equal(t, "db.example.com", ret.ServerName)
+}
+
+func TestTLSConfigSame(t *testing.T) {
+	var nilConfig *TLSConfig
+	assert.True(t, nilConfig.Same(&TLSConfig{SSLMode: SSLModeDisable}))
+	assert.True(t, (&TLSConfig{}).Same(&TLSConfig{SSLMode: SSLModeDisable}))
+	assert.False(t, (&TLSConfig{SSLMode: SSLModeRequire}).Same(&TLSConfig{SSLMode: SSLModeVerifyFull}))
+	assert.False(t, (&TLSConfig{SSLMode: SSLModeRequire, ServerName: "a"}).Same(&TLSConfig{SSLMode: SSLModeRequire, ServerName: "b"}))
+}
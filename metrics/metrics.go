@@ -15,6 +15,7 @@ const (
 	OperationDrop   OperationType = "drop"
 	OperationCount  OperationType = "count"
 	OperationBatch  OperationType = "batch"
+	OperationRaw    OperationType = "raw"
 )
 
 // QueryType represents the type of query
@@ -20,6 +20,7 @@ type DatabaseMetricsCollector struct {
 	txMu    sync.RWMutex
 	execMu  sync.RWMutex
 	connMu  sync.RWMutex
+	retryMu sync.RWMutex
 
 	// Query counters: database_queryType_status -> count
 	queryCounters map[string]int64
@@ -38,6 +39,9 @@ type DatabaseMetricsCollector struct {
 
 	// Connection pool statistics
 	connectionStats map[string]int64
+
+	// Retry counters: database_operation_outcome -> count
+	retryCounters map[string]int64
 }
 
 // NewDatabaseMetricsCollector creates a new database metrics collector.
@@ -49,6 +53,7 @@ func NewDatabaseMetricsCollector() *DatabaseMetricsCollector {
 		transactionCounters:     make(map[string]int64),
 		executionCounters:       make(map[string]int64),
 		connectionStats:         make(map[string]int64),
+		retryCounters:           make(map[string]int64),
 	}
 }
 
@@ -120,6 +125,15 @@ func (c *DatabaseMetricsCollector) UpdateConnectionStats(database string, state
 	c.connectionStats[key] = count
 }
 
+// RecordRetry records a retry outcome (retry, recovered, exhausted) of a database operation.
+func (c *DatabaseMetricsCollector) RecordRetry(database string, operation string, outcome string) {
+	c.retryMu.Lock()
+	defer c.retryMu.Unlock()
+
+	key := metrics.BuildKey(database, operation, outcome)
+	c.retryCounters[key]++
+}
+
 // GetQueryCounters returns a copy of query counters.
 func (c *DatabaseMetricsCollector) GetQueryCounters() map[string]int64 {
 	c.queryMu.RLock()
@@ -192,12 +206,26 @@ func (c *DatabaseMetricsCollector) GetConnectionStats() map[string]int64 {
 	return result
 }
 
+// GetRetryCounters returns a copy of retry counters.
+func (c *DatabaseMetricsCollector) GetRetryCounters() map[string]int64 {
+	c.retryMu.RLock()
+	defer c.retryMu.RUnlock()
+
+	result := make(map[string]int64, len(c.retryCounters))
+	for k, v := range c.retryCounters {
+		result[k] = v
+	}
+	return result
+}
+
 // Clear clears all collected metrics (useful for testing).
 func (c *DatabaseMetricsCollector) Clear() {
 	c.queryMu.Lock()
 	c.txMu.Lock()
 	c.execMu.Lock()
 	c.connMu.Lock()
+	c.retryMu.Lock()
+	defer c.retryMu.Unlock()
 	defer c.connMu.Unlock()
 	defer c.execMu.Unlock()
 	defer c.txMu.Unlock()
@@ -209,6 +237,7 @@ func (c *DatabaseMetricsCollector) Clear() {
 	c.transactionCounters = make(map[string]int64)
 	c.executionCounters = make(map[string]int64)
 	c.connectionStats = make(map[string]int64)
+	c.retryCounters = make(map[string]int64)
 }
 
 // classifyError classifies an error into error types for metrics.
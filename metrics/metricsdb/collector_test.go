@@ -57,6 +57,23 @@ func TestRecordTransaction(t *testing.T) {
 	assert.Equal(t, int64(1), counters[metrics.BuildKey("mysql", "begin", "error")])
 }
 
+func TestRecordRetry(t *testing.T) {
+	collector := NewDatabaseMetricsCollector()
+
+	collector.RecordRetry("postgresql", "acquire", "retry")
+	collector.RecordRetry("postgresql", "acquire", "retry")
+	collector.RecordRetry("postgresql", "acquire", "recovered")
+	collector.RecordRetry("mysql", "query", "exhausted")
+
+	counters := collector.GetRetryCounters()
+	assert.Equal(t, int64(2), counters[metrics.BuildKey("postgresql", "acquire", "retry")])
+	assert.Equal(t, int64(1), counters[metrics.BuildKey("postgresql", "acquire", "recovered")])
+	assert.Equal(t, int64(1), counters[metrics.BuildKey("mysql", "query", "exhausted")])
+
+	collector.Clear()
+	assert.Equal(t, 0, len(collector.GetRetryCounters()))
+}
+
 func TestRecordExecution(t *testing.T) {
 	collector := NewDatabaseMetricsCollector()
 
@@ -98,6 +98,14 @@ func (p *DatabaseMetricProvider) Metrics() []types.MetricDefinition {
 			[]string{"database", "operation", "status"},
 			databaseLabels,
 		),
+
+		// Retry counter
+		types.NewCounterDefinition(
+			"magicorm_database_retries_total",
+			"Total number of database retry outcomes",
+			[]string{"database", "operation", "outcome"},
+			databaseLabels,
+		),
 	}
 }
 
@@ -209,6 +217,24 @@ func (p *DatabaseMetricProvider) Collect() ([]types.Metric, *types.Error) {
 		}
 	}
 
+	// Collect retry counters
+	retryCounters := p.collector.GetRetryCounters()
+	for key, count := range retryCounters {
+		parts := metrics.ParseKey(key)
+		if len(parts) >= 3 {
+			database, operation, outcome := parts[0], parts[1], parts[2]
+			metricList = append(metricList, types.NewCounter(
+				"magicorm_database_retries_total",
+				float64(count),
+				map[string]string{
+					"database":  database,
+					"operation": operation,
+					"outcome":   outcome,
+				},
+			))
+		}
+	}
+
 	// Collect connection statistics
 	connectionStats := p.collector.GetConnectionStats()
 	for key, count := range connectionStats {
@@ -188,6 +188,10 @@ func (s TypeDeclare) IsNumberValueType() bool {
 	return s > TypeBooleanValue && s <= TypePositiveBigIntegerValue
 }
 
+func (s TypeDeclare) IsFloatValueType() bool {
+	return s == TypeFloatValue || s == TypeDoubleValue
+}
+
 type ValueDeclare string
 
 const (
@@ -196,6 +200,16 @@ const (
 	UUID          = "uuid"
 	Snowflake     = "snowflake"
 	DateTime      = "datetime"
+	// SoftDelete 软删除字段，值为NULL表示未删除，Delete时写入删除时间
+	SoftDelete = "softdelete"
+	// Version 乐观锁版本字段，Update时校验并递增
+	Version = "version"
+	// CreatedAt 创建时间，Insert时写入，Update时不再修改
+	CreatedAt = "createdAt"
+	// UpdatedAt 更新时间，Insert时写入，每次Update/UpdateByFilter时刷新
+	UpdatedAt = "updatedAt"
+	// FullText 全文检索字段，建表时创建全文索引，可使用Filter.Match检索
+	FullText = "fulltext"
 )
 
 func (s ValueDeclare) IsCustomer() bool {
@@ -218,6 +232,26 @@ func (s ValueDeclare) IsDateTime() bool {
 	return s == DateTime
 }
 
+func (s ValueDeclare) IsSoftDelete() bool {
+	return s == SoftDelete
+}
+
+func (s ValueDeclare) IsVersion() bool {
+	return s == Version
+}
+
+func (s ValueDeclare) IsFullText() bool {
+	return s == FullText
+}
+
+func (s ValueDeclare) IsCreatedAt() bool {
+	return s == CreatedAt
+}
+
+func (s ValueDeclare) IsUpdatedAt() bool {
+	return s == UpdatedAt
+}
+
 type ViewDeclare string
 
 const (
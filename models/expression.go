@@ -0,0 +1,152 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+
+	cd "github.com/muidea/magicCommon/def"
+)
+
+// ExprOpr 更新表达式的运算
+type ExprOpr int
+
+const (
+	AddExpr ExprOpr = iota // field = field + value
+	SubExpr                // field = field - value
+	MulExpr                // field = field * value
+)
+
+// Operator 返回运算对应的SQL运算符，非法运算返回空串
+func (s ExprOpr) Operator() string {
+	switch s {
+	case AddExpr:
+		return "+"
+	case SubExpr:
+		return "-"
+	case MulExpr:
+		return "*"
+	}
+
+	return ""
+}
+
+// UpdateExpr 原子更新表达式，由数据库在字段当前值上计算，避免读改写丢失并发更新
+type UpdateExpr struct {
+	Field string
+	Opr   ExprOpr
+	Value any
+}
+
+// Increase field = field + delta
+func Increase(field string, delta any) UpdateExpr {
+	return UpdateExpr{Field: field, Opr: AddExpr, Value: delta}
+}
+
+// Decrease field = field - delta
+func Decrease(field string, delta any) UpdateExpr {
+	return UpdateExpr{Field: field, Opr: SubExpr, Value: delta}
+}
+
+// Multiply field = field * factor
+func Multiply(field string, factor any) UpdateExpr {
+	return UpdateExpr{Field: field, Opr: MulExpr, Value: factor}
+}
+
+// GuardOpr 更新条件的比较运算
+type GuardOpr int
+
+const (
+	EqualGuard      GuardOpr = iota // =
+	NotEqualGuard                   // !=
+	AboveGuard                      // >
+	AboveEqualGuard                 // >=
+	BelowGuard                      // <
+	BelowEqualGuard                 // <=
+)
+
+// Operator 返回比较对应的SQL运算符，非法比较返回空串
+func (s GuardOpr) Operator() string {
+	switch s {
+	case EqualGuard:
+		return "="
+	case NotEqualGuard:
+		return "!="
+	case AboveGuard:
+		return ">"
+	case AboveEqualGuard:
+		return ">="
+	case BelowGuard:
+		return "<"
+	case BelowEqualGuard:
+		return "<="
+	}
+
+	return ""
+}
+
+// UpdateGuard 更新条件 field <opr> value，与filter以AND组合，如扣减库存时的 qty >= ?
+type UpdateGuard struct {
+	Field string
+	Opr   GuardOpr
+	Value any
+}
+
+// Guard 构造更新条件
+func Guard(field string, opr GuardOpr, value any) UpdateGuard {
+	return UpdateGuard{Field: field, Opr: opr, Value: value}
+}
+
+var integerValueRanges = map[TypeDeclare][2]*big.Int{
+	TypeByteValue:                 {big.NewInt(math.MinInt8), big.NewInt(math.MaxInt8)},
+	TypeSmallIntegerValue:         {big.NewInt(math.MinInt16), big.NewInt(math.MaxInt16)},
+	TypeInteger32Value:            {big.NewInt(math.MinInt32), big.NewInt(math.MaxInt32)},
+	TypeIntegerValue:              {big.NewInt(math.MinInt), big.NewInt(math.MaxInt)},
+	TypeBigIntegerValue:           {big.NewInt(math.MinInt64), big.NewInt(math.MaxInt64)},
+	TypePositiveByteValue:         {big.NewInt(0), big.NewInt(math.MaxUint8)},
+	TypePositiveSmallIntegerValue: {big.NewInt(0), big.NewInt(math.MaxUint16)},
+	TypePositiveInteger32Value:    {big.NewInt(0), big.NewInt(math.MaxUint32)},
+	TypePositiveIntegerValue:      {big.NewInt(0), new(big.Int).SetUint64(math.MaxUint)},
+	TypePositiveBigIntegerValue:   {big.NewInt(0), new(big.Int).SetUint64(math.MaxUint64)},
+}
+
+// CheckIntegerValue 整数类型的字段只接受整数值且不能超出类型范围，避免转换时截断小数或溢出
+//
+// 非整数类型或者无法识别的值返回nil，由字段类型转换决定是否合法。
+func CheckIntegerValue(typeDeclare TypeDeclare, value any) *cd.Error {
+	valueRange, ok := integerValueRanges[typeDeclare]
+	if !ok || value == nil {
+		return nil
+	}
+
+	numVal := new(big.Float)
+	rVal := reflect.Indirect(reflect.ValueOf(value))
+	switch rVal.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		numVal.SetInt64(rVal.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		numVal.SetUint64(rVal.Uint())
+	case reflect.Float32, reflect.Float64:
+		floatVal := rVal.Float()
+		if math.IsNaN(floatVal) || math.IsInf(floatVal, 0) {
+			return cd.NewError(cd.IllegalParam, fmt.Sprintf("illegal integer value %v", value))
+		}
+		numVal.SetFloat64(floatVal)
+	case reflect.String:
+		if _, parseOK := numVal.SetString(rVal.String()); !parseOK {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	if !numVal.IsInt() {
+		return cd.NewError(cd.IllegalParam, fmt.Sprintf("illegal integer value %v, type:%s", value, typeDeclare.String()))
+	}
+	intVal, _ := numVal.Int(nil)
+	if intVal.Cmp(valueRange[0]) < 0 || intVal.Cmp(valueRange[1]) > 0 {
+		return cd.NewError(cd.IllegalParam, fmt.Sprintf("integer value %v out of range, type:%s", value, typeDeclare.String()))
+	}
+	return nil
+}
@@ -12,16 +12,43 @@ const (
 	InOpr              // in
 	NotInOpr           // !in
 	LikeOpr            // like
+	MatchOpr           // 全文检索
 )
 
 type OprCode int
 
+// SortRelevance 作为Sort的字段名时按Match条件的全文检索相关度排序
+const SortRelevance = "$relevance"
+
+// FieldPathSeparator 过滤条件中关系字段路径的分隔符，如 "group.name"
+const FieldPathSeparator = "."
+
+// DeletedScope 声明了软删除字段的模型在查询时的范围
+type DeletedScope int
+
+const (
+	// ExcludeDeleted 默认，排除已软删除的行
+	ExcludeDeleted DeletedScope = iota
+	// IncludeDeleted 包含已软删除的行
+	IncludeDeleted
+	// OnlyDeleted 只包含已软删除的行
+	OnlyDeleted
+)
+
 // FilterItem FilterItem
 type FilterItem interface {
 	OprCode() OprCode
 	OprValue() Value
 }
 
+// ExistsItem 关系字段的存在性条件
+type ExistsItem interface {
+	// NotExists 为true时要求不存在满足条件的关联对象
+	NotExists() bool
+	// SubFilter 关联对象的过滤条件，为nil时不限定关联对象
+	SubFilter() Filter
+}
+
 // Sorter sort Item
 type Sorter interface {
 	Name() string
@@ -44,12 +71,29 @@ type Filter interface {
 	In(key string, val any) *cd.Error
 	NotIn(key string, val any) *cd.Error
 	Like(key string, val any) *cd.Error
+	// Match 全文检索，key须为声明了fulltext的字段，val为普通检索文本(如用户输入的 "hello world")，
+	// PostgreSQL按websearch_to_tsquery解析，MySQL按自然语言模式检索
+	Match(key string, val any) *cd.Error
+	// Exists 要求关系字段key存在满足filter的关联对象，filter绑定关联模型，为nil时只要求存在关联对象
+	Exists(key string, filter Filter) *cd.Error
+	// NotExists 要求关系字段key不存在满足filter的关联对象
+	NotExists(key string, filter Filter) *cd.Error
 	Pagination(pageNum, pageSize int)
 	Sort(fieldName string, ascFlag bool)
 	ValueMask(val any) *cd.Error
+	// Select 指定查询的字段，只查询这些列与关系，主键总会查询；多次调用以最后一次为准
+	Select(fieldNames ...string) *cd.Error
+	Deleted(scope DeletedScope)
 
 	GetFilterItem(key string) FilterItem
+	// GetPathFilterItems 返回以 fieldName 开头的点路径过滤项，key为去掉 "fieldName." 后的路径
+	GetPathFilterItems(fieldName string) map[string]FilterItem
+	// GetExistsItem 返回关系字段的存在性条件，未设置时为nil
+	GetExistsItem(key string) ExistsItem
 	Paginationer() Paginationer
 	Sorter() Sorter
+	GetDeletedScope() DeletedScope
+	// GetSelectFields 返回Select指定的字段，未指定时为空
+	GetSelectFields() []string
 	MaskModel() Model
 }
@@ -2,6 +2,7 @@ package models
 
 import (
 	"fmt"
+	"slices"
 
 	cd "github.com/muidea/magicCommon/def"
 )
@@ -83,6 +84,152 @@ func IsDateTimeDeclare(val ValueDeclare) bool {
 	return val == DateTime
 }
 
+func IsSoftDeleteDeclare(val ValueDeclare) bool {
+	return val == SoftDelete
+}
+
+// getDeclareField 返回模型中第一个声明为declare的字段，未声明时返回nil
+func getDeclareField(vModel Model, declare ValueDeclare) Field {
+	if vModel == nil {
+		return nil
+	}
+
+	for _, field := range vModel.GetFields() {
+		if spec := field.GetSpec(); spec != nil && spec.GetValueDeclare() == declare {
+			return field
+		}
+	}
+
+	return nil
+}
+
+// GetSoftDeleteField 返回模型声明的软删除字段，未声明时返回nil
+func GetSoftDeleteField(vModel Model) Field {
+	return getDeclareField(vModel, SoftDelete)
+}
+
+// VerifySoftDelete 软删除字段必须是可选(指针)的datetime字段，且每个模型最多声明一个
+func VerifySoftDelete(vModel Model) (err *cd.Error) {
+	softDeleteNum := 0
+	for _, vField := range vModel.GetFields() {
+		if spec := vField.GetSpec(); spec == nil || !IsSoftDeleteDeclare(spec.GetValueDeclare()) {
+			continue
+		}
+
+		softDeleteNum++
+		if softDeleteNum > 1 {
+			err = cd.NewError(cd.Unexpected, fmt.Sprintf("model declares more than one soft delete field, field name:%s", vField.GetName()))
+			return
+		}
+
+		vType := vField.GetType()
+		if vType.GetValue() != TypeDateTimeValue || !vType.IsPtrType() {
+			err = cd.NewError(cd.Unexpected, fmt.Sprintf("soft delete field must be an optional datetime field, field name:%s", vField.GetName()))
+			return
+		}
+	}
+
+	return
+}
+
+func IsVersionDeclare(val ValueDeclare) bool {
+	return val == Version
+}
+
+// GetVersionField 返回模型声明的乐观锁版本字段，未声明时返回nil
+func GetVersionField(vModel Model) Field {
+	return getDeclareField(vModel, Version)
+}
+
+// VerifyVersion 版本字段必须是非主键的必选整数字段，且每个模型最多声明一个
+func VerifyVersion(vModel Model) (err *cd.Error) {
+	versionNum := 0
+	for _, vField := range vModel.GetFields() {
+		spec := vField.GetSpec()
+		if spec == nil || !IsVersionDeclare(spec.GetValueDeclare()) {
+			continue
+		}
+
+		versionNum++
+		if versionNum > 1 {
+			err = cd.NewError(cd.Unexpected, fmt.Sprintf("model declares more than one version field, field name:%s", vField.GetName()))
+			return
+		}
+
+		vType := vField.GetType()
+		if spec.IsPrimaryKey() || !vType.GetValue().IsNumberValueType() || vType.IsPtrType() {
+			err = cd.NewError(cd.Unexpected, fmt.Sprintf("version field must be a required integer field, field name:%s", vField.GetName()))
+			return
+		}
+	}
+
+	return
+}
+
+func IsCreatedAtDeclare(val ValueDeclare) bool {
+	return val == CreatedAt
+}
+
+func IsUpdatedAtDeclare(val ValueDeclare) bool {
+	return val == UpdatedAt
+}
+
+// GetUpdatedAtField 返回模型声明的更新时间字段，未声明时返回nil
+func GetUpdatedAtField(vModel Model) Field {
+	return getDeclareField(vModel, UpdatedAt)
+}
+
+func IsFullTextDeclare(val ValueDeclare) bool {
+	return val == FullText
+}
+
+// IsFullTextField 判断Field是否声明为全文检索字段
+func IsFullTextField(field Field) bool {
+	spec := field.GetSpec()
+	return spec != nil && IsFullTextDeclare(spec.GetValueDeclare())
+}
+
+// VerifyFullText 全文检索字段必须是非主键的字符串字段，可以声明多个
+func VerifyFullText(vModel Model) (err *cd.Error) {
+	for _, vField := range vModel.GetFields() {
+		spec := vField.GetSpec()
+		if spec == nil || !IsFullTextDeclare(spec.GetValueDeclare()) {
+			continue
+		}
+
+		if spec.IsPrimaryKey() || vField.GetType().GetValue() != TypeStringValue {
+			err = cd.NewError(cd.Unexpected, fmt.Sprintf("fulltext field must be a string field, field name:%s", vField.GetName()))
+			return
+		}
+	}
+
+	return
+}
+
+// VerifyTimestamp 创建时间、更新时间字段必须是datetime字段，且每个模型各最多声明一个
+func VerifyTimestamp(vModel Model) (err *cd.Error) {
+	declareNum := map[ValueDeclare]int{}
+	for _, vField := range vModel.GetFields() {
+		spec := vField.GetSpec()
+		if spec == nil || (!IsCreatedAtDeclare(spec.GetValueDeclare()) && !IsUpdatedAtDeclare(spec.GetValueDeclare())) {
+			continue
+		}
+
+		declareNum[spec.GetValueDeclare()]++
+		if declareNum[spec.GetValueDeclare()] > 1 {
+			err = cd.NewError(cd.Unexpected, fmt.Sprintf("model declares more than one %s field, field name:%s", spec.GetValueDeclare(), vField.GetName()))
+			return
+		}
+
+		if vField.GetType().GetValue() != TypeDateTimeValue {
+			err = cd.NewError(cd.Unexpected, fmt.Sprintf("%s field must be a datetime field, field name:%s", spec.GetValueDeclare(), vField.GetName()))
+			return
+		}
+	}
+
+	return
+}
+
 // IsBasicField 判断Field对应的Type是否是基本类型
 func IsBasicField(field Field) bool {
 	return IsBasic(field.GetType())
@@ -127,12 +274,23 @@ func IsPrimaryField(field Field) bool {
 	return field.GetSpec().IsPrimaryKey()
 }
 
+// IsSelectedField 判断Field是否在Filter.Select指定的字段中
+// 未指定字段时全部选中，主键总是选中
+func IsSelectedField(selectFields []string, field Field) bool {
+	if len(selectFields) == 0 || IsPrimaryField(field) {
+		return true
+	}
+
+	return slices.Contains(selectFields, field.GetName())
+}
+
 // VerifyModel 验证Model
 // 1. Name和PkgPath不能为""
 // 2. Fields 不能存在重名的Field
 // 3. 至少有一个PrimaryField
-// 4. 如果校验失败，则返回失败信息
-// 5. 校验通过返回nil
+// 4. 软删除字段、版本字段、时间戳字段声明合法
+// 5. 如果校验失败，则返回失败信息
+// 6. 校验通过返回nil
 func VerifyModel(vModel Model) (err *cd.Error) {
 	if vModel.GetName() == "" {
 		err = cd.NewError(cd.Unexpected, "model name is empty")
@@ -170,5 +328,21 @@ func VerifyModel(vModel Model) (err *cd.Error) {
 		return
 	}
 
+	err = VerifySoftDelete(vModel)
+	if err != nil {
+		return
+	}
+
+	err = VerifyVersion(vModel)
+	if err != nil {
+		return
+	}
+
+	err = VerifyTimestamp(vModel)
+	if err != nil {
+		return
+	}
+
+	err = VerifyFullText(vModel)
 	return
 }
@@ -2,6 +2,8 @@ package orm
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"log/slog"
 
@@ -40,10 +42,23 @@ func newBaseRunner(
 		executor:      executor,
 		modelProvider: provider,
 		modelCodec:    modelCodec,
-		sqlBuilder:    NewBuilder(provider, modelCodec),
+		sqlBuilder:    newDatabaseBuilder(provider, modelCodec),
 	}
 }
 
+// newDatabaseBuilder 构造SQL Builder，并应用provider所属数据库通过DatabaseOption指定的构造参数
+func newDatabaseBuilder(provider provider.Provider, modelCodec codec.Codec) database.Builder {
+	builder := NewBuilder(provider, modelCodec)
+	if provider == nil {
+		return builder
+	}
+
+	if optionsVal, optionsOK := name2Options.Load(provider.Owner()); optionsOK {
+		builder.SetFilterPathDepth(optionsVal.(*databaseOptions).filterPathDepth)
+	}
+	return builder
+}
+
 // isContextValid 检查 context 是否失效
 func isContextValid(ctx context.Context) bool {
 	if ctx == nil {
@@ -58,11 +73,20 @@ func isContextValid(ctx context.Context) bool {
 	}
 }
 
+// contextError context 失效时对应的错误，超时返回 cd.Timeout
+func contextError(ctx context.Context) *cd.Error {
+	if ctx != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return cd.NewError(cd.Timeout, "context deadline exceeded")
+	}
+
+	return cd.NewError(cd.Unexpected, "context is invalid or cancelled")
+}
+
 // checkContext 检查 context 是否失效，如果失效则返回错误
 func (s *baseRunner) checkContext() *cd.Error {
 	if !isContextValid(s.context) {
 		slog.Error("orm context invalid or cancelled")
-		return cd.NewError(cd.Unexpected, "context is invalid or cancelled")
+		return contextError(s.context)
 	}
 	return nil
 }
@@ -71,7 +95,50 @@ func (s *baseRunner) checkContext() *cd.Error {
 func (s *impl) CheckContext() *cd.Error {
 	if !isContextValid(s.context) {
 		slog.Error("CheckContext: context invalid or cancelled")
-		return cd.NewError(cd.Unexpected, "context is invalid or cancelled")
+		return contextError(s.context)
 	}
 	return nil
 }
+
+// SetStatementTimeout 设置当前Orm默认的单条SQL执行超时，timeout<=0表示不限制
+func (s *impl) SetStatementTimeout(timeout time.Duration) {
+	if s.executor != nil {
+		s.executor.SetStatementTimeout(timeout)
+	}
+}
+
+// WithTimeout 返回共享当前连接和事务的Orm，通过它发起的调用使用timeout作为单条SQL执行超时
+//
+// 返回的Orm不持有连接，Release不会释放连接，仍需调用原Orm的Release
+func (s *impl) WithTimeout(timeout time.Duration) Orm {
+	ret := *s
+	ret.callTimeout = timeout
+	ret.borrowed = true
+	return &ret
+}
+
+// applyContext 调用期间使用Orm的context作为executor执行SQL的context，返回恢复函数
+func (s *impl) applyContext() func() {
+	if s.executor == nil {
+		return func() {}
+	}
+
+	preContext := s.executor.Context()
+	s.executor.SetContext(s.context)
+	return func() {
+		s.executor.SetContext(preContext)
+	}
+}
+
+// applyCallTimeout 调用期间使用callTimeout覆盖executor的语句超时，返回恢复函数
+func (s *impl) applyCallTimeout() func() {
+	if s.callTimeout <= 0 || s.executor == nil {
+		return func() {}
+	}
+
+	preTimeout := s.executor.StatementTimeout()
+	s.executor.SetStatementTimeout(s.callTimeout)
+	return func() {
+		s.executor.SetStatementTimeout(preTimeout)
+	}
+}
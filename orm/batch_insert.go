@@ -0,0 +1,377 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	cd "github.com/muidea/magicCommon/def"
+
+	"log/slog"
+
+	"github.com/muidea/magicOrm/database"
+	"github.com/muidea/magicOrm/database/codec"
+	"github.com/muidea/magicOrm/metrics"
+	"github.com/muidea/magicOrm/models"
+	"github.com/muidea/magicOrm/provider"
+	"github.com/muidea/magicOrm/validation/errors"
+)
+
+// defaultBatchInsertParamLimit 单条批量插入SQL的参数个数上限，PostgreSQL与MySQL的占位符上限均为65535
+const defaultBatchInsertParamLimit = 65535
+
+// BatchInsertRunner 批量插入同一类型的模型，每批生成一条多行INSERT
+type BatchInsertRunner struct {
+	baseRunner
+	vModels    []models.Model
+	paramLimit int
+}
+
+func NewBatchInsertRunner(
+	ctx context.Context,
+	vModels []models.Model,
+	executor database.Executor,
+	provider provider.Provider,
+	modelCodec codec.Codec,
+	paramLimit int) *BatchInsertRunner {
+	if paramLimit <= 0 {
+		paramLimit = defaultBatchInsertParamLimit
+	}
+
+	var vModel models.Model
+	if len(vModels) > 0 {
+		vModel = vModels[0]
+	}
+	return &BatchInsertRunner{
+		baseRunner: newBaseRunner(ctx, vModel, executor, provider, modelCodec, false, 0),
+		vModels:    vModels,
+		paramLimit: paramLimit,
+	}
+}
+
+// batchInsertParamCount 单个模型插入时占用的参数个数
+func batchInsertParamCount(vModel models.Model) (ret int) {
+	for _, field := range vModel.GetFields() {
+		if !models.IsBasicField(field) || !models.IsValidField(field) {
+			continue
+		}
+		if field.GetSpec().GetValueDeclare() == models.AutoIncrement {
+			continue
+		}
+
+		ret++
+	}
+
+	return
+}
+
+// splitBatchInsert 按参数个数上限拆分批次，单个模型超过上限时独占一批
+func splitBatchInsert(vModels []models.Model, paramLimit int) (ret [][]models.Model) {
+	startIdx := 0
+	paramCount := 0
+	for idx, vModel := range vModels {
+		modelParamCount := batchInsertParamCount(vModel)
+		if idx > startIdx && paramCount+modelParamCount > paramLimit {
+			ret = append(ret, vModels[startIdx:idx])
+			startIdx = idx
+			paramCount = 0
+		}
+
+		paramCount += modelParamCount
+	}
+
+	if startIdx < len(vModels) {
+		ret = append(ret, vModels[startIdx:])
+	}
+	return
+}
+
+func (s *BatchInsertRunner) insertHosts() (err *cd.Error) {
+	autoIncrementFlag := false
+	now := currentDateTime(s.context)
+	for _, vModel := range s.vModels {
+		if prepareInsertValues(vModel, now) {
+			autoIncrementFlag = true
+		}
+	}
+
+	for _, batchModels := range splitBatchInsert(s.vModels, s.paramLimit) {
+		insertResult, insertErr := s.sqlBuilder.BuildBatchInsert(batchModels)
+		if insertErr != nil {
+			err = insertErr
+			slog.Error("BatchInsertRunner insertHosts failed", "operation", "BuildBatchInsert", "error", err.Error())
+			return
+		}
+
+		pkVals, pkErr := executeBatchInsert(s.executor, insertResult, len(batchModels), autoIncrementFlag)
+		if pkErr != nil {
+			err = pkErr
+			slog.Error("BatchInsertRunner insertHosts failed", "operation", "executeBatchInsert", "error", err.Error())
+			return
+		}
+
+		if !autoIncrementFlag {
+			continue
+		}
+		for idx, vModel := range batchModels {
+			err = s.assignPrimaryKey(vModel, pkVals[idx])
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	return
+}
+
+// relationItem 待建立的关系，assign 用于关联模型插入后回填字段值
+type relationItem struct {
+	hostModel     models.Model
+	relationModel models.Model
+	assign        func(rModel models.Model) *cd.Error
+}
+
+func (s *BatchInsertRunner) collectRelationItems(fieldName string) (ret []relationItem, err *cd.Error) {
+	for _, vModel := range s.vModels {
+		vField := vModel.GetField(fieldName)
+		if !models.IsAssignedField(vField) {
+			// 与Insert保持一致，未赋值的关系字段默认可跳过，但 required relation 仍然必须提供。
+			if (vField.GetType().IsPtrType() || models.IsSliceField(vField)) && !isRequiredRelationField(vField) {
+				continue
+			}
+
+			err = cd.NewError(cd.IllegalParam, fmt.Sprintf("illegal field value, field:%s", vField.GetName()))
+			slog.Error("BatchInsertRunner illegal field value", "field", vField.GetName(), "error", err.Error())
+			return
+		}
+
+		elemType := vField.GetType().Elem()
+		if !models.IsSliceField(vField) {
+			rModel, rErr := s.modelProvider.GetTypeModel(elemType)
+			if rErr == nil {
+				rModel, rErr = s.modelProvider.SetModelValue(rModel, vField.GetValue())
+			}
+			if rErr != nil {
+				err = rErr
+				slog.Error("BatchInsertRunner collectRelationItems failed", "field", fieldName, "error", err.Error())
+				return
+			}
+
+			fieldPtr := vField
+			ret = append(ret, relationItem{
+				hostModel:     vModel,
+				relationModel: rModel,
+				assign: func(rModel models.Model) *cd.Error {
+					fieldPtr.SetValue(rModel.Interface(models.IsPtrField(fieldPtr)))
+					return nil
+				},
+			})
+			continue
+		}
+
+		for _, fVal := range vField.GetSliceValue() {
+			rModel, rErr := s.modelProvider.GetTypeModel(elemType)
+			if rErr == nil {
+				rModel, rErr = s.modelProvider.SetModelValue(rModel, fVal)
+			}
+			if rErr != nil {
+				err = rErr
+				slog.Error("BatchInsertRunner collectRelationItems failed", "field", fieldName, "error", err.Error())
+				return
+			}
+
+			valPtr := fVal
+			ret = append(ret, relationItem{
+				hostModel:     vModel,
+				relationModel: rModel,
+				assign: func(rModel models.Model) *cd.Error {
+					return valPtr.Set(rModel.Interface(elemType.IsPtrType()))
+				},
+			})
+		}
+	}
+
+	return
+}
+
+func (s *BatchInsertRunner) insertRelation(vField models.Field) (err *cd.Error) {
+	items, itemsErr := s.collectRelationItems(vField.GetName())
+	if itemsErr != nil || len(items) == 0 {
+		err = itemsErr
+		return
+	}
+
+	// 包含关系需要先插入关联模型，引用关系只建立关联
+	isReference := vField.GetType().Elem().IsPtrType()
+	if !models.IsSliceField(vField) {
+		isReference = models.IsPtrField(vField)
+	}
+	if !isReference {
+		rModels := make([]models.Model, 0, len(items))
+		for _, item := range items {
+			rModels = append(rModels, item.relationModel)
+		}
+
+		rInsertRunner := NewBatchInsertRunner(s.context, rModels, s.executor, s.modelProvider, s.modelCodec, s.paramLimit)
+		rModels, err = rInsertRunner.Insert()
+		if err != nil {
+			slog.Error("BatchInsertRunner insertRelation failed", "field", vField.GetName(), "error", err.Error())
+			return
+		}
+		for idx := range items {
+			items[idx].relationModel = rModels[idx]
+		}
+	}
+
+	// 每行关系占用两个参数
+	batchSize := s.paramLimit / 2
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+	for startIdx := 0; startIdx < len(items); startIdx += batchSize {
+		endIdx := min(startIdx+batchSize, len(items))
+
+		leftVals := make([]any, 0, endIdx-startIdx)
+		rightVals := make([]any, 0, endIdx-startIdx)
+		for _, item := range items[startIdx:endIdx] {
+			leftVals = append(leftVals, item.hostModel.GetPrimaryField().GetValue().Get())
+			rightVals = append(rightVals, item.relationModel.GetPrimaryField().GetValue().Get())
+		}
+
+		relationResult, relationErr := s.sqlBuilder.BuildBatchInsertRelation(s.vModel, vField, leftVals, rightVals)
+		if relationErr != nil {
+			err = relationErr
+			slog.Error("BatchInsertRunner insertRelation failed", "field", vField.GetName(), "error", err.Error())
+			return
+		}
+
+		_, err = s.executor.Execute(relationResult.SQL(), relationResult.Args()...)
+		if err != nil {
+			slog.Error("BatchInsertRunner insertRelation failed", "field", vField.GetName(), "error", err.Error())
+			return
+		}
+	}
+
+	for _, item := range items {
+		err = item.assign(item.relationModel)
+		if err != nil {
+			slog.Error("BatchInsertRunner insertRelation failed", "field", vField.GetName(), "error", err.Error())
+			return
+		}
+	}
+	return
+}
+
+func (s *BatchInsertRunner) Insert() (ret []models.Model, err *cd.Error) {
+	if err = s.checkContext(); err != nil {
+		return
+	}
+	if len(s.vModels) == 0 {
+		return
+	}
+
+	for _, vModel := range s.vModels {
+		err = invokeHooks(s.context, vModel, BeforeInsert)
+		if err != nil {
+			return
+		}
+	}
+
+	err = s.insertHosts()
+	if err != nil {
+		slog.Error("BatchInsertRunner insertHosts failed", "error", err.Error())
+		return
+	}
+
+	for _, field := range s.vModel.GetFields() {
+		// 忽略基础字段
+		if models.IsBasicField(field) {
+			continue
+		}
+
+		err = s.insertRelation(field)
+		if err != nil {
+			slog.Error("BatchInsertRunner failed", "error", err.Error())
+			return
+		}
+	}
+
+	for _, vModel := range s.vModels {
+		err = invokeHooks(s.context, vModel, AfterInsert)
+		if err != nil {
+			return
+		}
+	}
+
+	ret = make([]models.Model, 0, len(s.vModels))
+	for _, vModel := range s.vModels {
+		rModel, rErr := projectWriteResponseModel(vModel, s.modelProvider)
+		if rErr != nil {
+			err = rErr
+			return
+		}
+		ret = append(ret, rModel)
+	}
+	return
+}
+
+// BatchInsert 在一个事务中批量插入同一类型的模型，按参数个数上限拆分为多条多行INSERT，
+// 写入前后逐个调用 BeforeInsert/AfterInsert 钩子
+func (s *impl) BatchInsert(vModels []models.Model) (ret []models.Model, err *cd.Error) {
+	if len(s.interceptors) > 0 {
+		return s.interceptBatchInsert(vModels)
+	}
+
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		if ormMetricCollector != nil && len(vModels) > 0 {
+			ormMetricCollector.RecordOperation(string(metrics.OperationInsert), vModels[0], duration, cd.ToStdError(err))
+		}
+	}()
+
+	if err = s.CheckContext(); err != nil {
+		return
+	}
+
+	restoreTimeout := s.applyCallTimeout()
+	defer restoreTimeout()
+
+	if len(vModels) == 0 {
+		return
+	}
+
+	for _, vModel := range vModels {
+		if vModel == nil {
+			err = cd.NewError(cd.IllegalParam, "illegal model value")
+			return
+		}
+		if vModel.GetPkgKey() != vModels[0].GetPkgKey() {
+			err = cd.NewError(cd.IllegalParam, fmt.Sprintf("batch insert models must be the same type, %s != %s", vModel.GetPkgKey(), vModels[0].GetPkgKey()))
+			return
+		}
+
+		validationErr := s.validateModel(vModel, errors.ScenarioInsert)
+		if validationErr != nil {
+			err = validationErr
+			return
+		}
+	}
+
+	err = s.executor.BeginTransaction()
+	if err != nil {
+		return
+	}
+	defer func() {
+		s.finalTransaction(err)
+	}()
+
+	insertRunner := NewBatchInsertRunner(s.context, vModels, s.executor, s.modelProvider, s.modelCodec, s.batchParamLimit)
+	ret, err = insertRunner.Insert()
+	if err != nil {
+		slog.Error("BatchInsert BatchInsertRunner.Insert failed", "pkgKey", vModels[0].GetPkgKey(), "error", err.Error())
+		return
+	}
+	return
+}
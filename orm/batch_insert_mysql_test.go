@@ -0,0 +1,56 @@
+//go:build mysql
+// +build mysql
+
+package orm
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/muidea/magicOrm/database/codec"
+	"github.com/muidea/magicOrm/models"
+	"github.com/muidea/magicOrm/provider"
+)
+
+func TestBatchInsertMySQLAutoIncrementStep(t *testing.T) {
+	localProvider := provider.NewLocalProvider("tenant", nil)
+	if _, err := localProvider.RegisterModel(&iterateRow{}); err != nil {
+		t.Fatalf("RegisterModel(iterateRow) failed: %v", err)
+	}
+
+	rowModels := []models.Model{}
+	for _, name := range []string{"a", "b", "c"} {
+		rowModel, err := localProvider.GetEntityModel(&iterateRow{Name: name}, true)
+		if err != nil {
+			t.Fatalf("GetEntityModel(iterateRow) failed: %v", err)
+		}
+		rowModels = append(rowModels, rowModel)
+	}
+
+	executor := &fakeExecutor{
+		responses: []fakeQueryResponse{
+			{
+				match: func(querySQL string, _ []any) bool { return strings.Contains(querySQL, "@@auto_increment_increment") },
+				rows:  [][]any{{int64(2)}},
+			},
+		},
+		insertIDs: []any{int64(101)},
+	}
+	ormImpl := &impl{
+		context:       context.Background(),
+		executor:      executor,
+		modelProvider: localProvider,
+		modelCodec:    codec.New(localProvider, "tenant"),
+	}
+
+	insertedModels, err := ormImpl.BatchInsert(rowModels)
+	if err != nil {
+		t.Fatalf("impl.BatchInsert(iterateRow) failed: %v", err)
+	}
+	for idx, expectID := range []int64{101, 103, 105} {
+		if insertedModels[idx].Interface(true).(*iterateRow).ID != expectID {
+			t.Fatalf("primary keys should follow auto_increment_increment, got %#v", insertedModels[idx].Interface(true))
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package orm
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	cd "github.com/muidea/magicCommon/def"
+	"github.com/muidea/magicOrm/database/codec"
+	"github.com/muidea/magicOrm/models"
+	"github.com/muidea/magicOrm/provider"
+	"github.com/muidea/magicOrm/provider/remote"
+)
+
+func TestBatchInsertVMIRemoteChunksAndRelations(t *testing.T) {
+	remoteProvider := provider.NewRemoteProvider("tenant", nil)
+	registerVMIQueryModels(t, remoteProvider)
+
+	appleModel := buildProductInsertModel(t, remoteProvider)
+	pearModel := buildProductInsertModel(t, remoteProvider)
+	if err := pearModel.SetFieldValue("name", "pear"); err != nil {
+		t.Fatalf("SetFieldValue(name) failed: %v", err)
+	}
+
+	batchInsertMatch := func(name string) func(string, []any) bool {
+		return func(sql string, args []any) bool {
+			return strings.HasPrefix(sql, `INSERT INTO "tenant_Product"`) && len(args) > 0 && args[0] == name
+		}
+	}
+	executor := &fakeExecutor{
+		responses: []fakeQueryResponse{
+			{match: batchInsertMatch("apple"), rows: [][]any{{int64(1001)}}},
+			{match: batchInsertMatch("pear"), rows: [][]any{{int64(1002)}}},
+		},
+	}
+	ormImpl := &impl{
+		context:         context.Background(),
+		executor:        executor,
+		modelProvider:   remoteProvider,
+		modelCodec:      codec.New(remoteProvider, "tenant"),
+		batchParamLimit: 10,
+	}
+
+	insertedModels, err := ormImpl.BatchInsert([]models.Model{appleModel, pearModel})
+	if err != nil {
+		t.Fatalf("impl.BatchInsert(products) failed: %v", err)
+	}
+	if len(insertedModels) != 2 {
+		t.Fatalf("expected 2 inserted models, got %d", len(insertedModels))
+	}
+	for idx, expectID := range []int64{1001, 1002} {
+		productValue := insertedModels[idx].Interface(true).(*remote.ObjectValue)
+		if productValue.GetFieldValue("id") != expectID {
+			t.Fatalf("product primary key should be assigned from returning id, got %#v", productValue)
+		}
+	}
+
+	// 每个product占用9个参数，参数上限为10时拆分为两条INSERT
+	if countCallsByKind(executor.execCalls, "query") != 2 {
+		t.Fatalf("expected 2 batch insert statements, got %#v", executor.execCalls)
+	}
+	if !containsSQLCall(executor.execCalls, "exec", `INSERT INTO "tenant_ProductStatus3Status" ("left", "right") VALUES ($1,$2),($3,$4)`, []any{int64(1001), int64(9), int64(1002), int64(9)}) {
+		t.Fatalf("missing batch relation insert call: %#v", executor.execCalls)
+	}
+	if containsSQLCall(executor.execCalls, "exec", "tenant_Status\"", nil) {
+		t.Fatalf("status reference should not trigger host insert: %#v", executor.execCalls)
+	}
+	if executor.beginCalls != 1 || executor.commitCalls != 1 {
+		t.Fatalf("batch insert should run in one transaction, got begin=%d commit=%d", executor.beginCalls, executor.commitCalls)
+	}
+}
+
+func TestBatchInsertRunsInsertHooks(t *testing.T) {
+	localProvider := provider.NewLocalProvider("tenant", nil)
+	if _, err := localProvider.RegisterModel(&hookLocalNote{}); err != nil {
+		t.Fatalf("RegisterModel(hookLocalNote) failed: %v", err)
+	}
+
+	noteModels := []models.Model{}
+	for _, name := range []string{"", "todo"} {
+		noteModel, err := localProvider.GetEntityModel(&hookLocalNote{Name: name}, true)
+		if err != nil {
+			t.Fatalf("GetEntityModel(hookLocalNote) failed: %v", err)
+		}
+		noteModels = append(noteModels, noteModel)
+	}
+
+	executor := &fakeExecutor{
+		responses: []fakeQueryResponse{
+			{
+				match: func(sql string, _ []any) bool { return strings.HasPrefix(sql, `INSERT INTO "tenant_HookLocalNote"`) },
+				rows:  [][]any{{int64(21)}, {int64(22)}},
+			},
+		},
+	}
+	ormImpl := &impl{
+		context:       context.Background(),
+		executor:      executor,
+		modelProvider: localProvider,
+		modelCodec:    codec.New(localProvider, "tenant"),
+	}
+
+	hookLocalNoteInsertedID = 0
+	if _, err := ormImpl.BatchInsert(noteModels); err != nil {
+		t.Fatalf("impl.BatchInsert(hookLocalNote) failed: %v", err)
+	}
+	if !containsSQLCall(executor.execCalls, "query", `INSERT INTO "tenant_HookLocalNote"`, []any{"untitled", "todo"}) {
+		t.Fatalf("BeforeInsert value should be written, got %#v", executor.execCalls)
+	}
+	if hookLocalNoteInsertedID != 22 {
+		t.Fatalf("AfterInsert should run for every model with the generated id, got %d", hookLocalNoteInsertedID)
+	}
+}
+
+func TestBatchInsertRejectsMixedModels(t *testing.T) {
+	remoteProvider := provider.NewRemoteProvider("tenant", nil)
+	registerVMIQueryModels(t, remoteProvider)
+
+	productModel := buildProductInsertModel(t, remoteProvider)
+	statusModel, statusErr := remoteProvider.GetEntityModel(&remote.ObjectValue{
+		Name:    "status",
+		PkgPath: "/vmi",
+		Fields:  []*remote.FieldValue{{Name: "id", Value: int64(9)}},
+	}, true)
+	if statusErr != nil {
+		t.Fatalf("GetEntityModel(status) failed: %v", statusErr)
+	}
+
+	executor := &fakeExecutor{}
+	ormImpl := &impl{
+		context:       context.Background(),
+		executor:      executor,
+		modelProvider: remoteProvider,
+		modelCodec:    codec.New(remoteProvider, "tenant"),
+	}
+
+	if _, err := ormImpl.BatchInsert([]models.Model{productModel, statusModel}); err == nil || err.Code != cd.IllegalParam {
+		t.Fatalf("expected mixed batch insert to be rejected, got %v", err)
+	}
+	if len(executor.execCalls) != 0 {
+		t.Fatalf("rejected batch insert should not execute SQL: %#v", executor.execCalls)
+	}
+
+	if ret, err := ormImpl.BatchInsert(nil); err != nil || len(ret) != 0 {
+		t.Fatalf("empty batch insert should be a no-op, got %v %v", ret, err)
+	}
+}
+
+func TestSplitBatchInsert(t *testing.T) {
+	remoteProvider := provider.NewRemoteProvider("tenant", nil)
+	registerVMIQueryModels(t, remoteProvider)
+
+	vModels := []models.Model{}
+	for idx := 0; idx < 5; idx++ {
+		vModels = append(vModels, buildProductInsertModel(t, remoteProvider))
+	}
+
+	if batches := splitBatchInsert(vModels, 20); len(batches) != 3 || len(batches[0]) != 2 || len(batches[2]) != 1 {
+		t.Fatalf("unexpected batches: %d", len(batches))
+	}
+	// 单个模型超过上限时独占一批
+	if batches := splitBatchInsert(vModels, 1); len(batches) != 5 {
+		t.Fatalf("unexpected batches: %d", len(batches))
+	}
+	if batches := splitBatchInsert(vModels, defaultBatchInsertParamLimit); len(batches) != 1 {
+		t.Fatalf("unexpected batches: %d", len(batches))
+	}
+}
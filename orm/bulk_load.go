@@ -0,0 +1,220 @@
+package orm
+
+import (
+	"fmt"
+	"iter"
+	"time"
+
+	cd "github.com/muidea/magicCommon/def"
+
+	"log/slog"
+
+	"github.com/muidea/magicOrm/database"
+	"github.com/muidea/magicOrm/metrics"
+	"github.com/muidea/magicOrm/models"
+	"github.com/muidea/magicOrm/validation/errors"
+)
+
+// bulkLoadSource 逐个读取待导入的模型，生成默认值并校验
+type bulkLoadSource struct {
+	orm     *impl
+	next    func() (models.Model, bool)
+	first   models.Model
+	pkgKey  string
+	columns []string
+}
+
+func (s *bulkLoadSource) nextModel() (ret models.Model, ok bool, err *cd.Error) {
+	if s.first != nil {
+		ret, s.first = s.first, nil
+	} else {
+		ret, ok = s.next()
+		if !ok {
+			return
+		}
+	}
+
+	if ret == nil {
+		err = cd.NewError(cd.IllegalParam, "illegal model value")
+		return
+	}
+	if ret.GetPkgKey() != s.pkgKey {
+		err = cd.NewError(cd.IllegalParam, fmt.Sprintf("bulk load models must be the same type, %s != %s", ret.GetPkgKey(), s.pkgKey))
+		return
+	}
+	if err = s.orm.CheckContext(); err != nil {
+		return
+	}
+
+	err = s.orm.validateModel(ret, errors.ScenarioInsert)
+	if err != nil {
+		return
+	}
+
+	prepareInsertValues(ret, currentDateTime(s.orm.context))
+	ok = true
+	return
+}
+
+// Next implements database.RowSource, 按columns顺序编码基础字段，未赋值的字段写入NULL
+func (s *bulkLoadSource) Next() (ret []any, ok bool, err *cd.Error) {
+	vModel, ok, err := s.nextModel()
+	if err != nil || !ok {
+		return
+	}
+
+	ret = make([]any, 0, len(s.columns))
+	for _, column := range s.columns {
+		field := vModel.GetField(column)
+		if !models.IsValidField(field) {
+			ret = append(ret, nil)
+			continue
+		}
+
+		encodeVal, encodeErr := s.orm.modelCodec.PackedBasicFieldValue(field, field.GetValue())
+		if encodeErr != nil {
+			err = encodeErr
+			slog.Error("BulkLoad encode field value failed", "field", column, "error", err.Error())
+			return
+		}
+		ret = append(ret, encodeVal)
+	}
+	return
+}
+
+// bulkLoadColumns 导入的列，即除自增字段外的全部基础字段
+func bulkLoadColumns(vModel models.Model) (ret []string) {
+	for _, field := range vModel.GetFields() {
+		if !models.IsBasicField(field) || field.GetSpec().GetValueDeclare() == models.AutoIncrement {
+			continue
+		}
+
+		ret = append(ret, field.GetName())
+	}
+	return
+}
+
+// batchLoad 不支持COPY的数据库使用多行INSERT分批导入
+func (s *impl) batchLoad(source *bulkLoadSource) (ret int64, err *cd.Error) {
+	paramLimit := s.batchParamLimit
+	if paramLimit <= 0 {
+		paramLimit = defaultBatchInsertParamLimit
+	}
+
+	sqlBuilder := newDatabaseBuilder(s.modelProvider, s.modelCodec)
+	flush := func(batchModels []models.Model) *cd.Error {
+		insertResult, insertErr := sqlBuilder.BuildBatchInsert(batchModels)
+		if insertErr != nil {
+			return insertErr
+		}
+
+		_, execErr := s.executor.Execute(insertResult.SQL(), insertResult.Args()...)
+		if execErr != nil {
+			return execErr
+		}
+
+		ret += int64(len(batchModels))
+		return nil
+	}
+
+	batchModels := []models.Model{}
+	paramCount := 0
+	for {
+		vModel, ok, modelErr := source.nextModel()
+		if modelErr != nil {
+			err = modelErr
+			return
+		}
+		if !ok {
+			break
+		}
+
+		modelParamCount := batchInsertParamCount(vModel)
+		if len(batchModels) > 0 && paramCount+modelParamCount > paramLimit {
+			if err = flush(batchModels); err != nil {
+				return
+			}
+			batchModels = batchModels[:0]
+			paramCount = 0
+		}
+
+		batchModels = append(batchModels, vModel)
+		paramCount += modelParamCount
+	}
+
+	if len(batchModels) > 0 {
+		err = flush(batchModels)
+	}
+	return
+}
+
+// BulkLoad 流式导入同一类型的模型，只写入host表的基础字段，返回导入的行数
+//
+// PostgreSQL 使用 COPY FROM STDIN，其他数据库退化为按参数上限分批的多行 INSERT；整个导入在一个事务中完成。
+// 与 Insert 不同，不回填自增主键，也不处理关系字段。
+func (s *impl) BulkLoad(entities iter.Seq[models.Model]) (ret int64, err *cd.Error) {
+	if len(s.interceptors) > 0 {
+		return s.interceptBulkLoad(entities)
+	}
+
+	startTime := time.Now()
+	var first models.Model
+
+	defer func() {
+		duration := time.Since(startTime)
+		if ormMetricCollector != nil && first != nil {
+			ormMetricCollector.RecordOperation(string(metrics.OperationInsert), first, duration, cd.ToStdError(err))
+		}
+	}()
+
+	if err = s.CheckContext(); err != nil {
+		return
+	}
+
+	restoreTimeout := s.applyCallTimeout()
+	defer restoreTimeout()
+
+	if entities == nil {
+		err = cd.NewError(cd.IllegalParam, "illegal bulk load entities")
+		return
+	}
+
+	next, stop := iter.Pull(entities)
+	defer stop()
+
+	first, ok := next()
+	if !ok {
+		return
+	}
+	if first == nil {
+		err = cd.NewError(cd.IllegalParam, "illegal model value")
+		return
+	}
+
+	source := &bulkLoadSource{
+		orm:     s,
+		next:    next,
+		first:   first,
+		pkgKey:  first.GetPkgKey(),
+		columns: bulkLoadColumns(first),
+	}
+
+	err = s.executor.BeginTransaction()
+	if err != nil {
+		return
+	}
+	defer func() {
+		s.finalTransaction(err)
+	}()
+
+	if loader, loaderOK := s.executor.(database.BulkLoader); loaderOK {
+		ret, err = loader.CopyIn(s.modelCodec.ConstructModelTableName(first), source.columns, source)
+	} else {
+		ret, err = s.batchLoad(source)
+	}
+	if err != nil {
+		ret = 0
+		slog.Error("BulkLoad failed", "pkgKey", first.GetPkgKey(), "error", err.Error())
+	}
+	return
+}
@@ -0,0 +1,151 @@
+package orm
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	cd "github.com/muidea/magicCommon/def"
+	"github.com/muidea/magicOrm/database"
+	"github.com/muidea/magicOrm/database/codec"
+	"github.com/muidea/magicOrm/models"
+	"github.com/muidea/magicOrm/provider"
+	"github.com/muidea/magicOrm/provider/remote"
+)
+
+// fakeBulkExecutor 支持COPY的fakeExecutor
+type fakeBulkExecutor struct {
+	fakeExecutor
+
+	copyTable   string
+	copyColumns []string
+	copyRows    [][]any
+}
+
+func (s *fakeBulkExecutor) CopyIn(tableName string, columns []string, source database.RowSource) (int64, *cd.Error) {
+	s.copyTable = tableName
+	s.copyColumns = columns
+	for {
+		values, ok, err := source.Next()
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			break
+		}
+		s.copyRows = append(s.copyRows, values)
+	}
+
+	return int64(len(s.copyRows)), nil
+}
+
+func buildBulkLoadModels(t *testing.T, remoteProvider provider.Provider, count int) []models.Model {
+	t.Helper()
+
+	ret := []models.Model{}
+	for idx := 0; idx < count; idx++ {
+		ret = append(ret, buildProductInsertModel(t, remoteProvider))
+	}
+	return ret
+}
+
+func TestBulkLoadUsesCopy(t *testing.T) {
+	remoteProvider := provider.NewRemoteProvider("tenant", nil)
+	registerVMIQueryModels(t, remoteProvider)
+
+	executor := &fakeBulkExecutor{}
+	ormImpl := &impl{
+		context:       context.Background(),
+		executor:      executor,
+		modelProvider: remoteProvider,
+		modelCodec:    codec.New(remoteProvider, "tenant"),
+	}
+
+	rowCount, err := ormImpl.BulkLoad(slices.Values(buildBulkLoadModels(t, remoteProvider, 3)))
+	if err != nil {
+		t.Fatalf("impl.BulkLoad(products) failed: %v", err)
+	}
+	if rowCount != 3 || len(executor.copyRows) != 3 {
+		t.Fatalf("unexpected bulk load rows: %d %#v", rowCount, executor.copyRows)
+	}
+	if executor.copyTable != "tenant_Product" {
+		t.Fatalf("unexpected copy table: %s", executor.copyTable)
+	}
+	if strings.Join(executor.copyColumns, ",") != "name,description,image,expire,tags,creater,createTime,modifyTime,namespace" {
+		t.Fatalf("unexpected copy columns: %v", executor.copyColumns)
+	}
+	if !argsEquivalent(executor.copyRows[0], []any{"apple", "fresh apple", `["main.png"]`, 30, `["fruit"]`, int64(0), int64(0), int64(0), ""}) {
+		t.Fatalf("unexpected copy row: %#v", executor.copyRows[0])
+	}
+	if len(executor.execCalls) != 0 {
+		t.Fatalf("copy should not issue insert statements: %#v", executor.execCalls)
+	}
+	if executor.beginCalls != 1 || executor.commitCalls != 1 {
+		t.Fatalf("bulk load should run in one transaction, got begin=%d commit=%d", executor.beginCalls, executor.commitCalls)
+	}
+}
+
+func TestBulkLoadFallsBackToBatchInsert(t *testing.T) {
+	remoteProvider := provider.NewRemoteProvider("tenant", nil)
+	registerVMIQueryModels(t, remoteProvider)
+
+	executor := &fakeExecutor{}
+	ormImpl := &impl{
+		context:         context.Background(),
+		executor:        executor,
+		modelProvider:   remoteProvider,
+		modelCodec:      codec.New(remoteProvider, "tenant"),
+		batchParamLimit: 20,
+	}
+
+	rowCount, err := ormImpl.BulkLoad(slices.Values(buildBulkLoadModels(t, remoteProvider, 3)))
+	if err != nil {
+		t.Fatalf("impl.BulkLoad(products) failed: %v", err)
+	}
+	if rowCount != 3 {
+		t.Fatalf("unexpected bulk load rows: %d", rowCount)
+	}
+	// 每个product占用9个参数，参数上限为20时拆分为2+1
+	if countCallsByKind(executor.execCalls, "exec") != 2 {
+		t.Fatalf("expected 2 batch insert statements, got %#v", executor.execCalls)
+	}
+	if !containsSQLCall(executor.execCalls, "exec", `INSERT INTO "tenant_Product"`, nil) {
+		t.Fatalf("missing batch insert statement: %#v", executor.execCalls)
+	}
+}
+
+func TestBulkLoadRejectsMixedModels(t *testing.T) {
+	remoteProvider := provider.NewRemoteProvider("tenant", nil)
+	registerVMIQueryModels(t, remoteProvider)
+
+	statusModel, statusErr := remoteProvider.GetEntityModel(&remote.ObjectValue{
+		Name:    "status",
+		PkgPath: "/vmi",
+		Fields:  []*remote.FieldValue{{Name: "id", Value: int64(9)}},
+	}, true)
+	if statusErr != nil {
+		t.Fatalf("GetEntityModel(status) failed: %v", statusErr)
+	}
+
+	executor := &fakeBulkExecutor{}
+	ormImpl := &impl{
+		context:       context.Background(),
+		executor:      executor,
+		modelProvider: remoteProvider,
+		modelCodec:    codec.New(remoteProvider, "tenant"),
+	}
+
+	vModels := append(buildBulkLoadModels(t, remoteProvider, 1), statusModel)
+	rowCount, err := ormImpl.BulkLoad(slices.Values(vModels))
+	if err == nil || err.Code != cd.IllegalParam || rowCount != 0 {
+		t.Fatalf("expected mixed bulk load to be rejected, got %d %v", rowCount, err)
+	}
+	if executor.rollbackCalls != 1 || executor.commitCalls != 0 {
+		t.Fatalf("failed bulk load should rollback, got commit=%d rollback=%d", executor.commitCalls, executor.rollbackCalls)
+	}
+
+	if rowCount, err = ormImpl.BulkLoad(slices.Values([]models.Model{})); err != nil || rowCount != 0 {
+		t.Fatalf("empty bulk load should be a no-op, got %d %v", rowCount, err)
+	}
+}
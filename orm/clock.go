@@ -0,0 +1,32 @@
+package orm
+
+import (
+	"context"
+	"time"
+
+	"github.com/muidea/magicOrm/utils"
+)
+
+type clockContextKey struct{}
+
+// ContextWithClock 返回携带时钟的context，Orm 填充 createdAt/updatedAt/datetime 与软删除时间时使用该时钟
+//
+// 主要用于测试中固定时间；clock为nil表示使用系统时间
+func ContextWithClock(ctx context.Context, clock func() time.Time) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return context.WithValue(ctx, clockContextKey{}, clock)
+}
+
+// currentDateTime 返回ctx中时钟的当前UTC时间，未设置时钟时使用系统时间
+func currentDateTime(ctx context.Context) time.Time {
+	if ctx != nil {
+		if clock, ok := ctx.Value(clockContextKey{}).(func() time.Time); ok && clock != nil {
+			return clock().UTC()
+		}
+	}
+
+	return utils.GetCurrentDateTime()
+}
@@ -43,6 +43,28 @@ func (s *CountRunner) Count(vFilter models.Filter) (ret int64, err *cd.Error) {
 		return
 	}
 
+	ret, err = s.queryCount(countResult)
+	return
+}
+
+// CountRelation 统计ownerModel的ownerField关系中满足vFilter的对象数量
+func (s *CountRunner) CountRelation(ownerModel models.Model, ownerField models.Field, vFilter models.Filter) (ret int64, err *cd.Error) {
+	if err = s.checkContext(); err != nil {
+		return
+	}
+
+	countResult, countErr := s.sqlBuilder.BuildCountRelationModels(ownerModel, ownerField, s.vModel, vFilter)
+	if countErr != nil {
+		err = countErr
+		slog.Error("CountRunner CountRelation BuildCountRelationModels failed", "error", err.Error())
+		return
+	}
+
+	ret, err = s.queryCount(countResult)
+	return
+}
+
+func (s *CountRunner) queryCount(countResult database.Result) (ret int64, err *cd.Error) {
 	_, err = s.executor.Query(countResult.SQL(), false, countResult.Args()...)
 	if err != nil {
 		return
@@ -64,6 +86,12 @@ func (s *CountRunner) Count(vFilter models.Filter) (ret int64, err *cd.Error) {
 }
 
 func (s *impl) Count(vFilter models.Filter) (ret int64, err *cd.Error) {
+	if len(s.interceptors) > 0 {
+		return s.interceptFilter(metrics.OperationCount, "Count", vFilter, nil, func(o *impl, inv *Invocation) (int64, *cd.Error) {
+			return o.Count(inv.Filter)
+		})
+	}
+
 	startTime := time.Now()
 
 	defer func() {
@@ -82,6 +110,9 @@ func (s *impl) Count(vFilter models.Filter) (ret int64, err *cd.Error) {
 		return
 	}
 
+	restoreTimeout := s.applyCallTimeout()
+	defer restoreTimeout()
+
 	if vFilter == nil {
 		err = cd.NewError(cd.IllegalParam, "illegal filter value")
 		return
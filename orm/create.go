@@ -99,6 +99,10 @@ func (s *CreateRunner) Create() (err *cd.Error) {
 }
 
 func (s *impl) Create(vModel models.Model) (err *cd.Error) {
+	if len(s.interceptors) > 0 {
+		return s.interceptSchema(metrics.OperationCreate, "Create", vModel, (*impl).Create)
+	}
+
 	startTime := time.Now()
 
 	defer func() {
@@ -112,6 +116,9 @@ func (s *impl) Create(vModel models.Model) (err *cd.Error) {
 		return
 	}
 
+	restoreTimeout := s.applyCallTimeout()
+	defer restoreTimeout()
+
 	if vModel == nil {
 		err = cd.NewError(cd.IllegalParam, "illegal model value")
 		return
@@ -4,10 +4,13 @@
 package orm
 
 import (
+	"fmt"
+
 	cd "github.com/muidea/magicCommon/def"
 	"github.com/muidea/magicOrm/database"
 	"github.com/muidea/magicOrm/database/codec"
 	"github.com/muidea/magicOrm/database/mysql"
+	"github.com/muidea/magicOrm/models"
 	"github.com/muidea/magicOrm/provider"
 )
 
@@ -21,10 +24,111 @@ func NewExecutor(config database.Config) (database.Executor, *cd.Error) {
 	return mysql.NewExecutor(config)
 }
 
-func NewConfig(dbServer, dbName, username, password string) database.Config {
-	return mysql.NewConfig(dbServer, dbName, username, password, "")
+// NewConfig NewConfig, opts 可指定TLS等连接配置
+func NewConfig(dbServer, dbName, username, password string, opts ...DatabaseOption) database.Config {
+	options := newDatabaseOptions(opts...)
+	return mysql.NewConfig(dbServer, dbName, username, password, "").WithTLS(options.tlsConfig)
 }
 
 func NewBuilder(provider provider.Provider, modelCodec codec.Codec) database.Builder {
 	return mysql.NewBuilder(provider, modelCodec)
 }
+
+// autoIncrementExecutor 提供auto_increment_increment的Executor，mysql的Executor按连接缓存该值
+type autoIncrementExecutor interface {
+	AutoIncrementIncrement() (int64, *cd.Error)
+}
+
+// getAutoIncrementIncrement 读取executor当前连接的auto_increment_increment
+func getAutoIncrementIncrement(executor database.Executor) (ret int64, err *cd.Error) {
+	if incExecutor, ok := executor.(autoIncrementExecutor); ok {
+		return incExecutor.AutoIncrementIncrement()
+	}
+
+	_, err = executor.Query("SELECT @@auto_increment_increment", false)
+	if err != nil {
+		return
+	}
+	defer executor.Finish()
+
+	if !executor.Next() {
+		err = cd.NewError(cd.Unexpected, "query auto_increment_increment returned no rows")
+		return
+	}
+	err = executor.GetField(&ret)
+	return
+}
+
+// executeBatchInsert 执行多行插入，needPK 时根据 LAST_INSERT_ID 推算每行的自增主键
+//
+// MySQL 多行插入返回第一行的自增值，同一语句内的自增值按 auto_increment_increment 递增，
+// 步长不为1的部署(如Galera、多主)按读取的步长推算
+func executeBatchInsert(executor database.Executor, result database.Result, rowCount int, needPK bool) (ret []any, err *cd.Error) {
+	if !needPK {
+		_, err = executor.Execute(result.SQL(), result.Args()...)
+		return
+	}
+
+	step, stepErr := getAutoIncrementIncrement(executor)
+	if stepErr != nil {
+		err = stepErr
+		return
+	}
+	if step <= 0 {
+		err = cd.NewError(cd.Unexpected, fmt.Sprintf("illegal auto_increment_increment %d", step))
+		return
+	}
+
+	var firstID any
+	err = executor.ExecuteInsert(result.SQL(), &firstID, result.Args()...)
+	if err != nil {
+		return
+	}
+
+	firstVal, ok := firstID.(int64)
+	if !ok {
+		err = cd.NewError(cd.Unexpected, fmt.Sprintf("illegal last insert id %v", firstID))
+		return
+	}
+
+	ret = make([]any, 0, rowCount)
+	for idx := 0; idx < rowCount; idx++ {
+		ret = append(ret, firstVal+int64(idx)*step)
+	}
+	return
+}
+
+// checkIterateFilter MySQL按主键顺序分批读取，filter指定的排序无法保持，直接拒绝而不是静默改变遍历顺序
+func checkIterateFilter(filter models.Filter) *cd.Error {
+	if filter.Sorter() != nil {
+		return cd.NewError(cd.IllegalParam, "mysql iterate does not support filter sort, rows are iterated in primary key order")
+	}
+	return nil
+}
+
+// open MySQL不支持在存储过程外声明游标，由fetch按主键续读同一查询，查询必须包含主键列
+func (s *queryCursor) open() *cd.Error {
+	if s.pkIndex < 0 {
+		return cd.NewError(cd.IllegalParam, "iterate query must select primary key")
+	}
+	return nil
+}
+
+// fetch 读取主键大于上一批最后主键的至多size行，结果集保留在executor中
+//
+// 查询作为派生表按主键排序续读，各批之间不依赖OFFSET，handler在遍历中删除或修改行不会导致跳行或重复
+func (s *queryCursor) fetch(size int) (err *cd.Error) {
+	fetchSQL := fmt.Sprintf("SELECT * FROM (%s) AS `%s`", s.query.SQL(), s.name)
+	fetchArgs := s.query.Args()
+	if s.lastPK != nil {
+		fetchSQL = fmt.Sprintf("%s WHERE `%s`.`%s` > ?", fetchSQL, s.name, s.pkName)
+		fetchArgs = append(append([]any{}, fetchArgs...), s.lastPK)
+	}
+	fetchSQL = fmt.Sprintf("%s ORDER BY `%s`.`%s` LIMIT %d", fetchSQL, s.name, s.pkName, size)
+	_, err = s.executor.Query(fetchSQL, false, fetchArgs...)
+	return
+}
+
+func (s *queryCursor) close() *cd.Error {
+	return nil
+}
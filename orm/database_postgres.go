@@ -4,10 +4,13 @@
 package orm
 
 import (
+	"fmt"
+
 	cd "github.com/muidea/magicCommon/def"
 	"github.com/muidea/magicOrm/database"
 	"github.com/muidea/magicOrm/database/codec"
 	"github.com/muidea/magicOrm/database/postgres"
+	"github.com/muidea/magicOrm/models"
 	"github.com/muidea/magicOrm/provider"
 )
 
@@ -21,10 +24,63 @@ func NewExecutor(config database.Config) (database.Executor, *cd.Error) {
 	return postgres.NewExecutor(config)
 }
 
-func NewConfig(dbServer, dbName, username, password string) database.Config {
-	return postgres.NewConfig(dbServer, dbName, username, password)
+// NewConfig NewConfig, opts 可指定TLS等连接配置
+func NewConfig(dbServer, dbName, username, password string, opts ...DatabaseOption) database.Config {
+	options := newDatabaseOptions(opts...)
+	return postgres.NewConfig(dbServer, dbName, username, password).WithTLS(options.tlsConfig)
 }
 
 func NewBuilder(provider provider.Provider, modelCodec codec.Codec) database.Builder {
 	return postgres.NewBuilder(provider, modelCodec)
 }
+
+// executeBatchInsert 执行多行插入，needPK 时按行返回 RETURNING 的主键
+func executeBatchInsert(executor database.Executor, result database.Result, rowCount int, needPK bool) (ret []any, err *cd.Error) {
+	if !needPK {
+		_, err = executor.Execute(result.SQL(), result.Args()...)
+		return
+	}
+
+	_, err = executor.Query(result.SQL(), false, result.Args()...)
+	if err != nil {
+		return
+	}
+	defer executor.Finish()
+
+	ret = make([]any, 0, rowCount)
+	for executor.Next() {
+		var pkVal any
+		err = executor.GetField(&pkVal)
+		if err != nil {
+			return
+		}
+		ret = append(ret, pkVal)
+	}
+
+	if len(ret) != rowCount {
+		err = cd.NewError(cd.Unexpected, fmt.Sprintf("batch insert returned %d keys, expect %d", len(ret), rowCount))
+	}
+	return
+}
+
+// checkIterateFilter PostgreSQL服务端游标保持filter的排序，无额外限制
+func checkIterateFilter(filter models.Filter) *cd.Error {
+	return nil
+}
+
+// open 在当前事务中为查询声明服务端游标，结果保留在数据库端，由fetch分批读取
+func (s *queryCursor) open() (err *cd.Error) {
+	_, err = s.executor.Execute(fmt.Sprintf("DECLARE \"%s\" NO SCROLL CURSOR FOR %s", s.name, s.query.SQL()), s.query.Args()...)
+	return
+}
+
+// fetch 读取下一批至多size行，结果集保留在executor中
+func (s *queryCursor) fetch(size int) (err *cd.Error) {
+	_, err = s.executor.Query(fmt.Sprintf("FETCH FORWARD %d FROM \"%s\"", size, s.name), false)
+	return
+}
+
+func (s *queryCursor) close() (err *cd.Error) {
+	_, err = s.executor.Execute(fmt.Sprintf("CLOSE \"%s\"", s.name))
+	return
+}
@@ -19,6 +19,9 @@ import (
 type DeleteRunner struct {
 	baseRunner
 	QueryRunner
+
+	// hardDelete 为true时声明了软删除字段的模型也物理删除，包含关系的子对象沿用该设置
+	hardDelete bool
 }
 
 func NewDeleteRunner(
@@ -52,6 +55,41 @@ func (s *DeleteRunner) deleteHost(vModel models.Model) (err *cd.Error) {
 	return
 }
 
+func (s *DeleteRunner) softDeleteHost(vModel models.Model) (err *cd.Error) {
+	deletedField := models.GetSoftDeleteField(vModel)
+	err = deletedField.SetValue(currentDateTime(s.context))
+	if err != nil {
+		slog.Error("DeleteRunner softDeleteHost SetValue failed", "field", deletedField.GetName(), "error", err.Error())
+		return
+	}
+
+	deleteResult, deleteErr := s.sqlBuilder.BuildSoftDelete(vModel)
+	if deleteErr != nil {
+		err = deleteErr
+		slog.Error("DeleteRunner softDeleteHost BuildSoftDelete failed", "error", err.Error())
+		return
+	}
+
+	_, err = s.executor.Execute(deleteResult.SQL(), deleteResult.Args()...)
+	if err != nil {
+		slog.Error("DeleteRunner softDeleteHost Execute failed", "error", err.Error())
+	}
+	return
+}
+
+// isSoftDeleteRelation 关联实体声明了软删除字段且不是物理删除时，关联实体只做软删除
+func (s *DeleteRunner) isSoftDeleteRelation(vField models.Field) bool {
+	if s.hardDelete {
+		return false
+	}
+
+	rModel, rErr := s.modelProvider.GetTypeModel(vField.GetType().Elem())
+	if rErr != nil {
+		return false
+	}
+	return models.GetSoftDeleteField(rModel) != nil
+}
+
 func (s *DeleteRunner) deleteRelation(vModel models.Model, vField models.Field, deepLevel int) (err *cd.Error) {
 	hostResult, relationResult, resultErr := s.sqlBuilder.BuildDeleteRelation(vModel, vField)
 	if resultErr != nil {
@@ -85,10 +123,12 @@ func (s *DeleteRunner) deleteRelation(vModel models.Model, vField models.Field,
 			}
 		}
 
-		_, err = s.executor.Execute(hostResult.SQL(), hostResult.Args()...)
-		if err != nil {
-			slog.Error("DeleteRunner failed", "error", err.Error())
-			return
+		if !s.isSoftDeleteRelation(vField) {
+			_, err = s.executor.Execute(hostResult.SQL(), hostResult.Args()...)
+			if err != nil {
+				slog.Error("DeleteRunner failed", "error", err.Error())
+				return
+			}
 		}
 	}
 
@@ -115,6 +155,7 @@ func (s *DeleteRunner) deleteRelationSingleStructInner(vField models.Field, deep
 	}
 
 	rRunner := NewDeleteRunner(s.context, rModel, s.executor, s.modelProvider, s.modelCodec, deepLevel+1)
+	rRunner.hardDelete = s.hardDelete
 	err = rRunner.Delete()
 	if err != nil {
 		slog.Error("DeleteRunner failed", "error", err.Error())
@@ -141,6 +182,7 @@ func (s *DeleteRunner) deleteRelationSliceStructInner(vField models.Field, deepL
 			return
 		}
 		rRunner := NewDeleteRunner(s.context, rModel, s.executor, s.modelProvider, s.modelCodec, deepLevel+1)
+		rRunner.hardDelete = s.hardDelete
 		err = rRunner.Delete()
 		if err != nil {
 			slog.Error("DeleteRunner failed", "error", err.Error())
@@ -156,6 +198,30 @@ func (s *DeleteRunner) Delete() (err *cd.Error) {
 		return
 	}
 
+	err = invokeHooks(s.context, s.vModel, BeforeDelete)
+	if err != nil {
+		return
+	}
+
+	err = s.deleteEntity()
+	if err != nil {
+		return
+	}
+
+	err = invokeHooks(s.context, s.vModel, AfterDelete)
+	return
+}
+
+func (s *DeleteRunner) deleteEntity() (err *cd.Error) {
+	// 软删除只标记host，关系保持不变以便Restore
+	if !s.hardDelete && models.GetSoftDeleteField(s.vModel) != nil {
+		err = s.softDeleteHost(s.vModel)
+		if err != nil {
+			slog.Error("DeleteRunner failed", "error", err.Error())
+		}
+		return
+	}
+
 	err = s.deleteHost(s.vModel)
 	if err != nil {
 		slog.Error("DeleteRunner failed", "error", err.Error())
@@ -177,7 +243,25 @@ func (s *DeleteRunner) Delete() (err *cd.Error) {
 	return
 }
 
+// Delete 删除单条，声明了软删除字段的模型只写入删除时间
 func (s *impl) Delete(vModel models.Model) (ret models.Model, err *cd.Error) {
+	if len(s.interceptors) > 0 {
+		return s.interceptModel(metrics.OperationDelete, "Delete", vModel, (*impl).Delete)
+	}
+
+	return s.delete(vModel, false)
+}
+
+// HardDelete 物理删除单条，忽略软删除声明
+func (s *impl) HardDelete(vModel models.Model) (ret models.Model, err *cd.Error) {
+	if len(s.interceptors) > 0 {
+		return s.interceptModel(metrics.OperationDelete, "HardDelete", vModel, (*impl).HardDelete)
+	}
+
+	return s.delete(vModel, true)
+}
+
+func (s *impl) delete(vModel models.Model, hardDelete bool) (ret models.Model, err *cd.Error) {
 	startTime := time.Now()
 
 	defer func() {
@@ -191,6 +275,9 @@ func (s *impl) Delete(vModel models.Model) (ret models.Model, err *cd.Error) {
 		return
 	}
 
+	restoreTimeout := s.applyCallTimeout()
+	defer restoreTimeout()
+
 	if vModel == nil {
 		err = cd.NewError(cd.IllegalParam, "illegal model value")
 		return
@@ -212,6 +299,7 @@ func (s *impl) Delete(vModel models.Model) (ret models.Model, err *cd.Error) {
 	}()
 
 	deleteRunner := NewDeleteRunner(s.context, vModel, s.executor, s.modelProvider, s.modelCodec, 0)
+	deleteRunner.hardDelete = hardDelete
 	err = deleteRunner.Delete()
 	if err != nil {
 		slog.Error("Delete DeleteRunner.Delete failed", "pkgKey", vModel.GetPkgKey(), "error", err.Error())
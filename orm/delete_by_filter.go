@@ -0,0 +1,260 @@
+package orm
+
+import (
+	"context"
+	"time"
+
+	cd "github.com/muidea/magicCommon/def"
+
+	"log/slog"
+
+	"github.com/muidea/magicOrm/database"
+	"github.com/muidea/magicOrm/database/codec"
+	"github.com/muidea/magicOrm/metrics"
+	"github.com/muidea/magicOrm/models"
+	"github.com/muidea/magicOrm/provider"
+)
+
+// DeleteByFilterRunner 按filter批量删除，关系表的清理规则与DeleteRunner一致
+type DeleteByFilterRunner struct {
+	baseRunner
+
+	// hardDelete 为true时声明了软删除字段的模型也物理删除
+	hardDelete bool
+}
+
+func NewDeleteByFilterRunner(
+	ctx context.Context,
+	vModel models.Model,
+	executor database.Executor,
+	provider provider.Provider,
+	modelCodec codec.Codec) *DeleteByFilterRunner {
+	return &DeleteByFilterRunner{
+		baseRunner: newBaseRunner(ctx, vModel, executor, provider, modelCodec, false, 0),
+	}
+}
+
+// queryRelationKeys 查询满足filter的host关联的全部关联实体主键
+func (s *DeleteByFilterRunner) queryRelationKeys(vField models.Field, vFilter models.Filter) (ret []any, err *cd.Error) {
+	relationResult, relationErr := s.sqlBuilder.BuildQueryRelationByFilter(s.vModel, vField, vFilter)
+	if relationErr != nil {
+		err = relationErr
+		slog.Error("DeleteByFilterRunner queryRelationKeys failed", "field", vField.GetName(), "error", err.Error())
+		return
+	}
+
+	_, err = s.executor.Query(relationResult.SQL(), false, relationResult.Args()...)
+	if err != nil {
+		slog.Error("DeleteByFilterRunner queryRelationKeys failed", "field", vField.GetName(), "error", err.Error())
+		return
+	}
+	defer s.executor.Finish()
+
+	for s.executor.Next() {
+		var idVal any
+		err = s.executor.GetField(&idVal)
+		if err != nil {
+			slog.Error("DeleteByFilterRunner queryRelationKeys failed", "field", vField.GetName(), "error", err.Error())
+			return
+		}
+		ret = append(ret, idVal)
+	}
+	return
+}
+
+// deleteRelationModels 包含关系的关联实体随host一起删除，逐个交给DeleteRunner以清理其自身的关系
+func (s *DeleteByFilterRunner) deleteRelationModels(vField models.Field, vFilter models.Filter) (err *cd.Error) {
+	relationKeys, keysErr := s.queryRelationKeys(vField, vFilter)
+	if keysErr != nil {
+		err = keysErr
+		return
+	}
+
+	relationType := vField.GetType()
+	if models.IsSliceField(vField) {
+		relationType = relationType.Elem()
+	}
+	for _, relationKey := range relationKeys {
+		rModel, rErr := s.modelProvider.GetTypeModel(relationType)
+		if rErr != nil {
+			err = rErr
+			slog.Error("DeleteByFilterRunner deleteRelationModels failed", "field", vField.GetName(), "error", err.Error())
+			return
+		}
+
+		rVal, rErr := s.modelCodec.ExtractBasicFieldValue(rModel.GetPrimaryField(), relationKey)
+		if rErr != nil {
+			err = rErr
+			slog.Error("DeleteByFilterRunner deleteRelationModels failed", "field", vField.GetName(), "error", err.Error())
+			return
+		}
+
+		err = rModel.SetPrimaryFieldValue(rVal)
+		if err != nil {
+			return
+		}
+
+		rRunner := NewDeleteRunner(s.context, rModel, s.executor, s.modelProvider, s.modelCodec, 1)
+		rRunner.hardDelete = s.hardDelete
+		err = rRunner.Delete()
+		if err != nil {
+			slog.Error("DeleteByFilterRunner deleteRelationModels failed", "field", vField.GetName(), "error", err.Error())
+			return
+		}
+	}
+	return
+}
+
+func (s *DeleteByFilterRunner) deleteRelation(vField models.Field, vFilter models.Filter) (err *cd.Error) {
+	// 元素类型不是指针表示包含关系，需要先删除关联实体
+	if !vField.GetType().Elem().IsPtrType() {
+		err = s.deleteRelationModels(vField, vFilter)
+		if err != nil {
+			return
+		}
+	}
+
+	relationResult, relationErr := s.sqlBuilder.BuildDeleteRelationByFilter(s.vModel, vField, vFilter)
+	if relationErr != nil {
+		err = relationErr
+		slog.Error("DeleteByFilterRunner deleteRelation failed", "field", vField.GetName(), "error", err.Error())
+		return
+	}
+
+	_, err = s.executor.Execute(relationResult.SQL(), relationResult.Args()...)
+	if err != nil {
+		slog.Error("DeleteByFilterRunner deleteRelation failed", "field", vField.GetName(), "error", err.Error())
+	}
+	return
+}
+
+// softDelete 批量写入删除时间，与Delete一样不处理关系
+func (s *DeleteByFilterRunner) softDelete(vFilter models.Filter) (ret int64, err *cd.Error) {
+	deletedModel := s.vModel.Copy(models.MetaView)
+	deletedField := models.GetSoftDeleteField(deletedModel)
+	err = deletedField.SetValue(currentDateTime(s.context))
+	if err != nil {
+		slog.Error("DeleteByFilterRunner softDelete SetValue failed", "field", deletedField.GetName(), "error", err.Error())
+		return
+	}
+
+	deleteResult, deleteErr := s.sqlBuilder.BuildUpdateByFilter(deletedModel, vFilter, []string{deletedField.GetName()})
+	if deleteErr != nil {
+		err = deleteErr
+		slog.Error("DeleteByFilterRunner softDelete BuildUpdateByFilter failed", "error", err.Error())
+		return
+	}
+
+	ret, err = s.executor.Execute(deleteResult.SQL(), deleteResult.Args()...)
+	if err != nil {
+		slog.Error("DeleteByFilterRunner softDelete Execute failed", "error", err.Error())
+	}
+	return
+}
+
+// Delete 关系表的行通过host主键子查询定位，因此必须先于host删除
+func (s *DeleteByFilterRunner) Delete(vFilter models.Filter) (ret int64, err *cd.Error) {
+	if err = s.checkContext(); err != nil {
+		return
+	}
+
+	if !s.hardDelete && models.GetSoftDeleteField(s.vModel) != nil {
+		ret, err = s.softDelete(vFilter)
+		return
+	}
+
+	for _, field := range s.vModel.GetFields() {
+		if models.IsBasicField(field) {
+			continue
+		}
+
+		err = s.deleteRelation(field, vFilter)
+		if err != nil {
+			slog.Error("DeleteByFilterRunner failed", "error", err.Error())
+			return
+		}
+	}
+
+	deleteResult, deleteErr := s.sqlBuilder.BuildDeleteByFilter(s.vModel, vFilter)
+	if deleteErr != nil {
+		err = deleteErr
+		slog.Error("DeleteByFilterRunner BuildDeleteByFilter failed", "error", err.Error())
+		return
+	}
+
+	ret, err = s.executor.Execute(deleteResult.SQL(), deleteResult.Args()...)
+	if err != nil {
+		slog.Error("DeleteByFilterRunner Execute failed", "error", err.Error())
+	}
+	return
+}
+
+// DeleteByFilter 删除满足filter的全部行，返回host表受影响的行数
+//
+// 引用关系只删除关系表中的行；包含关系的关联实体与 Delete 一样随host一起删除。
+// 声明了软删除字段的模型与 Delete 一样只写入删除时间。
+// 整个删除在一个事务中完成，filter的分页与排序被忽略。
+func (s *impl) DeleteByFilter(vFilter models.Filter) (ret int64, err *cd.Error) {
+	if len(s.interceptors) > 0 {
+		return s.interceptFilter(metrics.OperationDelete, "DeleteByFilter", vFilter, nil, func(o *impl, inv *Invocation) (int64, *cd.Error) {
+			return o.DeleteByFilter(inv.Filter)
+		})
+	}
+
+	return s.deleteByFilter(vFilter, false)
+}
+
+// HardDeleteByFilter 物理删除满足filter的全部行，忽略软删除声明
+//
+// 软删除的行默认被filter排除，清理已删除的行需要 filter.Deleted(models.OnlyDeleted)。
+func (s *impl) HardDeleteByFilter(vFilter models.Filter) (ret int64, err *cd.Error) {
+	if len(s.interceptors) > 0 {
+		return s.interceptFilter(metrics.OperationDelete, "HardDeleteByFilter", vFilter, nil, func(o *impl, inv *Invocation) (int64, *cd.Error) {
+			return o.HardDeleteByFilter(inv.Filter)
+		})
+	}
+
+	return s.deleteByFilter(vFilter, true)
+}
+
+func (s *impl) deleteByFilter(vFilter models.Filter, hardDelete bool) (ret int64, err *cd.Error) {
+	startTime := time.Now()
+	var vModel models.Model
+
+	defer func() {
+		duration := time.Since(startTime)
+		if ormMetricCollector != nil {
+			ormMetricCollector.RecordOperation(string(metrics.OperationDelete), vModel, duration, cd.ToStdError(err))
+		}
+	}()
+
+	if err = s.CheckContext(); err != nil {
+		return
+	}
+
+	restoreTimeout := s.applyCallTimeout()
+	defer restoreTimeout()
+
+	if vFilter == nil {
+		err = cd.NewError(cd.IllegalParam, "illegal filter value")
+		return
+	}
+
+	vModel = vFilter.MaskModel()
+	err = s.executor.BeginTransaction()
+	if err != nil {
+		return
+	}
+	defer func() {
+		s.finalTransaction(err)
+	}()
+
+	deleteRunner := NewDeleteByFilterRunner(s.context, vModel, s.executor, s.modelProvider, s.modelCodec)
+	deleteRunner.hardDelete = hardDelete
+	ret, err = deleteRunner.Delete(vFilter)
+	if err != nil {
+		ret = 0
+		slog.Error("DeleteByFilter DeleteByFilterRunner.Delete failed", "pkgKey", vModel.GetPkgKey(), "error", err.Error())
+	}
+	return
+}
@@ -0,0 +1,141 @@
+package orm
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/muidea/magicOrm/database/codec"
+	"github.com/muidea/magicOrm/models"
+	"github.com/muidea/magicOrm/provider"
+	"github.com/muidea/magicOrm/provider/remote"
+)
+
+func TestDeleteByFilterVMIRemoteReferenceRelation(t *testing.T) {
+	remoteProvider := provider.NewRemoteProvider("tenant", nil)
+	registerVMIQueryModels(t, remoteProvider)
+
+	filter, err := remoteProvider.GetModelFilter(buildProductQueryModel(t, remoteProvider))
+	if err != nil {
+		t.Fatalf("GetModelFilter(product) failed: %v", err)
+	}
+	if err = filter.Below("expire", 10); err != nil {
+		t.Fatalf("filter.Below(expire) failed: %v", err)
+	}
+
+	executor := &fakeExecutor{rowsAffected: 2}
+	ormImpl := &impl{
+		context:       context.Background(),
+		executor:      executor,
+		modelProvider: remoteProvider,
+		modelCodec:    codec.New(remoteProvider, "tenant"),
+	}
+
+	affected, err := ormImpl.DeleteByFilter(filter)
+	if err != nil {
+		t.Fatalf("impl.DeleteByFilter(product) failed: %v", err)
+	}
+	if affected != 2 {
+		t.Fatalf("expected 2 affected rows, got %d", affected)
+	}
+
+	wantCalls := []string{
+		`DELETE FROM "tenant_ProductStatus3Status" WHERE "left" IN (SELECT "id" FROM "tenant_Product" WHERE "expire" < $1)`,
+		`DELETE FROM "tenant_Product" WHERE "expire" < $1`,
+	}
+	if len(executor.execCalls) != len(wantCalls) {
+		t.Fatalf("unexpected delete by filter calls: %#v", executor.execCalls)
+	}
+	for idx, wantSQL := range wantCalls {
+		if executor.execCalls[idx].kind != "exec" || executor.execCalls[idx].sql != wantSQL {
+			t.Fatalf("unexpected delete by filter call %d: %#v", idx, executor.execCalls[idx])
+		}
+	}
+	if executor.beginCalls != 1 || executor.commitCalls != 1 {
+		t.Fatalf("delete by filter should run in one transaction, got begin=%d commit=%d", executor.beginCalls, executor.commitCalls)
+	}
+}
+
+func testDeleteBoxObject() *remote.Object {
+	return &remote.Object{
+		Name:    "box",
+		PkgPath: "/bench",
+		Fields: []*remote.Field{
+			{
+				Name: "id",
+				Type: &remote.TypeImpl{Name: "int64", Value: models.TypeBigIntegerValue},
+				Spec: &remote.SpecImpl{FieldName: "id", PrimaryKey: true, ViewDeclare: []models.ViewDeclare{models.DetailView, models.LiteView}},
+			},
+			{
+				Name: "name",
+				Type: &remote.TypeImpl{Name: "string", Value: models.TypeStringValue},
+				Spec: &remote.SpecImpl{FieldName: "name", ViewDeclare: []models.ViewDeclare{models.DetailView, models.LiteView}},
+			},
+			{
+				Name: "items",
+				Type: &remote.TypeImpl{
+					Name:    "items",
+					PkgPath: "/bench",
+					Value:   models.TypeSliceValue,
+					ElemType: &remote.TypeImpl{
+						Name:    "child",
+						PkgPath: "/bench",
+						Value:   models.TypeStructValue,
+					},
+				},
+				Spec: &remote.SpecImpl{FieldName: "items", ViewDeclare: []models.ViewDeclare{models.DetailView}},
+			},
+		},
+	}
+}
+
+func TestDeleteByFilterDeletesContainedModels(t *testing.T) {
+	remoteProvider := provider.NewRemoteProvider("tenant", nil)
+	registerMinimalRelationModels(t, remoteProvider)
+	boxModel, err := remoteProvider.RegisterModel(testDeleteBoxObject())
+	if err != nil {
+		t.Fatalf("RegisterModel(box) failed: %v", err)
+	}
+
+	filter, err := remoteProvider.GetModelFilter(boxModel)
+	if err != nil {
+		t.Fatalf("GetModelFilter(box) failed: %v", err)
+	}
+	if err = filter.Equal("name", "empty"); err != nil {
+		t.Fatalf("filter.Equal(name) failed: %v", err)
+	}
+
+	executor := &fakeExecutor{
+		responses: []fakeQueryResponse{
+			{
+				match: func(sql string, _ []any) bool {
+					return strings.HasPrefix(sql, `SELECT "right" FROM "tenant_BoxItems2Child" WHERE "left" IN (SELECT "id" FROM "tenant_Box"`)
+				},
+				rows: [][]any{{int64(7)}, {int64(8)}},
+			},
+		},
+	}
+	ormImpl := &impl{
+		context:       context.Background(),
+		executor:      executor,
+		modelProvider: remoteProvider,
+		modelCodec:    codec.New(remoteProvider, "tenant"),
+	}
+
+	if _, err = ormImpl.DeleteByFilter(filter); err != nil {
+		t.Fatalf("impl.DeleteByFilter(box) failed: %v", err)
+	}
+
+	for _, childID := range []int64{7, 8} {
+		if !containsSQLCall(executor.execCalls, "exec", `DELETE FROM "tenant_Child" WHERE "id" = $1`, []any{childID}) {
+			t.Fatalf("missing contained child delete %d: %#v", childID, executor.execCalls)
+		}
+	}
+	lastCall := executor.execCalls[len(executor.execCalls)-1]
+	if lastCall.sql != `DELETE FROM "tenant_Box" WHERE "name" = $1` {
+		t.Fatalf("host rows should be deleted last, got %#v", executor.execCalls)
+	}
+	if !containsSQLCall(executor.execCalls, "exec", `DELETE FROM "tenant_BoxItems2Child" WHERE "left" IN`, []any{"empty"}) {
+		t.Fatalf("missing relation delete: %#v", executor.execCalls)
+	}
+}
@@ -95,6 +95,10 @@ func (s *DropRunner) Drop() (err *cd.Error) {
 }
 
 func (s *impl) Drop(vModel models.Model) (err *cd.Error) {
+	if len(s.interceptors) > 0 {
+		return s.interceptSchema(metrics.OperationDrop, "Drop", vModel, (*impl).Drop)
+	}
+
 	startTime := time.Now()
 
 	defer func() {
@@ -104,6 +108,13 @@ func (s *impl) Drop(vModel models.Model) (err *cd.Error) {
 		}
 	}()
 
+	if err = s.CheckContext(); err != nil {
+		return
+	}
+
+	restoreTimeout := s.applyCallTimeout()
+	defer restoreTimeout()
+
 	if vModel == nil {
 		err = cd.NewError(cd.IllegalParam, "illegal model value")
 		return
@@ -11,7 +11,11 @@ import (
 )
 
 // BatchQuery batch query
-func (s *impl) BatchQuery(filter models.Filter) (ret []models.Model, err *cd.Error) {
+func (s *impl) BatchQuery(filter models.Filter, opts ...LoadOption) (ret []models.Model, err *cd.Error) {
+	if len(s.interceptors) > 0 {
+		return s.interceptBatchQuery(filter, opts)
+	}
+
 	startTime := time.Now()
 
 	defer func() {
@@ -26,6 +30,13 @@ func (s *impl) BatchQuery(filter models.Filter) (ret []models.Model, err *cd.Err
 		}
 	}()
 
+	if err = s.CheckContext(); err != nil {
+		return
+	}
+
+	restoreTimeout := s.applyCallTimeout()
+	defer restoreTimeout()
+
 	if filter == nil {
 		err = cd.NewError(cd.IllegalParam, "filter is nil")
 		slog.Error("BatchQuery: filter is nil")
@@ -47,6 +58,7 @@ func (s *impl) BatchQuery(filter models.Filter) (ret []models.Model, err *cd.Err
 	}
 
 	vQueryRunner := NewQueryRunner(s.context, queryMask, responseModel, responseByMask, s.executor, s.modelProvider, s.modelCodec, true, 0)
+	vQueryRunner.loadOptions = newLoadOptions(opts...)
 	queryVal, queryErr := vQueryRunner.Query(filter)
 	if queryErr != nil {
 		err = queryErr
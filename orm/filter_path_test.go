@@ -0,0 +1,102 @@
+package orm
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	cd "github.com/muidea/magicCommon/def"
+
+	"github.com/muidea/magicOrm/database/codec"
+	"github.com/muidea/magicOrm/provider"
+)
+
+type depthTeam struct {
+	ID   int    `orm:"id key auto"`
+	Name string `orm:"name"`
+}
+
+type depthProfile struct {
+	ID   int       `orm:"id key auto"`
+	Team depthTeam `orm:"team"`
+}
+
+type depthUser struct {
+	ID      int          `orm:"id key auto"`
+	Profile depthProfile `orm:"profile"`
+}
+
+func TestCountByFilterPathLocal(t *testing.T) {
+	executor := &fakeExecutor{
+		responses: []fakeQueryResponse{
+			{
+				match: func(querySQL string, _ []any) bool { return strings.HasPrefix(querySQL, "SELECT COUNT(*)") },
+				rows:  [][]any{{sql.NullInt64{Int64: 1, Valid: true}}},
+			},
+		},
+	}
+	ormImpl, userModel := newUpdateFieldsTestOrm(t, executor, &fieldsUser{})
+
+	filter, err := ormImpl.modelProvider.GetModelFilter(userModel)
+	if err != nil {
+		t.Fatalf("GetModelFilter(fieldsUser) failed: %v", err)
+	}
+	if err = filter.Equal("profile.email", "ops@example.com"); err != nil {
+		t.Fatalf("filter.Equal(profile.email) failed: %v", err)
+	}
+
+	count, err := ormImpl.Count(filter)
+	if err != nil || count != 1 {
+		t.Fatalf("Count by filter path failed, count:%d, err:%v", count, err)
+	}
+	if !containsSQLCall(executor.execCalls, "query", `SELECT COUNT(*) FROM "tenant_FieldsUser" WHERE "id" IN (SELECT "left" FROM "tenant_FieldsUserProfile`, nil) ||
+		!containsSQLCall(executor.execCalls, "query", `WHERE "right" IN (SELECT "id" FROM "tenant_FieldsProfile" WHERE "email" = $1))`, []any{"ops@example.com"}) {
+		t.Fatalf("unexpected filter path calls: %#v", executor.execCalls)
+	}
+}
+
+func TestFilterPathDepthOption(t *testing.T) {
+	const owner = "filter-depth"
+	localProvider := provider.NewLocalProvider(owner, nil)
+	for _, entity := range []any{&depthTeam{}, &depthProfile{}, &depthUser{}} {
+		if _, err := localProvider.RegisterModel(entity); err != nil {
+			t.Fatalf("RegisterModel(%T) failed: %v", entity, err)
+		}
+	}
+	userModel, err := localProvider.GetEntityModel(&depthUser{}, true)
+	if err != nil {
+		t.Fatalf("GetEntityModel(depthUser) failed: %v", err)
+	}
+	filter, err := localProvider.GetModelFilter(userModel)
+	if err != nil {
+		t.Fatalf("GetModelFilter(depthUser) failed: %v", err)
+	}
+	if err = filter.Equal("profile.team.name", "ops"); err != nil {
+		t.Fatalf("filter.Equal(profile.team.name) failed: %v", err)
+	}
+
+	executor := &fakeExecutor{
+		responses: []fakeQueryResponse{
+			{
+				match: func(querySQL string, _ []any) bool { return strings.HasPrefix(querySQL, "SELECT COUNT(*)") },
+				rows:  [][]any{{sql.NullInt64{Int64: 1, Valid: true}}},
+			},
+		},
+	}
+	ormImpl := &impl{
+		context:       context.Background(),
+		executor:      executor,
+		modelProvider: localProvider,
+		modelCodec:    codec.New(localProvider, "tenant"),
+	}
+	if _, err = ormImpl.Count(filter); err != nil {
+		t.Fatalf("Count within default filter path depth failed: %v", err)
+	}
+
+	name2Options.Store(owner, newDatabaseOptions(WithFilterPathDepth(1)))
+	defer name2Options.Delete(owner)
+	if _, err = ormImpl.Count(filter); err == nil || err.Code != cd.IllegalParam {
+		t.Fatalf("expected filter path deeper than the database option to fail, got %v", err)
+	}
+}
@@ -0,0 +1,281 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	cd "github.com/muidea/magicCommon/def"
+
+	"log/slog"
+
+	"github.com/muidea/magicOrm/models"
+)
+
+// HookEvent 模型生命周期事件
+type HookEvent string
+
+const (
+	BeforeInsert HookEvent = "beforeInsert"
+	AfterInsert  HookEvent = "afterInsert"
+	BeforeUpdate HookEvent = "beforeUpdate"
+	AfterUpdate  HookEvent = "afterUpdate"
+	BeforeDelete HookEvent = "beforeDelete"
+	AfterDelete  HookEvent = "afterDelete"
+	BeforeQuery  HookEvent = "beforeQuery"
+	AfterQuery   HookEvent = "afterQuery"
+)
+
+// BeforeInsertHook 本地实体实现该接口后，Insert写入host前调用
+type BeforeInsertHook interface {
+	BeforeInsert(ctx context.Context) error
+}
+
+// AfterInsertHook 本地实体实现该接口后，Insert写完host与关系后调用，此时主键已回填
+type AfterInsertHook interface {
+	AfterInsert(ctx context.Context) error
+}
+
+// BeforeUpdateHook 本地实体实现该接口后，Update更新host前调用
+type BeforeUpdateHook interface {
+	BeforeUpdate(ctx context.Context) error
+}
+
+// AfterUpdateHook 本地实体实现该接口后，Update更新完host与关系后调用
+type AfterUpdateHook interface {
+	AfterUpdate(ctx context.Context) error
+}
+
+// BeforeDeleteHook 本地实体实现该接口后，Delete删除前调用
+type BeforeDeleteHook interface {
+	BeforeDelete(ctx context.Context) error
+}
+
+// AfterDeleteHook 本地实体实现该接口后，Delete删除后调用
+type AfterDeleteHook interface {
+	AfterDelete(ctx context.Context) error
+}
+
+// BeforeQueryHook 本地实体实现该接口后，Query按模型生成过滤条件前调用
+type BeforeQueryHook interface {
+	BeforeQuery(ctx context.Context) error
+}
+
+// AfterQueryHook 本地实体实现该接口后，每个查询结果加载完成后调用
+type AfterQueryHook interface {
+	AfterQuery(ctx context.Context) error
+}
+
+// ModelHook 按pkgKey注册的钩子，remote模型通过它获得与本地实体相同的生命周期回调
+type ModelHook func(ctx context.Context, vModel models.Model) error
+
+var (
+	hookLock     sync.RWMutex
+	pkgKey2Hooks = map[string]map[HookEvent][]ModelHook{}
+)
+
+// RegisterHook 为pkgKey对应的模型注册钩子，同一事件的多个钩子按注册顺序执行
+func RegisterHook(pkgKey string, event HookEvent, hook ModelHook) {
+	if hook == nil {
+		return
+	}
+
+	hookLock.Lock()
+	defer hookLock.Unlock()
+
+	eventHooks, ok := pkgKey2Hooks[pkgKey]
+	if !ok {
+		eventHooks = map[HookEvent][]ModelHook{}
+		pkgKey2Hooks[pkgKey] = eventHooks
+	}
+	eventHooks[event] = append(eventHooks[event], hook)
+}
+
+// UnregisterHooks 移除pkgKey对应模型的全部钩子
+func UnregisterHooks(pkgKey string) {
+	hookLock.Lock()
+	defer hookLock.Unlock()
+
+	delete(pkgKey2Hooks, pkgKey)
+}
+
+func getRegisteredHooks(pkgKey string, event HookEvent) []ModelHook {
+	hookLock.RLock()
+	defer hookLock.RUnlock()
+
+	return pkgKey2Hooks[pkgKey][event]
+}
+
+type entityHookKey struct {
+	modelType reflect.Type
+	pkgKey    string
+	event     HookEvent
+}
+
+// entityHookTypes 按模型类型记录是否实现了事件方法，没有实体方法的模型(如remote模型)不必每次构造实体
+var entityHookTypes sync.Map
+
+// getEntityHook 返回本地实体实现的事件方法，remote模型没有实体方法
+func getEntityHook(vModel models.Model, event HookEvent) func(ctx context.Context) error {
+	hookKey := entityHookKey{modelType: reflect.TypeOf(vModel), pkgKey: vModel.GetPkgKey(), event: event}
+	if implemented, ok := entityHookTypes.Load(hookKey); ok && !implemented.(bool) {
+		return nil
+	}
+
+	entityHook := lookupEntityHook(vModel.Interface(true), event)
+	entityHookTypes.Store(hookKey, entityHook != nil)
+	return entityHook
+}
+
+func lookupEntityHook(entity any, event HookEvent) func(ctx context.Context) error {
+	switch event {
+	case BeforeInsert:
+		if hook, ok := entity.(BeforeInsertHook); ok {
+			return hook.BeforeInsert
+		}
+	case AfterInsert:
+		if hook, ok := entity.(AfterInsertHook); ok {
+			return hook.AfterInsert
+		}
+	case BeforeUpdate:
+		if hook, ok := entity.(BeforeUpdateHook); ok {
+			return hook.BeforeUpdate
+		}
+	case AfterUpdate:
+		if hook, ok := entity.(AfterUpdateHook); ok {
+			return hook.AfterUpdate
+		}
+	case BeforeDelete:
+		if hook, ok := entity.(BeforeDeleteHook); ok {
+			return hook.BeforeDelete
+		}
+	case AfterDelete:
+		if hook, ok := entity.(AfterDeleteHook); ok {
+			return hook.AfterDelete
+		}
+	case BeforeQuery:
+		if hook, ok := entity.(BeforeQueryHook); ok {
+			return hook.BeforeQuery
+		}
+	case AfterQuery:
+		if hook, ok := entity.(AfterQueryHook); ok {
+			return hook.AfterQuery
+		}
+	}
+
+	return nil
+}
+
+// hasHook 模型是否存在event对应的实体方法或注册钩子
+func hasHook(vModel models.Model, event HookEvent) bool {
+	if vModel == nil {
+		return false
+	}
+
+	return getEntityHook(vModel, event) != nil || len(getRegisteredHooks(vModel.GetPkgKey(), event)) > 0
+}
+
+// invokeHooks 先调用实体方法，再按注册顺序调用注册钩子，任一失败即中止
+//
+// 钩子返回 *cd.Error 时保留其错误码，其他错误统一为 Unexpected。
+func invokeHooks(ctx context.Context, vModel models.Model, event HookEvent) (err *cd.Error) {
+	if vModel == nil {
+		return
+	}
+
+	hooks := getRegisteredHooks(vModel.GetPkgKey(), event)
+	entityHook := getEntityHook(vModel, event)
+	if entityHook == nil && len(hooks) == 0 {
+		return
+	}
+
+	var preValues map[string]any
+	if isBeforeWriteEvent(event) {
+		preValues = getBasicFieldValues(vModel)
+	}
+
+	if entityHook != nil {
+		if hookErr := entityHook(ctx); !isNilHookError(hookErr) {
+			err = toHookError(vModel, event, hookErr)
+			return
+		}
+	}
+	for _, hook := range hooks {
+		if hookErr := hook(ctx, vModel); !isNilHookError(hookErr) {
+			err = toHookError(vModel, event, hookErr)
+			return
+		}
+	}
+
+	if preValues != nil {
+		markHookAssignedFields(vModel, preValues)
+	}
+	return
+}
+
+// isNilHookError 钩子直接返回 nil 的 *cd.Error 时，error 接口不为nil，这里一并视为成功
+func isNilHookError(hookErr error) bool {
+	if hookErr == nil {
+		return true
+	}
+
+	cdErr, ok := hookErr.(*cd.Error)
+	return ok && cdErr == nil
+}
+
+func isBeforeWriteEvent(event HookEvent) bool {
+	return event == BeforeInsert || event == BeforeUpdate || event == BeforeQuery
+}
+
+// getBasicFieldValues 记录钩子执行前未标记赋值的基础字段值
+func getBasicFieldValues(vModel models.Model) map[string]any {
+	ret := map[string]any{}
+	for _, field := range vModel.GetFields() {
+		if !models.IsBasicField(field) || models.IsAssignedField(field) {
+			continue
+		}
+
+		ret[field.GetName()] = copyBasicValue(field.GetValue().Get())
+	}
+	return ret
+}
+
+// copyBasicValue 复制切片值，避免钩子原地修改元素后与执行前的值比较相等
+func copyBasicValue(val any) any {
+	rVal := reflect.ValueOf(val)
+	if rVal.Kind() != reflect.Slice || rVal.IsNil() {
+		return val
+	}
+
+	ret := reflect.MakeSlice(rVal.Type(), rVal.Len(), rVal.Len())
+	reflect.Copy(ret, rVal)
+	return ret.Interface()
+}
+
+// markHookAssignedFields 本地实体方法直接修改结构体字段时不会标记赋值，这里按钩子执行前后的值补上标记，
+// 使Update与Query能识别钩子写入的值，包括钩子清空为零值的字段
+func markHookAssignedFields(vModel models.Model, preValues map[string]any) {
+	for _, field := range vModel.GetFields() {
+		preVal, ok := preValues[field.GetName()]
+		if !ok || models.IsAssignedField(field) || reflect.DeepEqual(preVal, field.GetValue().Get()) {
+			continue
+		}
+
+		if setErr := field.SetValue(field.GetValue().Get()); setErr != nil {
+			slog.Warn("mark hook assigned field failed", "field", field.GetName(), "error", setErr.Error())
+		}
+	}
+}
+
+func toHookError(vModel models.Model, event HookEvent, hookErr error) *cd.Error {
+	var cdErr *cd.Error
+	if errors.As(hookErr, &cdErr) && cdErr != nil {
+		slog.Error("model hook failed", "pkgKey", vModel.GetPkgKey(), "event", event, "error", cdErr.Error())
+		return cdErr
+	}
+
+	slog.Error("model hook failed", "pkgKey", vModel.GetPkgKey(), "event", event, "error", hookErr.Error())
+	return cd.NewError(cd.Unexpected, fmt.Sprintf("%s hook failed, model:%s, error:%s", event, vModel.GetPkgKey(), hookErr.Error()))
+}
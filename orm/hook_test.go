@@ -0,0 +1,223 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	cd "github.com/muidea/magicCommon/def"
+
+	"github.com/muidea/magicOrm/database/codec"
+	"github.com/muidea/magicOrm/models"
+	"github.com/muidea/magicOrm/provider"
+	"github.com/muidea/magicOrm/provider/remote"
+)
+
+type hookLocalNote struct {
+	ID   int    `orm:"id key auto"`
+	Name string `orm:"name"`
+}
+
+var hookLocalNoteInsertedID int
+
+func (s *hookLocalNote) BeforeInsert(_ context.Context) error {
+	if s.Name == "" {
+		s.Name = "untitled"
+	}
+	return nil
+}
+
+func (s *hookLocalNote) AfterInsert(_ context.Context) error {
+	hookLocalNoteInsertedID = s.ID
+	return nil
+}
+
+func testHookMemoObject() *remote.Object {
+	return &remote.Object{
+		Name:    "memo",
+		PkgPath: "/bench",
+		Fields: []*remote.Field{
+			{
+				Name: "id",
+				Type: &remote.TypeImpl{Name: "int64", Value: models.TypeBigIntegerValue},
+				Spec: &remote.SpecImpl{FieldName: "id", PrimaryKey: true, ViewDeclare: []models.ViewDeclare{models.DetailView, models.LiteView}},
+			},
+			{
+				Name: "title",
+				Type: &remote.TypeImpl{Name: "string", Value: models.TypeStringValue},
+				Spec: &remote.SpecImpl{FieldName: "title", ViewDeclare: []models.ViewDeclare{models.DetailView, models.LiteView}},
+			},
+		},
+	}
+}
+
+func newHookTestOrm(t *testing.T, executor *fakeExecutor) (*impl, models.Model) {
+	t.Helper()
+
+	remoteProvider := provider.NewRemoteProvider("tenant", nil)
+	if _, err := remoteProvider.RegisterModel(testHookMemoObject()); err != nil {
+		t.Fatalf("RegisterModel(memo) failed: %v", err)
+	}
+
+	memoModel, err := remoteProvider.GetEntityModel(&remote.ObjectValue{
+		Name:    "memo",
+		PkgPath: "/bench",
+		Fields: []*remote.FieldValue{
+			{Name: "id", Value: int64(7)},
+			{Name: "title", Value: "draft"},
+		},
+	}, true)
+	if err != nil {
+		t.Fatalf("GetEntityModel(memo) failed: %v", err)
+	}
+	t.Cleanup(func() { UnregisterHooks(memoModel.GetPkgKey()) })
+
+	ormImpl := &impl{
+		context:       context.Background(),
+		executor:      executor,
+		modelProvider: remoteProvider,
+		modelCodec:    codec.New(remoteProvider, "tenant"),
+	}
+	return ormImpl, memoModel
+}
+
+func TestLocalEntityInsertHooks(t *testing.T) {
+	localProvider := provider.NewLocalProvider("tenant", nil)
+	if _, err := localProvider.RegisterModel(&hookLocalNote{}); err != nil {
+		t.Fatalf("RegisterModel(hookLocalNote) failed: %v", err)
+	}
+
+	note := &hookLocalNote{}
+	noteModel, err := localProvider.GetEntityModel(note, true)
+	if err != nil {
+		t.Fatalf("GetEntityModel(hookLocalNote) failed: %v", err)
+	}
+
+	executor := &fakeExecutor{insertIDs: []any{int64(11)}}
+	ormImpl := &impl{
+		context:       context.Background(),
+		executor:      executor,
+		modelProvider: localProvider,
+		modelCodec:    codec.New(localProvider, "tenant"),
+	}
+	if _, err = ormImpl.Insert(noteModel); err != nil {
+		t.Fatalf("impl.Insert(hookLocalNote) failed: %v", err)
+	}
+	if !containsSQLCall(executor.execCalls, "insert", `INSERT INTO "tenant_HookLocalNote"`, []any{"untitled"}) {
+		t.Fatalf("BeforeInsert value should be written, got %#v", executor.execCalls)
+	}
+	if hookLocalNoteInsertedID != 11 {
+		t.Fatalf("AfterInsert should see the generated id, got %d", hookLocalNoteInsertedID)
+	}
+}
+
+func TestRegisteredHookAbortsUpdate(t *testing.T) {
+	executor := &fakeExecutor{rowsAffected: 1}
+	ormImpl, memoModel := newHookTestOrm(t, executor)
+
+	RegisterHook(memoModel.GetPkgKey(), BeforeUpdate, func(_ context.Context, vModel models.Model) error {
+		return cd.NewError(cd.IllegalParam, "memo is locked")
+	})
+
+	_, err := ormImpl.Update(memoModel)
+	if err == nil || err.Code != cd.IllegalParam {
+		t.Fatalf("expected hook error to abort update, got %v", err)
+	}
+	if len(executor.execCalls) != 0 {
+		t.Fatalf("aborted update should not execute sql, got %#v", executor.execCalls)
+	}
+	if executor.beginCalls != 1 || executor.rollbackCalls != 1 {
+		t.Fatalf("update with hooks should roll back, begin:%d rollback:%d", executor.beginCalls, executor.rollbackCalls)
+	}
+}
+
+func TestRegisteredAfterDeleteHookRollsBack(t *testing.T) {
+	executor := &fakeExecutor{rowsAffected: 1}
+	ormImpl, memoModel := newHookTestOrm(t, executor)
+
+	RegisterHook(memoModel.GetPkgKey(), AfterDelete, func(_ context.Context, _ models.Model) error {
+		return errors.New("audit unavailable")
+	})
+
+	_, err := ormImpl.Delete(memoModel)
+	if err == nil || err.Code != cd.Unexpected || !strings.Contains(err.Message, "audit unavailable") {
+		t.Fatalf("expected wrapped hook error, got %v", err)
+	}
+	if !containsSQLCall(executor.execCalls, "exec", `DELETE FROM "tenant_Memo" WHERE "id" = $1`, nil) {
+		t.Fatalf("delete should run before AfterDelete, got %#v", executor.execCalls)
+	}
+	if executor.rollbackCalls != 1 || executor.commitCalls != 0 {
+		t.Fatalf("AfterDelete failure should roll back, commit:%d rollback:%d", executor.commitCalls, executor.rollbackCalls)
+	}
+}
+
+func TestRegisteredQueryHooks(t *testing.T) {
+	executor := &fakeExecutor{
+		responses: []fakeQueryResponse{
+			{
+				match: func(querySQL string, _ []any) bool { return strings.HasPrefix(querySQL, "SELECT") },
+				rows:  [][]any{{int64(7), "draft"}},
+			},
+		},
+	}
+	ormImpl, memoModel := newHookTestOrm(t, executor)
+
+	beforeCalls := 0
+	RegisterHook(memoModel.GetPkgKey(), BeforeQuery, func(_ context.Context, _ models.Model) error {
+		beforeCalls++
+		return nil
+	})
+	RegisterHook(memoModel.GetPkgKey(), AfterQuery, func(_ context.Context, vModel models.Model) error {
+		return vModel.SetFieldValue("title", strings.ToUpper(vModel.GetField("title").GetValue().Get().(string)))
+	})
+
+	queryModel, err := ormImpl.Query(memoModel)
+	if err != nil {
+		t.Fatalf("impl.Query(memo) failed: %v", err)
+	}
+	if beforeCalls != 1 {
+		t.Fatalf("BeforeQuery should be invoked once, got %d", beforeCalls)
+	}
+	if queryModel.GetField("title").GetValue().Get() != "DRAFT" {
+		t.Fatalf("AfterQuery should update the result, got %v", queryModel.GetField("title").GetValue().Get())
+	}
+}
+
+type hookLocalFlag struct {
+	ID     int    `orm:"id key auto"`
+	Name   string `orm:"name"`
+	Active bool   `orm:"active"`
+}
+
+func (s *hookLocalFlag) BeforeUpdate(_ context.Context) error {
+	s.Name = strings.TrimSpace(s.Name)
+	s.Active = false
+	return nil
+}
+
+func TestLocalEntityHookClearsFields(t *testing.T) {
+	localProvider := provider.NewLocalProvider("tenant", nil)
+	if _, err := localProvider.RegisterModel(&hookLocalFlag{}); err != nil {
+		t.Fatalf("RegisterModel(hookLocalFlag) failed: %v", err)
+	}
+
+	flagModel, err := localProvider.GetEntityModel(&hookLocalFlag{ID: 3, Name: "  ", Active: true}, true)
+	if err != nil {
+		t.Fatalf("GetEntityModel(hookLocalFlag) failed: %v", err)
+	}
+
+	executor := &fakeExecutor{rowsAffected: 1}
+	ormImpl := &impl{
+		context:       context.Background(),
+		executor:      executor,
+		modelProvider: localProvider,
+		modelCodec:    codec.New(localProvider, "tenant"),
+	}
+	if _, err = ormImpl.Update(flagModel); err != nil {
+		t.Fatalf("impl.Update(hookLocalFlag) failed: %v", err)
+	}
+	if !containsSQLCall(executor.execCalls, "exec", `UPDATE "tenant_HookLocalFlag" SET "name" = $1,"active" = $2`, []any{"", false, 3}) {
+		t.Fatalf("fields cleared by BeforeUpdate should be written, got %#v", executor.execCalls)
+	}
+}
@@ -47,8 +47,8 @@ func NewInsertRunner(
 	}
 }
 
-func (s *InsertRunner) insertHost(vModel models.Model) (err *cd.Error) {
-	autoIncrementFlag := false
+// prepareInsertValues 为未赋值的UUID、Snowflake、DateTime、创建/更新时间与版本字段生成值，返回模型是否包含自增字段
+func prepareInsertValues(vModel models.Model, now time.Time) (autoIncrementFlag bool) {
 	for _, field := range vModel.GetFields() {
 		if !models.IsBasicField(field) {
 			continue
@@ -70,15 +70,44 @@ func (s *InsertRunner) insertHost(vModel models.Model) (err *cd.Error) {
 					slog.Warn("Failed to set snowflake ID", "error", setErr)
 				}
 			}
-		case models.DateTime:
+		case models.DateTime, models.CreatedAt, models.UpdatedAt:
 			if vVal.IsZero() {
-				if setErr := vVal.Set(utils.GetCurrentDateTime()); setErr != nil {
+				if setErr := vVal.Set(now); setErr != nil {
 					slog.Warn("Failed to set datetime", "error", setErr)
 				}
 			}
+		case models.Version:
+			if vVal.IsZero() {
+				if setErr := increaseVersionValue(field); setErr != nil {
+					slog.Warn("Failed to set initial version", "error", setErr)
+				}
+			}
 		}
 	}
 
+	return
+}
+
+// assignPrimaryKey 将数据库返回的自增主键回填到模型
+func (s *baseRunner) assignPrimaryKey(vModel models.Model, pkVal any) (err *cd.Error) {
+	pkField := vModel.GetPrimaryField()
+	vVal, vErr := s.modelCodec.ExtractBasicFieldValue(pkField, pkVal)
+	if vErr != nil {
+		err = vErr
+		slog.Error("InsertRunner insertHost ExtractBasicFieldValue/SetValue failed", "field", pkField.GetName(), "error", err.Error())
+		return
+	}
+	err = pkField.SetValue(vVal)
+	if err != nil {
+		slog.Error("InsertRunner insertHost ExtractBasicFieldValue/SetValue failed", "field", pkField.GetName(), "error", err.Error())
+		return
+	}
+	return
+}
+
+func (s *InsertRunner) insertHost(vModel models.Model) (err *cd.Error) {
+	autoIncrementFlag := prepareInsertValues(vModel, currentDateTime(s.context))
+
 	pkVal, pkErr := s.innerHost(vModel)
 	if pkErr != nil {
 		err = pkErr
@@ -87,18 +116,7 @@ func (s *InsertRunner) insertHost(vModel models.Model) (err *cd.Error) {
 	}
 
 	if pkVal != nil && autoIncrementFlag {
-		pkField := vModel.GetPrimaryField()
-		vVal, vErr := s.modelCodec.ExtractBasicFieldValue(pkField, pkVal)
-		if vErr != nil {
-			err = vErr
-			slog.Error("InsertRunner insertHost ExtractBasicFieldValue/SetValue failed", "field", pkField.GetName(), "error", err.Error())
-			return
-		}
-		err = pkField.SetValue(vVal)
-		if err != nil {
-			slog.Error("InsertRunner insertHost ExtractBasicFieldValue/SetValue failed", "field", pkField.GetName(), "error", err.Error())
-			return
-		}
+		err = s.assignPrimaryKey(vModel, pkVal)
 	}
 	return
 }
@@ -242,6 +260,11 @@ func (s *InsertRunner) Insert() (ret models.Model, err *cd.Error) {
 		return
 	}
 
+	err = invokeHooks(s.context, s.vModel, BeforeInsert)
+	if err != nil {
+		return
+	}
+
 	err = s.insertHost(s.vModel)
 	if err != nil {
 		slog.Error("InsertRunner insertHost failed", "error", err.Error())
@@ -272,11 +295,20 @@ func (s *InsertRunner) Insert() (ret models.Model, err *cd.Error) {
 		}
 	}
 
+	err = invokeHooks(s.context, s.vModel, AfterInsert)
+	if err != nil {
+		return
+	}
+
 	ret, err = projectWriteResponseModel(s.vModel, s.modelProvider)
 	return
 }
 
 func (s *impl) Insert(vModel models.Model) (ret models.Model, err *cd.Error) {
+	if len(s.interceptors) > 0 {
+		return s.interceptModel(metrics.OperationInsert, "Insert", vModel, (*impl).Insert)
+	}
+
 	startTime := time.Now()
 
 	defer func() {
@@ -290,6 +322,9 @@ func (s *impl) Insert(vModel models.Model) (ret models.Model, err *cd.Error) {
 		return
 	}
 
+	restoreTimeout := s.applyCallTimeout()
+	defer restoreTimeout()
+
 	if vModel == nil {
 		err = cd.NewError(cd.IllegalParam, "illegal model value")
 		return
@@ -0,0 +1,266 @@
+package orm
+
+import (
+	"context"
+	"iter"
+
+	cd "github.com/muidea/magicCommon/def"
+
+	"github.com/muidea/magicOrm/metrics"
+	"github.com/muidea/magicOrm/models"
+)
+
+// Invocation 一次Orm调用，拦截器可在调用next前修改入参，或在next返回后读取、修改结果
+type Invocation struct {
+	// Context 执行本次调用使用的context，拦截器替换后对后续拦截器与SQL执行生效
+	Context context.Context
+	// Operation 操作类型，与metrics记录的类型一致
+	Operation metrics.OperationType
+	// Method 调用的Orm方法名，如 Insert、HardDelete、UpdateByFilter
+	Method string
+
+	// Model Create/Drop/Insert/Update/UpdateFields/Delete/HardDelete/Restore/Query/QueryByIDs/LoadRelation/QueryRelation/CountRelation/RawQuery 的模型
+	Model models.Model
+	// Models BatchInsert/BatchLoadRelation 的模型列表
+	Models []models.Model
+	// Entities BulkLoad 的模型序列
+	Entities iter.Seq[models.Model]
+	// Filter Count/BatchQuery/Iterate、QueryRelation/CountRelation、UpdateByExpr 以及 ByFilter 操作的过滤条件
+	Filter models.Filter
+	// Assignments UpdateByFilter 的字段赋值
+	Assignments map[string]any
+	// FieldNames UpdateFields 指定更新的字段
+	FieldNames []string
+	// Exprs UpdateByExpr 的更新表达式
+	Exprs []models.UpdateExpr
+	// Guards UpdateByExpr 的更新条件
+	Guards []models.UpdateGuard
+	// RelationField LoadRelation/BatchLoadRelation/QueryRelation/CountRelation 的关系字段
+	RelationField string
+	// IDs QueryByIDs 查询的主键列表
+	IDs []any
+	// LoadOptions Query/QueryByIDs/BatchQuery/Iterate/LoadRelation/BatchLoadRelation/QueryRelation 的关系加载选项
+	LoadOptions []LoadOption
+	// Handler Iterate 处理每个模型的回调
+	Handler func(models.Model) *cd.Error
+	// SQL RawQuery/RawExec 执行的原生SQL
+	SQL string
+	// Args RawQuery/RawExec 的SQL参数
+	Args []any
+
+	// ResultModel 返回单个模型的操作结果
+	ResultModel models.Model
+	// ResultModels BatchInsert/BatchQuery/QueryByIDs/BatchLoadRelation/QueryRelation/RawQuery 的结果
+	ResultModels []models.Model
+	// ResultMissingIDs QueryByIDs 未查询到的主键
+	ResultMissingIDs []any
+	// ResultCount Count、CountRelation、BulkLoad、UpdateByExpr、RawExec 与 ByFilter 操作的行数
+	ResultCount int64
+}
+
+// InvocationHandler 执行一次调用，结果写回Invocation
+type InvocationHandler func(inv *Invocation) *cd.Error
+
+// Interceptor 包裹每次Orm调用，不调用next即短路本次调用，此时由拦截器负责填写结果
+type Interceptor func(inv *Invocation, next InvocationHandler) *cd.Error
+
+// WithInterceptors 为owner下GetOrm获取的Orm注册拦截器，按注册顺序由外向内执行
+func WithInterceptors(interceptors ...Interceptor) DatabaseOption {
+	return func(o *databaseOptions) {
+		for _, interceptor := range interceptors {
+			if interceptor != nil {
+				o.interceptors = append(o.interceptors, interceptor)
+			}
+		}
+	}
+}
+
+// WithInterceptors 返回共享当前连接和事务的Orm，在已有拦截器之内追加interceptors
+//
+// 返回的Orm不持有连接，Release不会释放连接，仍需调用原Orm的Release
+func (s *impl) WithInterceptors(interceptors ...Interceptor) Orm {
+	ret := *s
+	ret.interceptors = make([]Interceptor, 0, len(s.interceptors)+len(interceptors))
+	ret.interceptors = append(ret.interceptors, s.interceptors...)
+	for _, interceptor := range interceptors {
+		if interceptor != nil {
+			ret.interceptors = append(ret.interceptors, interceptor)
+		}
+	}
+	ret.borrowed = true
+	return &ret
+}
+
+// intercept 依次经过拦截器后，以inv中的入参和context在不带拦截器的Orm上执行call，
+// 调用期间executor使用inv.Context执行SQL
+func (s *impl) intercept(inv *Invocation, call func(o *impl, inv *Invocation) *cd.Error) *cd.Error {
+	inv.Context = s.context
+	handler := func(inv *Invocation) *cd.Error {
+		o := *s
+		o.context = inv.Context
+		o.interceptors = nil
+		o.borrowed = true
+		restoreContext := o.applyContext()
+		defer restoreContext()
+		return call(&o, inv)
+	}
+
+	for idx := len(s.interceptors) - 1; idx >= 0; idx-- {
+		interceptor := s.interceptors[idx]
+		next := handler
+		handler = func(inv *Invocation) *cd.Error {
+			return interceptor(inv, next)
+		}
+	}
+
+	return handler(inv)
+}
+
+func (s *impl) interceptModel(operation metrics.OperationType, method string, vModel models.Model, call func(o *impl, vModel models.Model) (models.Model, *cd.Error)) (models.Model, *cd.Error) {
+	inv := &Invocation{Operation: operation, Method: method, Model: vModel}
+	err := s.intercept(inv, func(o *impl, inv *Invocation) (err *cd.Error) {
+		inv.ResultModel, err = call(o, inv.Model)
+		return
+	})
+	return inv.ResultModel, err
+}
+
+func (s *impl) interceptSchema(operation metrics.OperationType, method string, vModel models.Model, call func(o *impl, vModel models.Model) *cd.Error) *cd.Error {
+	inv := &Invocation{Operation: operation, Method: method, Model: vModel}
+	return s.intercept(inv, func(o *impl, inv *Invocation) *cd.Error {
+		return call(o, inv.Model)
+	})
+}
+
+func (s *impl) interceptFilter(operation metrics.OperationType, method string, vFilter models.Filter, assignments map[string]any, call func(o *impl, inv *Invocation) (int64, *cd.Error)) (int64, *cd.Error) {
+	inv := &Invocation{Operation: operation, Method: method, Filter: vFilter, Assignments: assignments}
+	err := s.intercept(inv, func(o *impl, inv *Invocation) (err *cd.Error) {
+		inv.ResultCount, err = call(o, inv)
+		return
+	})
+	return inv.ResultCount, err
+}
+
+func (s *impl) interceptUpdateFields(vModel models.Model, fieldNames []string) (models.Model, *cd.Error) {
+	inv := &Invocation{Operation: metrics.OperationUpdate, Method: "UpdateFields", Model: vModel, FieldNames: fieldNames}
+	err := s.intercept(inv, func(o *impl, inv *Invocation) (err *cd.Error) {
+		inv.ResultModel, err = o.UpdateFields(inv.Model, inv.FieldNames...)
+		return
+	})
+	return inv.ResultModel, err
+}
+
+func (s *impl) interceptUpdateByExpr(vFilter models.Filter, exprs []models.UpdateExpr, guards []models.UpdateGuard) (int64, *cd.Error) {
+	inv := &Invocation{Operation: metrics.OperationUpdate, Method: "UpdateByExpr", Filter: vFilter, Exprs: exprs, Guards: guards}
+	err := s.intercept(inv, func(o *impl, inv *Invocation) (err *cd.Error) {
+		inv.ResultCount, err = o.UpdateByExpr(inv.Filter, inv.Exprs, inv.Guards...)
+		return
+	})
+	return inv.ResultCount, err
+}
+
+func (s *impl) interceptBatchInsert(vModels []models.Model) ([]models.Model, *cd.Error) {
+	inv := &Invocation{Operation: metrics.OperationInsert, Method: "BatchInsert", Models: vModels}
+	err := s.intercept(inv, func(o *impl, inv *Invocation) (err *cd.Error) {
+		inv.ResultModels, err = o.BatchInsert(inv.Models)
+		return
+	})
+	return inv.ResultModels, err
+}
+
+func (s *impl) interceptBulkLoad(entities iter.Seq[models.Model]) (int64, *cd.Error) {
+	inv := &Invocation{Operation: metrics.OperationInsert, Method: "BulkLoad", Entities: entities}
+	err := s.intercept(inv, func(o *impl, inv *Invocation) (err *cd.Error) {
+		inv.ResultCount, err = o.BulkLoad(inv.Entities)
+		return
+	})
+	return inv.ResultCount, err
+}
+
+func (s *impl) interceptQuery(vModel models.Model, opts []LoadOption) (models.Model, *cd.Error) {
+	inv := &Invocation{Operation: metrics.OperationQuery, Method: "Query", Model: vModel, LoadOptions: opts}
+	err := s.intercept(inv, func(o *impl, inv *Invocation) (err *cd.Error) {
+		inv.ResultModel, err = o.Query(inv.Model, inv.LoadOptions...)
+		return
+	})
+	return inv.ResultModel, err
+}
+
+func (s *impl) interceptBatchQuery(vFilter models.Filter, opts []LoadOption) ([]models.Model, *cd.Error) {
+	inv := &Invocation{Operation: metrics.OperationBatch, Method: "BatchQuery", Filter: vFilter, LoadOptions: opts}
+	err := s.intercept(inv, func(o *impl, inv *Invocation) (err *cd.Error) {
+		inv.ResultModels, err = o.BatchQuery(inv.Filter, inv.LoadOptions...)
+		return
+	})
+	return inv.ResultModels, err
+}
+
+func (s *impl) interceptLoadRelation(vModel models.Model, fieldName string, opts []LoadOption) (models.Model, *cd.Error) {
+	inv := &Invocation{Operation: metrics.OperationQuery, Method: "LoadRelation", Model: vModel, RelationField: fieldName, LoadOptions: opts}
+	err := s.intercept(inv, func(o *impl, inv *Invocation) (err *cd.Error) {
+		inv.ResultModel, err = o.LoadRelation(inv.Model, inv.RelationField, inv.LoadOptions...)
+		return
+	})
+	return inv.ResultModel, err
+}
+
+func (s *impl) interceptBatchLoadRelation(vModels []models.Model, fieldName string, opts []LoadOption) ([]models.Model, *cd.Error) {
+	inv := &Invocation{Operation: metrics.OperationBatch, Method: "BatchLoadRelation", Models: vModels, RelationField: fieldName, LoadOptions: opts}
+	err := s.intercept(inv, func(o *impl, inv *Invocation) (err *cd.Error) {
+		inv.ResultModels, err = o.BatchLoadRelation(inv.Models, inv.RelationField, inv.LoadOptions...)
+		return
+	})
+	return inv.ResultModels, err
+}
+
+func (s *impl) interceptQueryRelation(vModel models.Model, fieldName string, vFilter models.Filter, opts []LoadOption) ([]models.Model, *cd.Error) {
+	inv := &Invocation{Operation: metrics.OperationBatch, Method: "QueryRelation", Model: vModel, RelationField: fieldName, Filter: vFilter, LoadOptions: opts}
+	err := s.intercept(inv, func(o *impl, inv *Invocation) (err *cd.Error) {
+		inv.ResultModels, err = o.QueryRelation(inv.Model, inv.RelationField, inv.Filter, inv.LoadOptions...)
+		return
+	})
+	return inv.ResultModels, err
+}
+
+func (s *impl) interceptCountRelation(vModel models.Model, fieldName string, vFilter models.Filter) (int64, *cd.Error) {
+	inv := &Invocation{Operation: metrics.OperationCount, Method: "CountRelation", Model: vModel, RelationField: fieldName, Filter: vFilter}
+	err := s.intercept(inv, func(o *impl, inv *Invocation) (err *cd.Error) {
+		inv.ResultCount, err = o.CountRelation(inv.Model, inv.RelationField, inv.Filter)
+		return
+	})
+	return inv.ResultCount, err
+}
+
+func (s *impl) interceptIterate(vFilter models.Filter, handler func(models.Model) *cd.Error, opts []LoadOption) *cd.Error {
+	inv := &Invocation{Operation: metrics.OperationBatch, Method: "Iterate", Filter: vFilter, Handler: handler, LoadOptions: opts}
+	return s.intercept(inv, func(o *impl, inv *Invocation) *cd.Error {
+		return o.Iterate(inv.Filter, inv.Handler, inv.LoadOptions...)
+	})
+}
+
+func (s *impl) interceptRawQuery(vModel models.Model, querySQL string, args []any) ([]models.Model, *cd.Error) {
+	inv := &Invocation{Operation: metrics.OperationRaw, Method: "RawQuery", Model: vModel, SQL: querySQL, Args: args}
+	err := s.intercept(inv, func(o *impl, inv *Invocation) (err *cd.Error) {
+		inv.ResultModels, err = o.RawQuery(inv.Model, inv.SQL, inv.Args...)
+		return
+	})
+	return inv.ResultModels, err
+}
+
+func (s *impl) interceptRawExec(execSQL string, args []any) (int64, *cd.Error) {
+	inv := &Invocation{Operation: metrics.OperationRaw, Method: "RawExec", SQL: execSQL, Args: args}
+	err := s.intercept(inv, func(o *impl, inv *Invocation) (err *cd.Error) {
+		inv.ResultCount, err = o.RawExec(inv.SQL, inv.Args...)
+		return
+	})
+	return inv.ResultCount, err
+}
+
+func (s *impl) interceptQueryByIDs(vModel models.Model, ids []any, opts []LoadOption) ([]models.Model, []any, *cd.Error) {
+	inv := &Invocation{Operation: metrics.OperationBatch, Method: "QueryByIDs", Model: vModel, IDs: ids, LoadOptions: opts}
+	err := s.intercept(inv, func(o *impl, inv *Invocation) (err *cd.Error) {
+		inv.ResultModels, inv.ResultMissingIDs, err = o.QueryByIDs(inv.Model, inv.IDs, inv.LoadOptions...)
+		return
+	})
+	return inv.ResultModels, inv.ResultMissingIDs, err
+}
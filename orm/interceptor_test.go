@@ -0,0 +1,154 @@
+package orm
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	cd "github.com/muidea/magicCommon/def"
+
+	"github.com/muidea/magicOrm/metrics"
+	"github.com/muidea/magicOrm/models"
+	"github.com/muidea/magicOrm/provider/remote"
+)
+
+func TestInterceptorOrderAndShortCircuit(t *testing.T) {
+	executor := &fakeExecutor{rowsAffected: 1}
+	ormImpl, memoModel := newHookTestOrm(t, executor)
+
+	trace := []string{}
+	outer := func(inv *Invocation, next InvocationHandler) *cd.Error {
+		trace = append(trace, "outer:"+string(inv.Operation)+":"+inv.Method)
+		err := next(inv)
+		trace = append(trace, "outer:done")
+		return err
+	}
+	deny := func(inv *Invocation, next InvocationHandler) *cd.Error {
+		trace = append(trace, "deny")
+		if inv.Method == "HardDelete" {
+			return cd.NewError(cd.Unauthorized, "hard delete is not allowed")
+		}
+		return next(inv)
+	}
+
+	interceptedOrm := ormImpl.WithInterceptors(outer).WithInterceptors(deny)
+	_, err := interceptedOrm.HardDelete(memoModel)
+	if err == nil || err.Code != cd.Unauthorized {
+		t.Fatalf("expected interceptor to short circuit, got %v", err)
+	}
+	if len(executor.execCalls) != 0 || executor.beginCalls != 0 {
+		t.Fatalf("short circuited call should not reach the database, got %#v", executor.execCalls)
+	}
+	if strings.Join(trace, ",") != "outer:delete:HardDelete,deny,outer:done" {
+		t.Fatalf("unexpected interceptor order: %v", trace)
+	}
+
+	if _, err = interceptedOrm.Delete(memoModel); err != nil {
+		t.Fatalf("Delete through interceptors failed: %v", err)
+	}
+	if !containsSQLCall(executor.execCalls, "exec", `DELETE FROM "tenant_Memo" WHERE "id" = $1`, nil) {
+		t.Fatalf("allowed call should reach the database, got %#v", executor.execCalls)
+	}
+	if len(ormImpl.interceptors) != 0 {
+		t.Fatal("WithInterceptors should not change the source Orm")
+	}
+}
+
+func TestInterceptorRewritesFilter(t *testing.T) {
+	executor := &fakeExecutor{
+		responses: []fakeQueryResponse{
+			{
+				match: func(querySQL string, _ []any) bool { return strings.HasPrefix(querySQL, "SELECT COUNT(*)") },
+				rows:  [][]any{{sql.NullInt64{Int64: 2, Valid: true}}},
+			},
+		},
+	}
+	ormImpl, memoModel := newHookTestOrm(t, executor)
+
+	rowFilter := func(inv *Invocation, next InvocationHandler) *cd.Error {
+		if inv.Filter != nil {
+			if err := inv.Filter.Equal("title", "visible"); err != nil {
+				return err
+			}
+		}
+		return next(inv)
+	}
+
+	filter, err := ormImpl.modelProvider.GetModelFilter(memoModel)
+	if err != nil {
+		t.Fatalf("GetModelFilter(memo) failed: %v", err)
+	}
+	count, err := ormImpl.WithInterceptors(rowFilter).Count(filter)
+	if err != nil || count != 2 {
+		t.Fatalf("Count through interceptors failed, count:%d, err:%v", count, err)
+	}
+	if !containsSQLCall(executor.execCalls, "query", `SELECT COUNT(*) FROM "tenant_Memo" WHERE "title" = $1`, []any{"visible"}) {
+		t.Fatalf("interceptor filter should be applied, got %#v", executor.execCalls)
+	}
+}
+
+func TestInterceptorReplacesContext(t *testing.T) {
+	clockNow := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	interceptorNow := clockNow.Add(24 * time.Hour)
+	executor := &fakeExecutor{}
+	ormImpl, _, postModel := newTimestampTestOrm(t, executor, clockNow,
+		&remote.FieldValue{Name: "id", Value: int64(7)},
+	)
+
+	var operation metrics.OperationType
+	withClock := func(inv *Invocation, next InvocationHandler) *cd.Error {
+		operation = inv.Operation
+		inv.Context = ContextWithClock(inv.Context, func() time.Time { return interceptorNow })
+		return next(inv)
+	}
+
+	insertModel, err := ormImpl.WithInterceptors(withClock).Insert(postModel)
+	if err != nil {
+		t.Fatalf("Insert through interceptors failed: %v", err)
+	}
+	if operation != metrics.OperationInsert {
+		t.Fatalf("unexpected operation: %s", operation)
+	}
+	assertFieldTime(t, insertModel, "createdAt", interceptorNow)
+}
+
+func TestInterceptorContextReachesExecutor(t *testing.T) {
+	executor := &fakeExecutor{rowsAffected: 1}
+	ormImpl, memoModel := newHookTestOrm(t, executor)
+
+	var cancel context.CancelFunc
+	withCancel := func(inv *Invocation, next InvocationHandler) *cd.Error {
+		inv.Context, cancel = context.WithCancel(inv.Context)
+		defer cancel()
+		return next(inv)
+	}
+	// 钩子在CheckContext之后、SQL执行之前取消context，只有executor使用了拦截器的context才会失败
+	RegisterHook(memoModel.GetPkgKey(), BeforeDelete, func(context.Context, models.Model) error {
+		cancel()
+		return nil
+	})
+
+	_, err := ormImpl.WithInterceptors(withCancel).HardDelete(memoModel)
+	if err == nil {
+		t.Fatal("SQL should run on the context replaced by interceptor")
+	}
+	if !containsSQLCall(executor.execCalls, "exec", `DELETE FROM "tenant_Memo"`, nil) {
+		t.Fatalf("delete should reach the executor, got %#v", executor.execCalls)
+	}
+	if executor.rollbackCalls != 1 {
+		t.Fatalf("failed delete should roll back, rollback:%d", executor.rollbackCalls)
+	}
+	if executor.Context() != nil {
+		t.Fatal("executor context should be restored after the call")
+	}
+}
+
+func TestWithInterceptorsOption(t *testing.T) {
+	passThrough := func(inv *Invocation, next InvocationHandler) *cd.Error { return next(inv) }
+	options := newDatabaseOptions(WithInterceptors(nil, passThrough), WithInterceptors(passThrough))
+	if len(options.interceptors) != 2 {
+		t.Fatalf("expected 2 owner interceptors, got %d", len(options.interceptors))
+	}
+}
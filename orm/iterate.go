@@ -0,0 +1,206 @@
+package orm
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	cd "github.com/muidea/magicCommon/def"
+
+	"log/slog"
+
+	"github.com/muidea/magicOrm/database"
+	"github.com/muidea/magicOrm/metrics"
+	"github.com/muidea/magicOrm/models"
+)
+
+var iterateCursorSeq atomic.Uint64
+
+// queryCursor Iterate 分批读取查询结果的游标，open/fetch/close 由各数据库实现
+type queryCursor struct {
+	executor database.Executor
+	name     string
+	query    database.Result
+
+	// pkName/pkIndex 主键列名及其在查询行中的位置，lastPK 为已读取的最后一行主键，供按主键续读的实现使用
+	pkName  string
+	pkIndex int
+	lastPK  any
+}
+
+func newQueryCursor(executor database.Executor, query database.Result, vModel models.Model, selectedIndexes []int) *queryCursor {
+	cursor := &queryCursor{
+		executor: executor,
+		name:     fmt.Sprintf("magicorm_cursor_%d", iterateCursorSeq.Add(1)),
+		query:    query,
+		pkIndex:  -1,
+	}
+
+	vFields := vModel.GetFields()
+	for idx, fieldIdx := range selectedIndexes {
+		if fieldIdx >= 0 && fieldIdx < len(vFields) && models.IsPrimaryField(vFields[fieldIdx]) {
+			cursor.pkName = vFields[fieldIdx].GetName()
+			cursor.pkIndex = idx
+			break
+		}
+	}
+	return cursor
+}
+
+// advance 记录本批最后一行的主键
+func (s *queryCursor) advance(queryValueList resultItemsList) {
+	if s.pkIndex < 0 || len(queryValueList) == 0 {
+		return
+	}
+	s.lastPK = queryValueList[len(queryValueList)-1][s.pkIndex]
+}
+
+// resetRelationCache 丢弃已加载的关系缓存，Iterate 每批单独缓存，避免内存随遍历行数增长
+func (s *QueryRunner) resetRelationCache() {
+	s.relationCache = map[string]models.Model{}
+	s.relationMisses = map[string]struct{}{}
+	s.relationEdges = map[string][]any{}
+	s.relationWarns = map[string]struct{}{}
+}
+
+// Iterate 通过游标每次读取chunkSize行，构造模型并批量加载关系后逐个交给handler，handler返回错误时停止
+func (s *QueryRunner) Iterate(filter models.Filter, chunkSize int, handler func(models.Model) *cd.Error) (err *cd.Error) {
+	if err = s.applySelectFields(filter); err != nil {
+		slog.Error("QueryRunner Iterate applySelectFields failed", "error", err.Error())
+		return
+	}
+
+	queryResult, queryErr := s.buildQueryResult(s.vModel, filter)
+	if queryErr != nil {
+		err = queryErr
+		slog.Error("QueryRunner Iterate buildQueryResult failed", "error", err.Error())
+		return
+	}
+
+	cursor := newQueryCursor(s.executor, queryResult, s.vModel, s.selectedBasicFieldIndexs)
+	if err = cursor.open(); err != nil {
+		slog.Error("QueryRunner Iterate open cursor failed", "error", err.Error())
+		return
+	}
+	defer func() {
+		// 出错时事务回滚会一并释放游标
+		if err == nil {
+			err = cursor.close()
+		}
+	}()
+
+	for {
+		if err = s.checkContext(); err != nil {
+			return
+		}
+		if err = cursor.fetch(chunkSize); err != nil {
+			slog.Error("QueryRunner Iterate fetch cursor failed", "error", err.Error())
+			return
+		}
+
+		queryValueList, scanErr := s.scanQueryRows()
+		s.executor.Finish()
+		if scanErr != nil {
+			err = scanErr
+			return
+		}
+		if len(queryValueList) == 0 {
+			return
+		}
+		cursor.advance(queryValueList)
+
+		s.resetRelationCache()
+		modelList, _, _, _, _, assignErr := s.assignQueryModels(queryValueList)
+		if assignErr != nil {
+			err = assignErr
+			return
+		}
+		for _, modelVal := range modelList {
+			if err = s.checkContext(); err != nil {
+				return
+			}
+			if err = handler(modelVal); err != nil {
+				return
+			}
+		}
+		if len(queryValueList) < chunkSize {
+			return
+		}
+	}
+}
+
+// Iterate 流式遍历满足filter的模型，逐个交给handler，handler返回错误时停止遍历并返回该错误
+//
+// 在事务内通过游标每批读取 WithChunkSize 指定的行数（默认500），每批单独加载关系，内存只与批大小相关；
+// PostgreSQL使用服务端游标；MySQL按主键递增分批读取（WHERE pk > 上一批最后的主键），遍历顺序为主键顺序，filter指定排序时返回IllegalParam。
+// 返回字段与opts的语义同BatchQuery，每批读取前与每次回调前检查context，取消后返回对应错误。
+// handler可以使用同一个Orm执行其他操作，这些操作处于遍历的事务中。
+func (s *impl) Iterate(vFilter models.Filter, handler func(models.Model) *cd.Error, opts ...LoadOption) (err *cd.Error) {
+	if len(s.interceptors) > 0 {
+		return s.interceptIterate(vFilter, handler, opts)
+	}
+
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		if ormMetricCollector != nil {
+			var model models.Model
+			if vFilter != nil {
+				model = vFilter.MaskModel()
+			}
+			ormMetricCollector.RecordOperation(string(metrics.OperationBatch), model, duration, cd.ToStdError(err))
+		}
+	}()
+
+	if err = s.CheckContext(); err != nil {
+		return
+	}
+
+	restoreTimeout := s.applyCallTimeout()
+	defer restoreTimeout()
+
+	if vFilter == nil {
+		err = cd.NewError(cd.IllegalParam, "filter is nil")
+		return
+	}
+	if handler == nil {
+		err = cd.NewError(cd.IllegalParam, "iterate handler is nil")
+		return
+	}
+	if err = checkIterateFilter(vFilter); err != nil {
+		slog.Error("Iterate checkIterateFilter failed", "error", err.Error())
+		return
+	}
+
+	responseModel, responseByMask, responseErr := buildQueryResponseModel(nil, vFilter)
+	if responseErr != nil {
+		err = responseErr
+		slog.Error("Iterate buildQueryResponseModel failed", "error", err.Error())
+		return
+	}
+
+	queryMask, maskErr := buildQueryExecutionModel(responseModel, !responseByMask)
+	if maskErr != nil {
+		err = maskErr
+		slog.Error("Iterate buildQueryExecutionModel failed", "error", err.Error())
+		return
+	}
+
+	err = s.executor.BeginTransaction()
+	if err != nil {
+		return
+	}
+	defer func() {
+		s.finalTransaction(err)
+	}()
+
+	loadOpts := newLoadOptions(opts...)
+	vQueryRunner := NewQueryRunner(s.context, queryMask, responseModel, responseByMask, s.executor, s.modelProvider, s.modelCodec, true, 0)
+	vQueryRunner.loadOptions = loadOpts
+	err = vQueryRunner.Iterate(vFilter, loadOpts.chunkSize, handler)
+	if err != nil {
+		slog.Error("Iterate QueryRunner.Iterate failed", "error", err.Error())
+	}
+	return
+}
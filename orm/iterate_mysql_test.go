@@ -0,0 +1,127 @@
+//go:build mysql
+// +build mysql
+
+package orm
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	cd "github.com/muidea/magicCommon/def"
+
+	"github.com/muidea/magicOrm/database/codec"
+	"github.com/muidea/magicOrm/models"
+	"github.com/muidea/magicOrm/provider"
+)
+
+type iterateRow struct {
+	ID   int64  `orm:"id key auto"`
+	Name string `orm:"name"`
+}
+
+func TestIterateMySQLDeleteInHandler(t *testing.T) {
+	localProvider := provider.NewLocalProvider("tenant", nil)
+	if _, err := localProvider.RegisterModel(&iterateRow{}); err != nil {
+		t.Fatalf("RegisterModel(iterateRow) failed: %v", err)
+	}
+	rowModel, err := localProvider.GetEntityModel(&iterateRow{}, true)
+	if err != nil {
+		t.Fatalf("GetEntityModel(iterateRow) failed: %v", err)
+	}
+	rowFilter, err := localProvider.GetModelFilter(rowModel)
+	if err != nil {
+		t.Fatalf("GetModelFilter(iterateRow) failed: %v", err)
+	}
+
+	table := []int64{1, 2, 3, 4, 5}
+	executor := &fakeExecutor{}
+	executor.responses = append(executor.responses, fakeQueryResponse{
+		match: func(querySQL string, _ []any) bool {
+			return strings.HasPrefix(querySQL, "SELECT * FROM (SELECT `id`,`name` FROM `tenant_IterateRow`")
+		},
+		rowsFunc: func(args []any) [][]any {
+			var lastID int64
+			if len(args) > 0 {
+				lastID = args[len(args)-1].(int64)
+			}
+			rows := [][]any{}
+			for _, id := range table {
+				if id > lastID && len(rows) < 2 {
+					rows = append(rows, []any{id, "row"})
+				}
+			}
+			return rows
+		},
+	})
+	executor.onExecute = func(querySQL string, args []any) {
+		if !strings.HasPrefix(querySQL, "DELETE FROM `tenant_IterateRow`") {
+			return
+		}
+		for idx, id := range table {
+			if id == args[0] {
+				table = append(table[:idx], table[idx+1:]...)
+				return
+			}
+		}
+	}
+
+	ormImpl := &impl{
+		context:       context.Background(),
+		executor:      executor,
+		modelProvider: localProvider,
+		modelCodec:    codec.New(localProvider, "tenant"),
+	}
+
+	visited := []int64{}
+	err = ormImpl.Iterate(rowFilter, func(vModel models.Model) *cd.Error {
+		visited = append(visited, vModel.Interface(true).(*iterateRow).ID)
+		_, deleteErr := ormImpl.Delete(vModel)
+		return deleteErr
+	}, WithChunkSize(2))
+	if err != nil {
+		t.Fatalf("Iterate failed: %v", err)
+	}
+	if len(visited) != 5 || visited[0] != 1 || visited[4] != 5 {
+		t.Fatalf("rows deleted by handler should not shift later batches, visited: %v", visited)
+	}
+	if len(table) != 0 {
+		t.Fatalf("all rows should be deleted, remaining: %v", table)
+	}
+	if !containsSQLCall(executor.execCalls, "query", "WHERE `magicorm_cursor_", []any{int64(2)}) ||
+		!containsSQLCall(executor.execCalls, "query", "ORDER BY `magicorm_cursor_", nil) {
+		t.Fatalf("unexpected fetch calls: %#v", executor.execCalls)
+	}
+}
+
+func TestIterateMySQLRejectsSort(t *testing.T) {
+	localProvider := provider.NewLocalProvider("tenant", nil)
+	if _, err := localProvider.RegisterModel(&iterateRow{}); err != nil {
+		t.Fatalf("RegisterModel(iterateRow) failed: %v", err)
+	}
+	rowModel, err := localProvider.GetEntityModel(&iterateRow{}, true)
+	if err != nil {
+		t.Fatalf("GetEntityModel(iterateRow) failed: %v", err)
+	}
+	rowFilter, err := localProvider.GetModelFilter(rowModel)
+	if err != nil {
+		t.Fatalf("GetModelFilter(iterateRow) failed: %v", err)
+	}
+	rowFilter.Sort("name", false)
+
+	executor := &fakeExecutor{}
+	ormImpl := &impl{
+		context:       context.Background(),
+		executor:      executor,
+		modelProvider: localProvider,
+		modelCodec:    codec.New(localProvider, "tenant"),
+	}
+
+	err = ormImpl.Iterate(rowFilter, func(models.Model) *cd.Error { return nil })
+	if err == nil || err.Code != cd.IllegalParam {
+		t.Fatalf("Iterate with sort should return IllegalParam, got %v", err)
+	}
+	if len(executor.execCalls) != 0 || executor.beginCalls != 0 {
+		t.Fatalf("rejected iterate should not touch the executor: %#v", executor.execCalls)
+	}
+}
@@ -0,0 +1,118 @@
+package orm
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	cd "github.com/muidea/magicCommon/def"
+
+	"github.com/muidea/magicOrm/models"
+)
+
+func newIterateTestOrm(t *testing.T, chunks ...[][]any) (*impl, *fakeExecutor, models.Filter) {
+	t.Helper()
+
+	ormImpl, executor, orderModel := newLoadOptionsTestOrm(t)
+	fetchCount := 0
+	fetchResponses := []fakeQueryResponse{}
+	for idx := range chunks {
+		chunkIndex := idx
+		fetchResponses = append(fetchResponses, fakeQueryResponse{
+			match: func(querySQL string, _ []any) bool {
+				if !strings.HasPrefix(querySQL, "FETCH FORWARD") || fetchCount != chunkIndex {
+					return false
+				}
+				fetchCount++
+				return true
+			},
+			rows: chunks[chunkIndex],
+		})
+	}
+	fetchResponses = append(fetchResponses, fakeQueryResponse{
+		match: func(querySQL string, _ []any) bool { return strings.HasPrefix(querySQL, "FETCH FORWARD") },
+	})
+	executor.responses = append(fetchResponses, executor.responses...)
+
+	orderFilter, err := ormImpl.modelProvider.GetModelFilter(orderModel)
+	if err != nil {
+		t.Fatalf("GetModelFilter(loadOrder) failed: %v", err)
+	}
+	return ormImpl, executor, orderFilter
+}
+
+func TestIterateStreamsChunks(t *testing.T) {
+	ormImpl, executor, orderFilter := newIterateTestOrm(t,
+		[][]any{{int64(1), "SO-1"}, {int64(2), "SO-2"}},
+		[][]any{{int64(3), "SO-3"}},
+	)
+
+	sns := []string{}
+	err := ormImpl.Iterate(orderFilter, func(vModel models.Model) *cd.Error {
+		order := vModel.Interface(true).(*loadOrder)
+		if order.ID == 1 && !customerLoaded(order.Customer) {
+			t.Fatalf("relations should be loaded by chunk, got %#v", order)
+		}
+		sns = append(sns, order.SN)
+		return nil
+	}, WithChunkSize(2))
+	if err != nil {
+		t.Fatalf("Iterate failed: %v", err)
+	}
+	if strings.Join(sns, ",") != "SO-1,SO-2,SO-3" {
+		t.Fatalf("unexpected iterated models: %v", sns)
+	}
+	if executor.beginCalls != 1 || executor.commitCalls != 1 {
+		t.Fatalf("iterate should run in a transaction, begin:%d commit:%d", executor.beginCalls, executor.commitCalls)
+	}
+	if !containsSQLCall(executor.execCalls, "exec", `NO SCROLL CURSOR FOR SELECT "id","sn" FROM "tenant_LoadOrder"`, nil) ||
+		!containsSQLCall(executor.execCalls, "query", "FETCH FORWARD 2 FROM", nil) ||
+		!containsSQLCall(executor.execCalls, "exec", "CLOSE ", nil) {
+		t.Fatalf("unexpected cursor calls: %#v", executor.execCalls)
+	}
+
+	batchQueries := 0
+	for _, call := range executor.execCalls {
+		if strings.Contains(call.sql, `SELECT "left","right" FROM "tenant_LoadOrderCustomer`) {
+			batchQueries++
+		}
+	}
+	if batchQueries != 2 {
+		t.Fatalf("relations should be prefetched once per chunk, got %d", batchQueries)
+	}
+}
+
+func TestIterateStopsOnHandlerError(t *testing.T) {
+	ormImpl, executor, orderFilter := newIterateTestOrm(t,
+		[][]any{{int64(1), "SO-1"}, {int64(2), "SO-2"}},
+		[][]any{{int64(3), "SO-3"}},
+	)
+
+	handled := 0
+	err := ormImpl.Iterate(orderFilter, func(models.Model) *cd.Error {
+		handled++
+		return cd.NewError(cd.Unexpected, "stop")
+	}, WithChunkSize(2), WithoutRelations())
+	if err == nil || err.Code != cd.Unexpected || handled != 1 {
+		t.Fatalf("handler error should stop iterate, handled:%d err:%v", handled, err)
+	}
+	if executor.rollbackCalls != 1 {
+		t.Fatalf("iterate should roll back on error, rollback:%d", executor.rollbackCalls)
+	}
+}
+
+func TestIterateHonorsContextCancel(t *testing.T) {
+	ormImpl, _, orderFilter := newIterateTestOrm(t, [][]any{{int64(1), "SO-1"}, {int64(2), "SO-2"}})
+	ctx, cancel := context.WithCancel(context.Background())
+	ormImpl.context = ctx
+
+	handled := 0
+	err := ormImpl.Iterate(orderFilter, func(models.Model) *cd.Error {
+		handled++
+		cancel()
+		return nil
+	}, WithoutRelations())
+	if err == nil || handled != 1 {
+		t.Fatalf("iterate should stop after context cancel, handled:%d err:%v", handled, err)
+	}
+}
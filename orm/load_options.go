@@ -0,0 +1,142 @@
+package orm
+
+import (
+	"strings"
+
+	"github.com/muidea/magicOrm/models"
+)
+
+// defaultLoadDepth 默认加载的关系层数，与update/delete遍历关系使用的maxDeepLevel一致：
+// 顶层及其下maxDeepLevel层模型的关系都会加载
+const defaultLoadDepth = maxDeepLevel + 1
+
+// defaultIterateChunkSize Iterate 默认每批读取并加载关系的行数，也是 QueryByIDs 默认每批查询的主键数
+const defaultIterateChunkSize = 500
+
+// LoadOption Query/BatchQuery 的关系加载选项，未指定时保持默认行为
+type LoadOption func(*loadOptions)
+
+type loadOptions struct {
+	maxDepth  int
+	includes  map[string]struct{}
+	excludes  map[string]struct{}
+	childView models.ViewDeclare
+	chunkSize int
+}
+
+func newLoadOptions(opts ...LoadOption) *loadOptions {
+	ret := &loadOptions{maxDepth: defaultLoadDepth, childView: models.LiteView, chunkSize: defaultIterateChunkSize}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(ret)
+		}
+	}
+
+	return ret
+}
+
+// WithLoadDepth 最多加载depth层关系，1表示只加载顶层模型的关系，0等同于 WithoutRelations
+func WithLoadDepth(depth int) LoadOption {
+	return func(o *loadOptions) {
+		if depth < 0 {
+			depth = 0
+		}
+		o.maxDepth = depth
+	}
+}
+
+// WithoutRelations 不加载任何关系字段，只查询host表
+func WithoutRelations() LoadOption {
+	return WithLoadDepth(0)
+}
+
+// WithIncludeRelations 只加载列出的关系字段，点路径（如 "customer.status"）指定子对象下的关系字段；
+// 列出子路径时其上层关系字段同时加载
+func WithIncludeRelations(fieldPaths ...string) LoadOption {
+	return func(o *loadOptions) {
+		if o.includes == nil {
+			o.includes = map[string]struct{}{}
+		}
+		for _, fieldPath := range fieldPaths {
+			o.includes[fieldPath] = struct{}{}
+		}
+	}
+}
+
+// WithExcludeRelations 不加载列出的关系字段及其下的关系，点路径含义同 WithIncludeRelations
+func WithExcludeRelations(fieldPaths ...string) LoadOption {
+	return func(o *loadOptions) {
+		if o.excludes == nil {
+			o.excludes = map[string]struct{}{}
+		}
+		for _, fieldPath := range fieldPaths {
+			o.excludes[fieldPath] = struct{}{}
+		}
+	}
+}
+
+// WithChildView 关系子对象按view查询与裁剪，默认 models.LiteView
+func WithChildView(view models.ViewDeclare) LoadOption {
+	return func(o *loadOptions) {
+		o.childView = view
+	}
+}
+
+// WithChunkSize Iterate 每批读取并加载关系的行数、QueryByIDs 每批查询的主键数，小于1时使用默认值，对其余查询无效
+func WithChunkSize(size int) LoadOption {
+	return func(o *loadOptions) {
+		if size < 1 {
+			size = defaultIterateChunkSize
+		}
+		o.chunkSize = size
+	}
+}
+
+// loadDepth 当前模型的关系层级deepLevel（顶层为0）是否还需要加载关系，未设置选项时按默认层数
+func (s *loadOptions) loadDepth(deepLevel int) bool {
+	if s == nil {
+		return deepLevel < defaultLoadDepth
+	}
+
+	return deepLevel < s.maxDepth
+}
+
+// loadRelation fieldPath为从顶层模型开始的关系字段路径
+func (s *loadOptions) loadRelation(fieldPath string) bool {
+	if s == nil {
+		return true
+	}
+
+	for excludePath := range s.excludes {
+		if fieldPath == excludePath || strings.HasPrefix(fieldPath, excludePath+models.FieldPathSeparator) {
+			return false
+		}
+	}
+	if len(s.includes) == 0 {
+		return true
+	}
+
+	for includePath := range s.includes {
+		if fieldPath == includePath || strings.HasPrefix(includePath, fieldPath+models.FieldPathSeparator) {
+			return true
+		}
+	}
+	return false
+}
+
+// relationView 关系子对象使用的view
+func (s *loadOptions) relationView() models.ViewDeclare {
+	if s == nil {
+		return models.LiteView
+	}
+
+	return s.childView
+}
+
+func joinRelationPath(parentPath, fieldName string) string {
+	if parentPath == "" {
+		return fieldName
+	}
+
+	return parentPath + models.FieldPathSeparator + fieldName
+}
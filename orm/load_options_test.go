@@ -0,0 +1,159 @@
+package orm
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/muidea/magicOrm/database/codec"
+	"github.com/muidea/magicOrm/models"
+	"github.com/muidea/magicOrm/provider"
+)
+
+type loadStatus struct {
+	ID   int    `orm:"id key auto" view:"detail,lite"`
+	Name string `orm:"name" view:"detail,lite"`
+	Memo string `orm:"memo" view:"detail"`
+}
+
+type loadCustomer struct {
+	ID     int         `orm:"id key auto" view:"detail,lite"`
+	Name   string      `orm:"name" view:"detail,lite"`
+	Status *loadStatus `orm:"status" view:"detail,lite"`
+}
+
+type loadOrder struct {
+	ID       int           `orm:"id key auto" view:"detail,lite"`
+	SN       string        `orm:"sn" view:"detail,lite"`
+	Customer *loadCustomer `orm:"customer" view:"detail,lite"`
+	Status   *loadStatus   `orm:"status" view:"detail,lite"`
+}
+
+func newLoadOptionsTestOrm(t *testing.T) (*impl, *fakeExecutor, models.Model) {
+	t.Helper()
+
+	localProvider := provider.NewLocalProvider("tenant", nil)
+	for _, entity := range []any{&loadStatus{}, &loadCustomer{}, &loadOrder{}} {
+		if _, err := localProvider.RegisterModel(entity); err != nil {
+			t.Fatalf("RegisterModel(%T) failed: %v", entity, err)
+		}
+	}
+	orderModel, err := localProvider.GetEntityModel(&loadOrder{ID: 1}, true)
+	if err != nil {
+		t.Fatalf("GetEntityModel(loadOrder) failed: %v", err)
+	}
+
+	executor := &fakeExecutor{}
+	for _, response := range []struct {
+		snippet string
+		rows    [][]any
+	}{
+		{`FROM "tenant_LoadOrder" `, [][]any{{int64(1), "SO-1"}}},
+		{`FROM "tenant_LoadCustomer" `, [][]any{{int64(2), "alice"}}},
+		{`"memo" FROM "tenant_LoadStatus" `, [][]any{{int64(3), "active", "memo"}}},
+		{`FROM "tenant_LoadStatus" `, [][]any{{int64(3), "active"}}},
+		{`SELECT "right" FROM "tenant_LoadOrderCustomer`, [][]any{{int64(2)}}},
+		{`SELECT "right" FROM "tenant_LoadOrderStatus`, [][]any{{int64(3)}}},
+		{`SELECT "right" FROM "tenant_LoadCustomerStatus`, [][]any{{int64(3)}}},
+		{`FROM "tenant_LoadOrderCustomer`, [][]any{{int64(1), int64(2)}}},
+		{`FROM "tenant_LoadOrderStatus`, [][]any{{int64(1), int64(3)}}},
+		{`FROM "tenant_LoadCustomerStatus`, [][]any{{int64(2), int64(3)}}},
+	} {
+		executor.responses = append(executor.responses, fakeQueryResponse{
+			match: func(querySQL string, _ []any) bool { return strings.Contains(querySQL, response.snippet) },
+			rows:  response.rows,
+		})
+	}
+
+	ormImpl := &impl{
+		context:       context.Background(),
+		executor:      executor,
+		modelProvider: localProvider,
+		modelCodec:    codec.New(localProvider, "tenant"),
+	}
+	return ormImpl, executor, orderModel
+}
+
+func queryLoadOrder(t *testing.T, opts ...LoadOption) (*loadOrder, []fakeExecCall) {
+	t.Helper()
+
+	ormImpl, executor, orderModel := newLoadOptionsTestOrm(t)
+	queryModel, err := ormImpl.Query(orderModel, opts...)
+	if err != nil {
+		t.Fatalf("impl.Query(loadOrder) failed: %v", err)
+	}
+	return queryModel.Interface(true).(*loadOrder), executor.execCalls
+}
+
+func customerLoaded(customer *loadCustomer) bool {
+	return customer != nil && customer.ID != 0
+}
+
+func statusLoaded(status *loadStatus) bool {
+	return status != nil && status.ID != 0
+}
+
+func TestQueryDefaultLoadOptions(t *testing.T) {
+	order, _ := queryLoadOrder(t)
+	if !customerLoaded(order.Customer) || order.Customer.Name != "alice" || !statusLoaded(order.Customer.Status) || !statusLoaded(order.Status) {
+		t.Fatalf("relations should be loaded by default, got %#v", order)
+	}
+	if order.Customer.Status.Memo != "" || order.Status.Memo != "" {
+		t.Fatal("children should be projected by lite view by default")
+	}
+}
+
+func TestQueryWithoutRelations(t *testing.T) {
+	order, calls := queryLoadOrder(t, WithoutRelations())
+	if order.SN != "SO-1" || customerLoaded(order.Customer) || statusLoaded(order.Status) {
+		t.Fatalf("relations should not be loaded, got %#v", order)
+	}
+	if len(calls) != 1 {
+		t.Fatalf("only host table should be queried, got %#v", calls)
+	}
+}
+
+func TestQueryLoadDepth(t *testing.T) {
+	order, calls := queryLoadOrder(t, WithLoadDepth(1))
+	if !customerLoaded(order.Customer) || !statusLoaded(order.Status) || statusLoaded(order.Customer.Status) {
+		t.Fatalf("only top level relations should be loaded, got %#v", order)
+	}
+	if containsSQLCall(calls, "query", "tenant_LoadCustomerStatus", nil) {
+		t.Fatalf("second level relations should not be queried, got %#v", calls)
+	}
+}
+
+func TestQueryIncludeRelationsWithChildView(t *testing.T) {
+	order, calls := queryLoadOrder(t, WithIncludeRelations("customer.status"), WithChildView(models.DetailView))
+	if statusLoaded(order.Status) || containsSQLCall(calls, "query", "tenant_LoadOrderStatus", nil) {
+		t.Fatalf("relation outside include paths should not be loaded, got %#v", order)
+	}
+	if !customerLoaded(order.Customer) || !statusLoaded(order.Customer.Status) || order.Customer.Status.Memo != "memo" {
+		t.Fatalf("included relation should be loaded by detail view, got %#v", order.Customer)
+	}
+}
+
+func TestQueryExcludeRelations(t *testing.T) {
+	order, _ := queryLoadOrder(t, WithExcludeRelations("customer.status"))
+	if !customerLoaded(order.Customer) || statusLoaded(order.Customer.Status) || !statusLoaded(order.Status) {
+		t.Fatalf("only customer.status should be excluded, got %#v", order)
+	}
+}
+
+func TestLoadOptionsRelationPaths(t *testing.T) {
+	options := newLoadOptions(WithIncludeRelations("customer.status"), WithExcludeRelations("customer.status.owner"))
+	for fieldPath, want := range map[string]bool{
+		"customer":              true,
+		"customer.status":       true,
+		"customer.status.owner": false,
+		"customer.profile":      false,
+		"status":                false,
+	} {
+		if options.loadRelation(fieldPath) != want {
+			t.Fatalf("loadRelation(%s) should be %v", fieldPath, want)
+		}
+	}
+	if newLoadOptions().maxDepth != defaultLoadDepth || newLoadOptions(WithLoadDepth(-1)).maxDepth != 0 {
+		t.Fatal("unexpected load depth")
+	}
+}
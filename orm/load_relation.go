@@ -0,0 +1,173 @@
+package orm
+
+import (
+	"fmt"
+	"time"
+
+	cd "github.com/muidea/magicCommon/def"
+
+	"log/slog"
+
+	"github.com/muidea/magicOrm/metrics"
+	"github.com/muidea/magicOrm/models"
+)
+
+// newLoadRelationOptions LoadRelation 的加载选项，指定的关系字段本身总会加载，层数至少为1
+func newLoadRelationOptions(opts ...LoadOption) *loadOptions {
+	ret := newLoadOptions(opts...)
+	if ret.maxDepth < 1 {
+		ret.maxDepth = 1
+	}
+
+	return ret
+}
+
+// getLoadRelationField 校验vModel已赋值主键且fieldName为关系字段
+func getLoadRelationField(vModel models.Model, fieldName string) (ret models.Field, err *cd.Error) {
+	if vModel == nil {
+		err = cd.NewError(cd.IllegalParam, "illegal model value")
+		return
+	}
+
+	pkField := vModel.GetPrimaryField()
+	if pkField == nil || !models.IsAssignedField(pkField) {
+		err = cd.NewError(cd.IllegalParam, fmt.Sprintf("model primary field is not assigned, model:%s", vModel.GetPkgKey()))
+		return
+	}
+
+	vField := vModel.GetField(fieldName)
+	if vField == nil || models.IsBasicField(vField) {
+		err = cd.NewError(cd.IllegalParam, fmt.Sprintf("illegal relation field, model:%s, field:%s", vModel.GetPkgKey(), fieldName))
+		return
+	}
+
+	ret = vField
+	return
+}
+
+// loadRelationField 为modelList加载fieldName关系，关系表与关联对象各只查询一次
+func (s *QueryRunner) loadRelationField(modelList []models.Model, fieldName string) (err *cd.Error) {
+	if err = s.checkContext(); err != nil {
+		return
+	}
+
+	vField := modelList[0].GetField(fieldName)
+	pkField := modelList[0].GetPrimaryField()
+	leftIDs := make([]any, 0, len(modelList))
+	for _, modelVal := range modelList {
+		leftID, leftErr := s.modelCodec.ExtractBasicFieldValue(pkField, modelVal.GetPrimaryField().GetValue().Get())
+		if leftErr != nil {
+			err = leftErr
+			return
+		}
+		leftIDs = append(leftIDs, leftID)
+	}
+
+	if err = s.batchQueryRelationKeys(modelList[0], vField, leftIDs); err != nil {
+		return
+	}
+	if rightIDs := s.collectRelationRightIDs(modelList[0], vField, leftIDs); len(rightIDs) > 0 {
+		if err = s.batchQueryRelationModels(vField, rightIDs, 0); err != nil {
+			return
+		}
+	}
+
+	for _, modelVal := range modelList {
+		if err = s.queryRelation(modelVal, modelVal.GetField(fieldName), 0); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// LoadRelation 为已查询的vModel加载fieldName关系字段，返回加载后的模型，不修改vModel
+//
+// vModel需已赋值主键；opts与Query一致，路径与层数以vModel为顶层，fieldName本身总会加载。
+func (s *impl) LoadRelation(vModel models.Model, fieldName string, opts ...LoadOption) (ret models.Model, err *cd.Error) {
+	if len(s.interceptors) > 0 {
+		return s.interceptLoadRelation(vModel, fieldName, opts)
+	}
+
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		if ormMetricCollector != nil {
+			ormMetricCollector.RecordOperation(string(metrics.OperationQuery), vModel, duration, cd.ToStdError(err))
+		}
+	}()
+
+	if err = s.CheckContext(); err != nil {
+		return
+	}
+
+	restoreTimeout := s.applyCallTimeout()
+	defer restoreTimeout()
+
+	if _, err = getLoadRelationField(vModel, fieldName); err != nil {
+		return
+	}
+
+	qModel := vModel.Copy(models.OriginView)
+	vQueryRunner := NewQueryRunner(s.context, qModel, qModel, false, s.executor, s.modelProvider, s.modelCodec, false, 0)
+	vQueryRunner.loadOptions = newLoadRelationOptions(opts...)
+	err = vQueryRunner.queryRelation(qModel, qModel.GetField(fieldName), 0)
+	if err != nil {
+		slog.Error("LoadRelation QueryRunner.queryRelation failed", "pkgKey", vModel.GetPkgKey(), "field", fieldName, "error", err.Error())
+		return
+	}
+
+	ret = qModel
+	return
+}
+
+// BatchLoadRelation 为同一模型的vModels加载fieldName关系字段，关系表只查询一次，返回加载后的模型列表
+func (s *impl) BatchLoadRelation(vModels []models.Model, fieldName string, opts ...LoadOption) (ret []models.Model, err *cd.Error) {
+	if len(s.interceptors) > 0 {
+		return s.interceptBatchLoadRelation(vModels, fieldName, opts)
+	}
+
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		if ormMetricCollector != nil && len(vModels) > 0 {
+			ormMetricCollector.RecordOperation(string(metrics.OperationBatch), vModels[0], duration, cd.ToStdError(err))
+		}
+	}()
+
+	if err = s.CheckContext(); err != nil {
+		return
+	}
+
+	restoreTimeout := s.applyCallTimeout()
+	defer restoreTimeout()
+
+	if len(vModels) == 0 {
+		return
+	}
+
+	qModels := make([]models.Model, 0, len(vModels))
+	for _, vModel := range vModels {
+		if _, err = getLoadRelationField(vModel, fieldName); err != nil {
+			return
+		}
+		if vModel.GetPkgKey() != vModels[0].GetPkgKey() {
+			err = cd.NewError(cd.IllegalParam, fmt.Sprintf("batch load relation models must be the same type, %s != %s", vModel.GetPkgKey(), vModels[0].GetPkgKey()))
+			return
+		}
+
+		qModels = append(qModels, vModel.Copy(models.OriginView))
+	}
+
+	vQueryRunner := NewQueryRunner(s.context, qModels[0], qModels[0], false, s.executor, s.modelProvider, s.modelCodec, true, 0)
+	vQueryRunner.loadOptions = newLoadRelationOptions(opts...)
+	err = vQueryRunner.loadRelationField(qModels, fieldName)
+	if err != nil {
+		slog.Error("BatchLoadRelation QueryRunner.loadRelationField failed", "pkgKey", vModels[0].GetPkgKey(), "field", fieldName, "error", err.Error())
+		return
+	}
+
+	ret = qModels
+	return
+}
@@ -0,0 +1,69 @@
+package orm
+
+import (
+	"strings"
+	"testing"
+
+	cd "github.com/muidea/magicCommon/def"
+
+	"github.com/muidea/magicOrm/models"
+)
+
+func TestLoadRelation(t *testing.T) {
+	ormImpl, executor, orderModel := newLoadOptionsTestOrm(t)
+	queryModel, err := ormImpl.Query(orderModel, WithoutRelations())
+	if err != nil {
+		t.Fatalf("impl.Query(loadOrder) failed: %v", err)
+	}
+
+	loadedModel, err := ormImpl.LoadRelation(queryModel, "customer")
+	if err != nil {
+		t.Fatalf("LoadRelation(customer) failed: %v", err)
+	}
+	order := loadedModel.Interface(true).(*loadOrder)
+	if order.SN != "SO-1" || !customerLoaded(order.Customer) || !statusLoaded(order.Customer.Status) || statusLoaded(order.Status) {
+		t.Fatalf("only customer should be loaded, got %#v", order)
+	}
+	if customerLoaded(queryModel.Interface(true).(*loadOrder).Customer) {
+		t.Fatal("LoadRelation should not modify the source model")
+	}
+	if containsSQLCall(executor.execCalls, "query", "tenant_LoadOrderStatus", nil) {
+		t.Fatalf("other relations should not be queried, got %#v", executor.execCalls)
+	}
+
+	if _, err = ormImpl.LoadRelation(queryModel, "sn"); err == nil || err.Code != cd.IllegalParam {
+		t.Fatalf("basic field should be rejected, got %v", err)
+	}
+}
+
+func TestBatchLoadRelation(t *testing.T) {
+	ormImpl, executor, orderModel := newLoadOptionsTestOrm(t)
+	otherModel, err := ormImpl.modelProvider.GetEntityModel(&loadOrder{ID: 2}, true)
+	if err != nil {
+		t.Fatalf("GetEntityModel(loadOrder) failed: %v", err)
+	}
+
+	loadedModels, err := ormImpl.BatchLoadRelation([]models.Model{orderModel, otherModel}, "customer", WithLoadDepth(1))
+	if err != nil {
+		t.Fatalf("BatchLoadRelation(customer) failed: %v", err)
+	}
+	if len(loadedModels) != 2 {
+		t.Fatalf("unexpected loaded models, got %d", len(loadedModels))
+	}
+
+	first := loadedModels[0].Interface(true).(*loadOrder)
+	second := loadedModels[1].Interface(true).(*loadOrder)
+	if !customerLoaded(first.Customer) || statusLoaded(first.Customer.Status) || customerLoaded(second.Customer) {
+		t.Fatalf("unexpected loaded customers, got %#v, %#v", first.Customer, second.Customer)
+	}
+
+	relationQueries := 0
+	for _, call := range executor.execCalls {
+		if strings.Contains(call.sql, "tenant_LoadOrderCustomer") {
+			relationQueries++
+		}
+	}
+	if relationQueries != 1 || !containsSQLCall(executor.execCalls, "query", `SELECT "left","right" FROM "tenant_LoadOrderCustomer`, nil) {
+		t.Fatalf("relation table should be queried once in batch, got %#v", executor.execCalls)
+	}
+}
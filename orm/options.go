@@ -0,0 +1,65 @@
+package orm
+
+import (
+	"time"
+
+	"github.com/muidea/magicOrm/database"
+)
+
+// DatabaseOption defines a functional option for AddDatabase/NewConfig
+type DatabaseOption func(*databaseOptions)
+
+type databaseOptions struct {
+	tlsConfig        *database.TLSConfig
+	statementTimeout time.Duration
+	retryPolicy      *database.RetryPolicy
+	batchParamLimit  int
+	filterPathDepth  int
+	interceptors     []Interceptor
+}
+
+func newDatabaseOptions(opts ...DatabaseOption) *databaseOptions {
+	ret := &databaseOptions{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(ret)
+		}
+	}
+
+	return ret
+}
+
+// WithTLS sets the TLS configuration of database connection
+func WithTLS(tlsConfig *database.TLSConfig) DatabaseOption {
+	return func(o *databaseOptions) {
+		o.tlsConfig = tlsConfig
+	}
+}
+
+// WithDefaultStatementTimeout sets the default statement timeout of Orm acquired by GetOrm
+func WithDefaultStatementTimeout(timeout time.Duration) DatabaseOption {
+	return func(o *databaseOptions) {
+		o.statementTimeout = timeout
+	}
+}
+
+// WithRetryPolicy sets the retry policy of connection acquisition and idempotent reads for the owner pool
+func WithRetryPolicy(policy database.RetryPolicy) DatabaseOption {
+	return func(o *databaseOptions) {
+		o.retryPolicy = &policy
+	}
+}
+
+// WithFilterPathDepth sets the max number of relation levels a dotted filter path may cross, depth<1 uses database.DefaultFilterPathDepth
+func WithFilterPathDepth(depth int) DatabaseOption {
+	return func(o *databaseOptions) {
+		o.filterPathDepth = depth
+	}
+}
+
+// WithBatchInsertParamLimit sets the max number of bind parameters of each statement generated by BatchInsert
+func WithBatchInsertParamLimit(limit int) DatabaseOption {
+	return func(o *databaseOptions) {
+		o.batchParamLimit = limit
+	}
+}
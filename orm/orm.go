@@ -3,7 +3,9 @@ package orm
 import (
 	"context"
 	"fmt"
+	"iter"
 	"sync"
+	"time"
 
 	cd "github.com/muidea/magicCommon/def"
 	"github.com/muidea/magicCommon/monitoring"
@@ -27,22 +29,72 @@ const maxDeepLevel = 3
 
 // Orm is the stable public query/write contract exposed by magicOrm.
 //
-// Query is the single-object query entrypoint driven by a model instance.
-// BatchQuery is the multi-object query entrypoint driven by a filter.
-// magicOrm intentionally keeps only these two public query shapes to avoid
-// overlapping single-row semantics in the ORM layer.
+// Query loads a single entity driven by a model instance and QueryByIDs loads entities by primary key.
+// BatchQuery and Count work on a filter, Iterate streams filter results through a cursor for large reads.
+// QueryRelation, CountRelation, LoadRelation and BatchLoadRelation read relation fields of queried entities,
+// RawQuery maps native SQL results onto a model for queries the filter can not express.
 type Orm interface {
 	Create(entity models.Model) *cd.Error
 	Drop(entity models.Model) *cd.Error
 	Insert(entity models.Model) (models.Model, *cd.Error)
+	// BatchInsert inserts entities of the same model in one transaction using multi-row INSERT statements,
+	// running BeforeInsert/AfterInsert hooks for every entity.
+	BatchInsert(entities []models.Model) ([]models.Model, *cd.Error)
+	// BulkLoad streams entities of the same model into the host table, using COPY on PostgreSQL.
+	// Model hooks are not run.
+	BulkLoad(entities iter.Seq[models.Model]) (int64, *cd.Error)
 	Update(entity models.Model) (models.Model, *cd.Error)
+	// UpdateFields updates only the named fields of entity, dotted paths update fields of contained relation entities.
+	UpdateFields(entity models.Model, fieldNames ...string) (models.Model, *cd.Error)
+	// UpdateByFilter sets the assigned basic fields on every row matching filter and returns the affected row count.
+	// Model hooks are not run.
+	UpdateByFilter(filter models.Filter, assignments map[string]any) (int64, *cd.Error)
+	// UpdateByExpr atomically applies arithmetic expressions to numeric fields on every row matching filter and guards, returns the affected row count.
+	// Model hooks are not run.
+	UpdateByExpr(filter models.Filter, exprs []models.UpdateExpr, guards ...models.UpdateGuard) (int64, *cd.Error)
+	// Delete deletes entity, models declaring a soft delete field only get the deletion time set.
 	Delete(entity models.Model) (models.Model, *cd.Error)
-	Query(entity models.Model) (models.Model, *cd.Error)
+	// DeleteByFilter deletes every row matching filter together with its relations and returns the affected row count.
+	// Hooks of the filtered model are not run, contained relation entities still run their delete hooks.
+	DeleteByFilter(filter models.Filter) (int64, *cd.Error)
+	// HardDelete physically deletes entity even if its model declares a soft delete field.
+	HardDelete(entity models.Model) (models.Model, *cd.Error)
+	// HardDeleteByFilter physically deletes every row matching filter even if the model declares a soft delete field.
+	// Hooks are run as in DeleteByFilter.
+	HardDeleteByFilter(filter models.Filter) (int64, *cd.Error)
+	// Restore clears the deletion time of a soft deleted entity in a transaction, running BeforeUpdate/AfterUpdate hooks,
+	// and returns the stored entity.
+	Restore(entity models.Model) (models.Model, *cd.Error)
+	// Query loads the entity matching the assigned fields, opts controls relation loading depth, fields and child view.
+	Query(entity models.Model, opts ...LoadOption) (models.Model, *cd.Error)
+	// QueryByIDs loads the entities whose primary keys are in ids, aligned to ids with nil for missing ones, which are also returned.
+	QueryByIDs(entity models.Model, ids []any, opts ...LoadOption) ([]models.Model, []any, *cd.Error)
 	Count(filter models.Filter) (int64, *cd.Error)
-	BatchQuery(filter models.Filter) ([]models.Model, *cd.Error)
+	// BatchQuery loads every entity matching filter, opts controls relation loading as in Query.
+	BatchQuery(filter models.Filter, opts ...LoadOption) ([]models.Model, *cd.Error)
+	// Iterate streams every entity matching filter to handler through a cursor, loading relations chunk by chunk.
+	Iterate(filter models.Filter, handler func(models.Model) *cd.Error, opts ...LoadOption) *cd.Error
+	// LoadRelation returns a copy of a queried entity with the relation field fieldName loaded, opts applies below that field.
+	LoadRelation(entity models.Model, fieldName string, opts ...LoadOption) (models.Model, *cd.Error)
+	// BatchLoadRelation loads the relation field fieldName for entities of the same model with a single relation table query.
+	BatchLoadRelation(entities []models.Model, fieldName string, opts ...LoadOption) ([]models.Model, *cd.Error)
+	// QueryRelation loads the entities of the slice relation fieldName matching filter, which binds the related model and may sort and page.
+	QueryRelation(entity models.Model, fieldName string, filter models.Filter, opts ...LoadOption) ([]models.Model, *cd.Error)
+	// CountRelation counts the entities of the slice relation fieldName matching filter.
+	CountRelation(entity models.Model, fieldName string, filter models.Filter) (int64, *cd.Error)
+	// RawQuery runs a native query in the current transaction and maps result columns by name onto basic fields of entity's model.
+	RawQuery(entity models.Model, sql string, args ...any) ([]models.Model, *cd.Error)
+	// RawExec runs a native statement in the current transaction and returns the affected row count.
+	RawExec(sql string, args ...any) (int64, *cd.Error)
 	BeginTransaction() *cd.Error
 	CommitTransaction() *cd.Error
 	RollbackTransaction() *cd.Error
+	// SetStatementTimeout sets the default timeout of each SQL statement, timeout<=0 means unlimited.
+	SetStatementTimeout(timeout time.Duration)
+	// WithTimeout returns an Orm sharing the same connection whose calls use timeout per SQL statement.
+	WithTimeout(timeout time.Duration) Orm
+	// WithInterceptors returns an Orm sharing the same connection whose calls also pass through interceptors.
+	WithInterceptors(interceptors ...Interceptor) Orm
 	Release()
 }
 
@@ -51,6 +103,7 @@ var (
 	name2PoolInitializeOnce    sync.Once
 	name2PoolUninitializedOnce sync.Once
 	name2LastPoolStats         sync.Map
+	name2Options               sync.Map
 	validationManagerPool      sync.Pool
 
 	ormMetricProvider  *metricsorm.ORMMetricProvider
@@ -206,6 +259,7 @@ func Uninitialized() {
 			logPoolStats(owner, "uninitialize_pool", pool, true)
 			pool.Uninitialized()
 			name2LastPoolStats.Delete(owner)
+			name2Options.Delete(owner)
 
 			return true
 		})
@@ -214,8 +268,10 @@ func Uninitialized() {
 	})
 }
 
-func AddDatabase(dbServer, dbName, username, password string, maxConnNum int, owner string) (err *cd.Error) {
-	config := NewConfig(dbServer, dbName, username, password)
+// AddDatabase 为owner注册数据库连接池，opts 可指定TLS等连接配置
+func AddDatabase(dbServer, dbName, username, password string, maxConnNum int, owner string, opts ...DatabaseOption) (err *cd.Error) {
+	config := NewConfig(dbServer, dbName, username, password, opts...)
+	options := newDatabaseOptions(opts...)
 
 	val, ok := name2Pool.Load(owner)
 	if ok {
@@ -227,6 +283,7 @@ func AddDatabase(dbServer, dbName, username, password string, maxConnNum int, ow
 	}
 
 	pool := NewPool()
+	pool.SetRetryPolicy(options.retryPolicy)
 	err = pool.Initialize(maxConnNum, config)
 	if err != nil {
 		slog.Error("AddDatabase pool.Initialize failed", "owner", owner, "error", err.Error())
@@ -235,10 +292,75 @@ func AddDatabase(dbServer, dbName, username, password string, maxConnNum int, ow
 
 	pool.IncReference()
 	name2Pool.Store(owner, pool)
+	name2Options.Store(owner, options)
 	logPoolStats(owner, "initialize_pool", pool, true)
 	return
 }
 
+// SetRetryPolicy 修改owner连接池的重试策略，对之后获取的Orm生效
+func SetRetryPolicy(owner string, policy *database.RetryPolicy) *cd.Error {
+	val, ok := name2Pool.Load(owner)
+	if !ok {
+		return cd.NewError(cd.NotFound, fmt.Sprintf("can't find database,owner:%s", owner))
+	}
+
+	val.(database.Pool).SetRetryPolicy(policy)
+	return nil
+}
+
+// HealthCheck Ping owner对应的数据库并返回连接池状态
+func HealthCheck(ctx context.Context, owner string) (ret database.HealthStatus, err *cd.Error) {
+	val, ok := name2Pool.Load(owner)
+	if !ok {
+		err = cd.NewError(cd.NotFound, fmt.Sprintf("can't find database,owner:%s", owner))
+		return
+	}
+
+	pool := val.(database.Pool)
+	ret = pool.HealthCheck(ctx)
+	logPoolStats(owner, "health_check", pool, false)
+	return
+}
+
+// DrainDatabase 停止为owner分配新的Orm，并等待已获取的Orm全部Release，超时返回cd.Timeout
+//
+// 排空后GetOrm返回cd.ServiceUnavailable，可调用DelDatabase关闭或ReplaceDatabase恢复
+func DrainDatabase(owner string, timeout time.Duration) *cd.Error {
+	val, ok := name2Pool.Load(owner)
+	if !ok {
+		return cd.NewError(cd.NotFound, fmt.Sprintf("can't find database,owner:%s", owner))
+	}
+
+	pool := val.(database.Pool)
+	logPoolStats(owner, "drain_pool", pool, true)
+	err := pool.Drain(timeout)
+	if err != nil {
+		slog.Warn("DrainDatabase failed", "owner", owner, "error", err.Error())
+	}
+	return err
+}
+
+// ReplaceDatabase 使用新配置替换owner的数据库连接，用于轮换账号密码等场景
+//
+// 替换后GetOrm使用新连接，已获取的Orm继续使用旧连接直至Release，旧连接在全部Release后关闭，
+// 不会中断仍在使用旧连接的Orm(如长时间的Iterate或事务)
+func ReplaceDatabase(owner string, config database.Config) *cd.Error {
+	val, ok := name2Pool.Load(owner)
+	if !ok {
+		return cd.NewError(cd.NotFound, fmt.Sprintf("can't find database,owner:%s", owner))
+	}
+
+	pool := val.(database.Pool)
+	err := pool.Replace(config, 0)
+	if err != nil {
+		slog.Error("ReplaceDatabase failed", "owner", owner, "error", err.Error())
+		return err
+	}
+
+	logPoolStats(owner, "replace_pool", pool, true)
+	return nil
+}
+
 func DelDatabase(owner string) {
 	val, ok := name2Pool.Load(owner)
 	if !ok {
@@ -251,6 +373,7 @@ func DelDatabase(owner string) {
 		pool.Uninitialized()
 		name2Pool.Delete(owner)
 		name2LastPoolStats.Delete(owner)
+		name2Options.Delete(owner)
 	}
 }
 
@@ -278,7 +401,7 @@ func NewOrm(provider provider.Provider, cfg database.Config, prefix string) (Orm
 	return orm, nil
 }
 
-// GetOrm get orm from pool
+// GetOrm get orm from pool, ctx 中通过 ContextWithSchema 指定的schema会应用到获取的连接上
 func GetOrm(ctx context.Context, provider provider.Provider, prefix string) (ret Orm, err *cd.Error) {
 	if provider == nil {
 		err = cd.NewError(cd.IllegalParam, "provider is nil")
@@ -300,9 +423,16 @@ func GetOrm(ctx context.Context, provider provider.Provider, prefix string) (ret
 		slog.Error("GetOrm pool.GetExecutor failed", "owner", provider.Owner(), "error", err.Error())
 		return
 	}
+	if schema := SchemaFromContext(ctx); schema != "" {
+		err = executorVal.SwitchSchema(schema)
+		if err != nil {
+			executorVal.Release()
+			slog.Error("GetOrm switch schema failed", "owner", provider.Owner(), "schema", schema, "error", err.Error())
+			return
+		}
+	}
 	logPoolStats(provider.Owner(), "acquire_executor", pool, false)
-
-	ret = &impl{
+	ormPtr := &impl{
 		context:             ctx,
 		executor:            executorVal,
 		modelProvider:       provider,
@@ -311,6 +441,14 @@ func GetOrm(ctx context.Context, provider provider.Provider, prefix string) (ret
 		validationCache:     false,
 		pooledValidationMgr: true,
 	}
+	if optionsVal, optionsOK := name2Options.Load(provider.Owner()); optionsOK {
+		options := optionsVal.(*databaseOptions)
+		executorVal.SetStatementTimeout(options.statementTimeout)
+		ormPtr.batchParamLimit = options.batchParamLimit
+		ormPtr.interceptors = options.interceptors
+	}
+
+	ret = ormPtr
 	return
 }
 
@@ -323,6 +461,14 @@ type impl struct {
 	validationMgr       validation.ValidationManager
 	validationCache     bool
 	pooledValidationMgr bool
+	// callTimeout WithTimeout 指定的单条SQL执行超时
+	callTimeout time.Duration
+	// borrowed 共享其他Orm的连接，Release时不释放
+	borrowed bool
+	// batchParamLimit BatchInsert 单条SQL的参数个数上限，<=0 使用默认值
+	batchParamLimit int
+	// interceptors 包裹每次调用的拦截器，按顺序由外向内执行
+	interceptors []Interceptor
 }
 
 // BeginTransaction begin transaction
@@ -395,6 +541,10 @@ func (s *impl) finalTransaction(err *cd.Error) {
 }
 
 func (s *impl) Release() {
+	if s.borrowed {
+		return
+	}
+
 	if s.pooledValidationMgr && s.validationMgr != nil {
 		releaseValidationManager(s.validationMgr)
 		s.validationMgr = nil
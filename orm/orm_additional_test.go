@@ -3,7 +3,9 @@ package orm
 import (
 	"context"
 	"testing"
+	"time"
 
+	cd "github.com/muidea/magicCommon/def"
 	"github.com/muidea/magicOrm/database/postgres"
 )
 
@@ -18,3 +20,32 @@ func TestProviderGuardClauses(t *testing.T) {
 		t.Fatal("expected GetOrm to reject nil provider")
 	}
 }
+
+func TestDatabaseManagementUnknownOwner(t *testing.T) {
+	cfg := postgres.NewConfig("127.0.0.1:5432", "demo", "user", "password")
+
+	if _, err := HealthCheck(context.Background(), "missing-owner"); err == nil || err.Code != cd.NotFound {
+		t.Fatalf("expected HealthCheck NotFound, got %v", err)
+	}
+	if err := DrainDatabase("missing-owner", time.Millisecond); err == nil || err.Code != cd.NotFound {
+		t.Fatalf("expected DrainDatabase NotFound, got %v", err)
+	}
+	if err := ReplaceDatabase("missing-owner", cfg); err == nil || err.Code != cd.NotFound {
+		t.Fatalf("expected ReplaceDatabase NotFound, got %v", err)
+	}
+}
+
+func TestContextWithSchema(t *testing.T) {
+	if schema := SchemaFromContext(context.Background()); schema != "" {
+		t.Fatalf("unexpected schema %q", schema)
+	}
+
+	ctx := ContextWithSchema(context.Background(), "tenant_a")
+	if schema := SchemaFromContext(ctx); schema != "tenant_a" {
+		t.Fatalf("unexpected schema %q", schema)
+	}
+
+	if _, err := GetOrmForSchema(ctx, nil, "", "tenant_a"); err == nil || err.Code != cd.IllegalParam {
+		t.Fatalf("expected GetOrmForSchema to reject nil provider, got %v", err)
+	}
+}
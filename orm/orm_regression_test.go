@@ -76,7 +76,10 @@ func TestOrmPublicQueryContract(t *testing.T) {
 	if !ok {
 		t.Fatal("Query must remain part of the public Orm interface")
 	}
-	if queryMethod.Type.NumIn() != 1 || queryMethod.Type.In(0) != reflect.TypeOf((*models.Model)(nil)).Elem() {
+	loadOptionsType := reflect.TypeOf([]LoadOption{})
+	if queryMethod.Type.NumIn() != 2 || !queryMethod.Type.IsVariadic() ||
+		queryMethod.Type.In(0) != reflect.TypeOf((*models.Model)(nil)).Elem() ||
+		queryMethod.Type.In(1) != loadOptionsType {
 		t.Fatalf("unexpected Query input signature: %s", queryMethod.Type.String())
 	}
 	if queryMethod.Type.NumOut() != 2 ||
@@ -89,7 +92,9 @@ func TestOrmPublicQueryContract(t *testing.T) {
 	if !ok {
 		t.Fatal("BatchQuery must remain part of the public Orm interface")
 	}
-	if batchMethod.Type.NumIn() != 1 || batchMethod.Type.In(0) != reflect.TypeOf((*models.Filter)(nil)).Elem() {
+	if batchMethod.Type.NumIn() != 2 || !batchMethod.Type.IsVariadic() ||
+		batchMethod.Type.In(0) != reflect.TypeOf((*models.Filter)(nil)).Elem() ||
+		batchMethod.Type.In(1) != loadOptionsType {
 		t.Fatalf("unexpected BatchQuery input signature: %s", batchMethod.Type.String())
 	}
 	if batchMethod.Type.NumOut() != 2 ||
@@ -43,6 +43,15 @@ type QueryRunner struct {
 	lastQuerySQL             string
 	lastQueryArgs            int
 	selectedBasicFieldIndexs []int
+	// loadOptions 关系加载选项，子对象的QueryRunner共享
+	loadOptions *loadOptions
+	// relationPath 当前模型相对顶层模型的关系字段路径，顶层为空
+	relationPath string
+	// ownerModel 非空时只查询ownerModel的ownerField关系中的对象
+	ownerModel models.Model
+	ownerField models.Field
+	// selectFields filter.Select指定的字段，为空时查询全部字段
+	selectFields []string
 }
 
 type relationPrefetchGroup struct {
@@ -55,18 +64,18 @@ func durationMs(val time.Duration) float64 {
 	return float64(val) / float64(time.Millisecond)
 }
 
-func selectedQueryBasicFieldCount(vModel models.Model) int {
-	return len(selectedQueryBasicFieldIndexes(vModel))
+func selectedQueryBasicFieldCount(vModel models.Model, selectFields []string) int {
+	return len(selectedQueryBasicFieldIndexes(vModel, selectFields))
 }
 
-func selectedQueryBasicFieldIndexes(vModel models.Model) []int {
+func selectedQueryBasicFieldIndexes(vModel models.Model, selectFields []string) []int {
 	if vModel == nil {
 		return nil
 	}
 
 	indexes := make([]int, 0, len(vModel.GetFields()))
 	for idx, field := range vModel.GetFields() {
-		if !models.IsBasicField(field) {
+		if !models.IsBasicField(field) || !models.IsSelectedField(selectFields, field) {
 			continue
 		}
 		if !models.IsValidField(field) && !(models.IsSliceField(field) && !models.IsPtrField(field)) {
@@ -292,7 +301,8 @@ func NewQueryRunner(
 		relationMisses:           map[string]struct{}{},
 		relationEdges:            map[string][]any{},
 		relationWarns:            map[string]struct{}{},
-		selectedBasicFieldIndexs: selectedQueryBasicFieldIndexes(vModel),
+		selectedBasicFieldIndexs: selectedQueryBasicFieldIndexes(vModel, nil),
+		loadOptions:              newLoadOptions(),
 	}
 }
 
@@ -426,6 +436,12 @@ func (s *QueryRunner) getCachedRelationEdge(pkgKey, fieldName string, leftID any
 }
 
 func (s *QueryRunner) shouldLoadRelationField(field models.Field) bool {
+	if field != nil && !s.loadOptions.loadRelation(joinRelationPath(s.relationPath, field.GetName())) {
+		return false
+	}
+	if field != nil && !models.IsSelectedField(s.selectFields, field) {
+		return false
+	}
 	if field == nil || s.responseModel == nil {
 		return true
 	}
@@ -443,10 +459,10 @@ func (s *QueryRunner) relationResponseModel(field models.Field) (ret models.Mode
 		return
 	}
 
-	// Child relations follow the ORM built-in rule instead of caller-provided nested masks:
+	// Child relations follow the load options instead of caller-provided nested masks:
 	// once the relation field is included in the top-level response, the child itself is
-	// always queried and projected by its own lite view.
-	ret, err = s.relationLiteResponseModel(field)
+	// always queried and projected by the child view, lite view by default.
+	ret, err = s.relationChildResponseModel(field)
 	if err != nil {
 		return
 	}
@@ -455,7 +471,7 @@ func (s *QueryRunner) relationResponseModel(field models.Field) (ret models.Mode
 	return
 }
 
-func (s *QueryRunner) relationLiteResponseModel(field models.Field) (ret models.Model, err *cd.Error) {
+func (s *QueryRunner) relationChildResponseModel(field models.Field) (ret models.Model, err *cd.Error) {
 	relationType := field.GetType()
 	if models.IsSliceField(field) {
 		relationType = relationType.Elem()
@@ -467,12 +483,23 @@ func (s *QueryRunner) relationLiteResponseModel(field models.Field) (ret models.
 		return
 	}
 
-	ret = baseModel.Copy(models.LiteView)
+	ret = baseModel.Copy(s.loadOptions.relationView())
 	return
 }
 
+// newRelationQueryRunner 查询vField关联对象的QueryRunner，共享关系缓存与加载选项
+func (s *QueryRunner) newRelationQueryRunner(vField models.Field, queryMask, responseModel models.Model, responseByMask bool, batchFilter bool, deepLevel int) *QueryRunner {
+	rQueryRunner := NewQueryRunner(s.context, queryMask, responseModel, responseByMask, s.executor, s.modelProvider, s.modelCodec, batchFilter, deepLevel+1)
+	rQueryRunner.relationCache = s.relationCache
+	rQueryRunner.relationMisses = s.relationMisses
+	rQueryRunner.relationEdges = s.relationEdges
+	rQueryRunner.loadOptions = s.loadOptions
+	rQueryRunner.relationPath = joinRelationPath(s.relationPath, vField.GetName())
+	return rQueryRunner
+}
+
 func (s *QueryRunner) prefetchRelations(modelList []models.Model, deepLevel int) (err *cd.Error) {
-	if len(modelList) == 0 || deepLevel > maxDeepLevel {
+	if len(modelList) == 0 || !s.loadOptions.loadDepth(deepLevel) {
 		return
 	}
 	if len(modelList) == 1 && deepLevel > 0 {
@@ -530,24 +557,7 @@ func (s *QueryRunner) prefetchRelations(modelList []models.Model, deepLevel int)
 			continue
 		}
 
-		uniqueIDs := []any{}
-		seenIDs := map[string]struct{}{}
-		for _, leftID := range group.leftIDs {
-			rightIDs, ok := s.getCachedRelationEdge(group.model.GetPkgKey(), group.field.GetName(), leftID)
-			if !ok {
-				continue
-			}
-
-			for _, rightID := range rightIDs {
-				rightKey := fmt.Sprintf("%v", rightID)
-				if _, exists := seenIDs[rightKey]; exists {
-					continue
-				}
-				seenIDs[rightKey] = struct{}{}
-				uniqueIDs = append(uniqueIDs, rightID)
-			}
-		}
-
+		uniqueIDs := s.collectRelationRightIDs(group.model, group.field, group.leftIDs)
 		if len(uniqueIDs) == 0 {
 			continue
 		}
@@ -559,6 +569,28 @@ func (s *QueryRunner) prefetchRelations(modelList []models.Model, deepLevel int)
 	return
 }
 
+// collectRelationRightIDs 从已缓存的关系边中收集leftIDs关联的对象ID，去重并保持顺序
+func (s *QueryRunner) collectRelationRightIDs(vModel models.Model, vField models.Field, leftIDs []any) (ret []any) {
+	seenIDs := map[string]struct{}{}
+	for _, leftID := range leftIDs {
+		rightIDs, ok := s.getCachedRelationEdge(vModel.GetPkgKey(), vField.GetName(), leftID)
+		if !ok {
+			continue
+		}
+
+		for _, rightID := range rightIDs {
+			rightKey := fmt.Sprintf("%v", rightID)
+			if _, exists := seenIDs[rightKey]; exists {
+				continue
+			}
+			seenIDs[rightKey] = struct{}{}
+			ret = append(ret, rightID)
+		}
+	}
+
+	return
+}
+
 func (s *QueryRunner) batchQueryRelationKeys(vModel models.Model, vField models.Field, leftIDs []any) (err *cd.Error) {
 	if len(leftIDs) == 0 {
 		return
@@ -724,10 +756,7 @@ func (s *QueryRunner) batchQueryRelationModels(vField models.Field, ids []any, d
 		}
 	}
 
-	rQueryRunner := NewQueryRunner(s.context, queryMask, responseModel, responseByMask, s.executor, s.modelProvider, s.modelCodec, true, deepLevel+1)
-	rQueryRunner.relationCache = s.relationCache
-	rQueryRunner.relationMisses = s.relationMisses
-	rQueryRunner.relationEdges = s.relationEdges
+	rQueryRunner := s.newRelationQueryRunner(vField, queryMask, responseModel, responseByMask, true, deepLevel)
 	queryVal, queryErr := rQueryRunner.Query(vFilter)
 	if queryErr != nil {
 		err = queryErr
@@ -855,10 +884,7 @@ func (s *QueryRunner) queryRelationModel(vField models.Field, id any, deepLevel
 		}
 	}
 
-	rQueryRunner := NewQueryRunner(s.context, queryMask, responseModel, responseByMask, s.executor, s.modelProvider, s.modelCodec, false, deepLevel+1)
-	rQueryRunner.relationCache = s.relationCache
-	rQueryRunner.relationMisses = s.relationMisses
-	rQueryRunner.relationEdges = s.relationEdges
+	rQueryRunner := s.newRelationQueryRunner(vField, queryMask, responseModel, responseByMask, false, deepLevel)
 	queryVal, queryErr := rQueryRunner.Query(vFilter)
 	if queryErr != nil {
 		err = queryErr
@@ -878,8 +904,45 @@ func (s *QueryRunner) queryRelationModel(vField models.Field, id any, deepLevel
 	return
 }
 
+// applySelectFields 按filter.Select限定查询字段，未选中的字段重置为未赋值，既不查询列也不加载关系
+func (s *QueryRunner) applySelectFields(filter models.Filter) (err *cd.Error) {
+	if filter == nil || len(filter.GetSelectFields()) == 0 {
+		return
+	}
+
+	selectFields := filter.GetSelectFields()
+	for _, name := range selectFields {
+		if s.vModel.GetField(name) == nil {
+			err = cd.NewError(cd.IllegalParam, fmt.Sprintf("illegal select field, model:%s, field:%s", s.vModel.GetPkgKey(), name))
+			return
+		}
+	}
+
+	selectModel := s.vModel.Copy(models.OriginView)
+	for _, field := range selectModel.GetFields() {
+		if !models.IsSelectedField(selectFields, field) {
+			field.Reset()
+		}
+	}
+
+	s.vModel = selectModel
+	s.selectFields = selectFields
+	s.selectedBasicFieldIndexs = selectedQueryBasicFieldIndexes(selectModel, selectFields)
+	s.skipProjectResponse = canSkipProjectResponse(selectModel, s.responseModel, s.responseByMask)
+	return
+}
+
+// buildQueryResult 生成查询SQL，设置了ownerModel时只查询其关系中的对象
+func (s *QueryRunner) buildQueryResult(vModel models.Model, filter models.Filter) (database.Result, *cd.Error) {
+	if s.ownerModel != nil {
+		return s.sqlBuilder.BuildQueryRelationModels(s.ownerModel, s.ownerField, vModel, filter)
+	}
+
+	return s.sqlBuilder.BuildQuery(vModel, filter)
+}
+
 func (s *QueryRunner) innerQuery(vModel models.Model, filter models.Filter) (ret resultItemsList, queryExecDuration time.Duration, rowScanDuration time.Duration, err *cd.Error) {
-	queryResult, queryErr := s.sqlBuilder.BuildQuery(vModel, filter)
+	queryResult, queryErr := s.buildQueryResult(vModel, filter)
 	if queryErr != nil {
 		err = queryErr
 		slog.Error("QueryRunner innerQuery BuildQuery failed", "error", err.Error())
@@ -897,10 +960,17 @@ func (s *QueryRunner) innerQuery(vModel models.Model, filter models.Filter) (ret
 	}
 	defer s.executor.Finish()
 
+	stageStartTime = time.Now()
+	ret, err = s.scanQueryRows()
+	rowScanDuration = time.Since(stageStartTime)
+	return
+}
+
+// scanQueryRows 读取executor当前结果集中的全部行
+func (s *QueryRunner) scanQueryRows() (ret resultItemsList, err *cd.Error) {
 	fieldCount := len(s.selectedBasicFieldIndexs)
 	referenceVal := make([]any, fieldCount)
 	queryList := resultItemsList{}
-	stageStartTime = time.Now()
 	for s.executor.Next() {
 		itemValues := make(resultItems, fieldCount)
 		for idx := range itemValues {
@@ -915,7 +985,6 @@ func (s *QueryRunner) innerQuery(vModel models.Model, filter models.Filter) (ret
 
 		queryList = append(queryList, itemValues)
 	}
-	rowScanDuration = time.Since(stageStartTime)
 
 	ret = queryList
 	return
@@ -998,7 +1067,7 @@ func (s *QueryRunner) assignBasicField(vField models.Field, val any) (err *cd.Er
 }
 
 func (s *QueryRunner) queryRelation(vModel models.Model, vField models.Field, deepLevel int) (err *cd.Error) {
-	if deepLevel > maxDeepLevel {
+	if !s.loadOptions.loadDepth(deepLevel) {
 		return
 	}
 
@@ -1115,7 +1184,7 @@ func (s *QueryRunner) innerQueryRelationSingleModel(id any, vField models.Field,
 		return
 	}
 
-	if deepLevel < maxDeepLevel && s.shouldWarnRelationMiss(vField.GetType().GetPkgKey(), id) {
+	if s.loadOptions.loadDepth(deepLevel+1) && s.shouldWarnRelationMiss(vField.GetType().GetPkgKey(), id) {
 		// 到这里说明未查询到关联目标，当前行为是记录告警并保留字段为空，
 		// 由调用方或外部治理流程处理关系表与目标表之间的数据不一致。
 		slog.Warn("query relation failed, miss relation data", "model", vField.GetType().GetPkgKey(), "id", id)
@@ -1166,7 +1235,7 @@ func (s *QueryRunner) innerQueryRelationSliceModel(ids []any, vField models.Fiel
 			}
 		}
 		if cachedModel == nil {
-			if deepLevel < maxDeepLevel && s.shouldWarnRelationMiss(svModel.GetPkgKey(), id) {
+			if s.loadOptions.loadDepth(deepLevel+1) && s.shouldWarnRelationMiss(svModel.GetPkgKey(), id) {
 				slog.Warn("query relation failed, miss relation data", "model", svModel.GetPkgKey(), "id", id)
 			}
 			continue
@@ -1178,43 +1247,10 @@ func (s *QueryRunner) innerQueryRelationSliceModel(ids []any, vField models.Fiel
 	return
 }
 
-func (s *QueryRunner) Query(filter models.Filter) (ret []models.Model, err *cd.Error) {
-	queryStartTime := time.Now()
-	var (
-		dbQueryDuration          time.Duration
-		queryExecDuration        time.Duration
-		rowScanDuration          time.Duration
-		assignBasicDuration      time.Duration
-		prefetchRelationDuration time.Duration
-		assignRelationDuration   time.Duration
-		projectResponseDuration  time.Duration
-	)
-
-	if err = s.checkContext(); err != nil {
-		return
-	}
-
-	stageStartTime := time.Now()
-	queryValueList, queryExecDuration, rowScanDuration, queryValueErr := s.innerQuery(s.vModel, filter)
-	dbQueryDuration = time.Since(stageStartTime)
-	if queryValueErr != nil {
-		err = queryValueErr
-		slog.Error("QueryRunner failed", "error", err.Error())
-		return
-	}
-
-	queryCount := len(queryValueList)
-	if queryCount == 0 {
-		return
-	}
-	if !s.batchFilter && queryCount > 1 {
-		err = cd.NewError(cd.Unexpected, fmt.Sprintf("matched model:%s %d items value", s.vModel.GetPkgKey(), queryCount))
-		slog.Warn("Query failed", "error", err.Error())
-		return
-	}
-
+// assignQueryModels 由查询到的行构造模型，批量预取并回填关系，按响应模型裁剪后调用AfterQuery
+func (s *QueryRunner) assignQueryModels(queryValueList resultItemsList) (ret []models.Model, assignBasicDuration, prefetchRelationDuration, assignRelationDuration, projectResponseDuration time.Duration, err *cd.Error) {
 	sliceValue := []models.Model{}
-	stageStartTime = time.Now()
+	stageStartTime := time.Now()
 	for idx := range queryValueList {
 		modelVal, modelErr := s.innerAssignBasic(s.vModel, queryValueList[idx])
 		if modelErr != nil {
@@ -1246,6 +1282,54 @@ func (s *QueryRunner) Query(filter models.Filter) (ret []models.Model, err *cd.E
 			sliceValue[idx] = applyQueryResponseModel(sliceValue[idx], s.responseModel, s.responseByMask)
 		}
 		projectResponseDuration += time.Since(stageStartTime)
+
+		if err = invokeHooks(s.context, sliceValue[idx], AfterQuery); err != nil {
+			return
+		}
+	}
+
+	ret = sliceValue
+	return
+}
+
+func (s *QueryRunner) Query(filter models.Filter) (ret []models.Model, err *cd.Error) {
+	queryStartTime := time.Now()
+	var (
+		dbQueryDuration   time.Duration
+		queryExecDuration time.Duration
+		rowScanDuration   time.Duration
+	)
+
+	if err = s.checkContext(); err != nil {
+		return
+	}
+	if err = s.applySelectFields(filter); err != nil {
+		slog.Error("QueryRunner applySelectFields failed", "error", err.Error())
+		return
+	}
+
+	stageStartTime := time.Now()
+	queryValueList, queryExecDuration, rowScanDuration, queryValueErr := s.innerQuery(s.vModel, filter)
+	dbQueryDuration = time.Since(stageStartTime)
+	if queryValueErr != nil {
+		err = queryValueErr
+		slog.Error("QueryRunner failed", "error", err.Error())
+		return
+	}
+
+	queryCount := len(queryValueList)
+	if queryCount == 0 {
+		return
+	}
+	if !s.batchFilter && queryCount > 1 {
+		err = cd.NewError(cd.Unexpected, fmt.Sprintf("matched model:%s %d items value", s.vModel.GetPkgKey(), queryCount))
+		slog.Warn("Query failed", "error", err.Error())
+		return
+	}
+
+	sliceValue, assignBasicDuration, prefetchRelationDuration, assignRelationDuration, projectResponseDuration, err := s.assignQueryModels(queryValueList)
+	if err != nil {
+		return
 	}
 
 	totalDuration := time.Since(queryStartTime)
@@ -1273,7 +1357,11 @@ func (s *QueryRunner) Query(filter models.Filter) (ret []models.Model, err *cd.E
 	return
 }
 
-func (s *impl) Query(vModel models.Model) (ret models.Model, err *cd.Error) {
+func (s *impl) Query(vModel models.Model, opts ...LoadOption) (ret models.Model, err *cd.Error) {
+	if len(s.interceptors) > 0 {
+		return s.interceptQuery(vModel, opts)
+	}
+
 	startTime := time.Now()
 
 	defer func() {
@@ -1287,6 +1375,9 @@ func (s *impl) Query(vModel models.Model) (ret models.Model, err *cd.Error) {
 		return
 	}
 
+	restoreTimeout := s.applyCallTimeout()
+	defer restoreTimeout()
+
 	if vModel == nil {
 		err = cd.NewError(cd.IllegalParam, "query model is nil")
 		return
@@ -1294,6 +1385,11 @@ func (s *impl) Query(vModel models.Model) (ret models.Model, err *cd.Error) {
 
 	// 这里主动Copy一份出来，是为了避免在查询数据过程中对源数据产生了干扰
 	vModel = vModel.Copy(models.OriginView)
+	err = invokeHooks(s.context, vModel, BeforeQuery)
+	if err != nil {
+		return
+	}
+
 	vFilter, vErr := getModelFilter(vModel, s.modelProvider, s.modelCodec)
 	if vErr != nil {
 		err = vErr
@@ -1312,6 +1408,7 @@ func (s *impl) Query(vModel models.Model) (ret models.Model, err *cd.Error) {
 	}
 
 	vQueryRunner := NewQueryRunner(s.context, queryMask, responseModel, responseByMask, s.executor, s.modelProvider, s.modelCodec, false, 0)
+	vQueryRunner.loadOptions = newLoadOptions(opts...)
 	queryVal, queryErr := vQueryRunner.Query(vFilter)
 	if queryErr != nil {
 		err = queryErr
@@ -0,0 +1,119 @@
+package orm
+
+import (
+	"time"
+
+	cd "github.com/muidea/magicCommon/def"
+
+	"log/slog"
+
+	"github.com/muidea/magicOrm/metrics"
+	"github.com/muidea/magicOrm/models"
+)
+
+// QueryByIDs 每次以chunkSize个主键查询，结果与ids逐个对应，未查询到的位置为nil，missingIDs去重后按ids中的顺序列出
+func (s *QueryRunner) QueryByIDs(ids []any, chunkSize int) (ret []models.Model, missingIDs []any, err *cd.Error) {
+	idOrder, pendingIDs, prepareErr := s.prepareRelationIDs(s.vModel, ids)
+	if prepareErr != nil {
+		err = prepareErr
+		return
+	}
+
+	pkField := s.vModel.GetPrimaryField()
+	pkgKey := s.vModel.GetPkgKey()
+	foundModels := map[string]models.Model{}
+	for offset := 0; offset < len(pendingIDs); offset += chunkSize {
+		chunkIDs := pendingIDs[offset:min(offset+chunkSize, len(pendingIDs))]
+		vFilter, vErr := s.modelProvider.GetModelFilter(s.vModel)
+		if vErr != nil {
+			err = vErr
+			return
+		}
+		if err = vFilter.In(pkField.GetName(), buildTypedValueSlice(chunkIDs)); err != nil {
+			return
+		}
+
+		queryVal, queryErr := s.Query(vFilter)
+		if queryErr != nil {
+			err = queryErr
+			return
+		}
+		for _, modelVal := range queryVal {
+			foundID, foundErr := s.modelCodec.ExtractBasicFieldValue(pkField, modelVal.GetPrimaryField().GetValue().Get())
+			if foundErr != nil {
+				err = foundErr
+				return
+			}
+			foundModels[relationCacheKey(pkgKey, foundID)] = modelVal
+		}
+	}
+
+	ret = make([]models.Model, len(idOrder))
+	missingIndex := map[string]struct{}{}
+	for idx, id := range idOrder {
+		cacheKey := relationCacheKey(pkgKey, id)
+		if modelVal, ok := foundModels[cacheKey]; ok {
+			ret[idx] = modelVal
+			continue
+		}
+		if _, exists := missingIndex[cacheKey]; exists {
+			continue
+		}
+
+		missingIDs = append(missingIDs, ids[idx])
+		missingIndex[cacheKey] = struct{}{}
+	}
+	return
+}
+
+// QueryByIDs 按主键列表查询vModel类型的模型，返回结果与ids逐个对应
+//
+// 主键去重后每批查询 WithChunkSize 指定的数量（默认500），同一批的关系一起加载，关系缓存在各批之间复用；
+// 未查询到（包括已软删除）的位置为nil，这些主键按ids中的顺序记入missingIDs，重复的主键只记一次。
+// 返回字段与Query一致按DetailView裁剪，opts与Query一致。
+func (s *impl) QueryByIDs(vModel models.Model, ids []any, opts ...LoadOption) (ret []models.Model, missingIDs []any, err *cd.Error) {
+	if len(s.interceptors) > 0 {
+		return s.interceptQueryByIDs(vModel, ids, opts)
+	}
+
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		if ormMetricCollector != nil {
+			ormMetricCollector.RecordOperation(string(metrics.OperationBatch), vModel, duration, cd.ToStdError(err))
+		}
+	}()
+
+	if err = s.CheckContext(); err != nil {
+		return
+	}
+
+	restoreTimeout := s.applyCallTimeout()
+	defer restoreTimeout()
+
+	if vModel == nil {
+		err = cd.NewError(cd.IllegalParam, "query model is nil")
+		return
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	responseModel := vModel.Copy(models.DetailView)
+	queryMask, maskErr := buildFullQueryMaskModel(responseModel)
+	if maskErr != nil {
+		err = maskErr
+		slog.Error("QueryByIDs buildFullQueryMaskModel failed", "pkgKey", vModel.GetPkgKey(), "error", err.Error())
+		return
+	}
+
+	loadOpts := newLoadOptions(opts...)
+	vQueryRunner := NewQueryRunner(s.context, queryMask, responseModel, false, s.executor, s.modelProvider, s.modelCodec, true, 0)
+	vQueryRunner.loadOptions = loadOpts
+	ret, missingIDs, err = vQueryRunner.QueryByIDs(ids, loadOpts.chunkSize)
+	if err != nil {
+		slog.Error("QueryByIDs QueryRunner.QueryByIDs failed", "pkgKey", vModel.GetPkgKey(), "error", err.Error())
+	}
+	return
+}
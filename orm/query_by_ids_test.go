@@ -0,0 +1,49 @@
+package orm
+
+import (
+	"strings"
+	"testing"
+
+	cd "github.com/muidea/magicCommon/def"
+)
+
+func TestQueryByIDsPreservesInputOrder(t *testing.T) {
+	executor := &fakeExecutor{
+		responses: []fakeQueryResponse{
+			{
+				match: func(querySQL string, args []any) bool {
+					return strings.Contains(querySQL, `FROM "tenant_RelationMember"`) && len(args) == 2
+				},
+				rows: [][]any{{int64(1), "alice", int64(25)}, {int64(3), "carol", int64(35)}},
+			},
+			{
+				match: func(querySQL string, _ []any) bool { return strings.Contains(querySQL, `FROM "tenant_RelationMember"`) },
+			},
+		},
+	}
+	ormImpl, _, memberFilter := newQueryRelationTestOrm(t, executor)
+	memberModel := memberFilter.MaskModel()
+
+	members, missingIDs, err := ormImpl.QueryByIDs(memberModel, []any{3, 1, 2, 3}, WithChunkSize(2))
+	if err != nil {
+		t.Fatalf("QueryByIDs failed: %v", err)
+	}
+	if len(members) != 4 || members[2] != nil {
+		t.Fatalf("unexpected members: %#v", members)
+	}
+	for idx, name := range map[int]string{0: "carol", 1: "alice", 3: "carol"} {
+		if members[idx].Interface(true).(*relationMember).Name != name {
+			t.Fatalf("unexpected member at %d: %#v", idx, members[idx].Interface(true))
+		}
+	}
+	if len(missingIDs) != 1 || missingIDs[0] != 2 {
+		t.Fatalf("unexpected missing ids: %#v", missingIDs)
+	}
+	if len(executor.execCalls) != 2 || !containsSQLCall(executor.execCalls, "query", `WHERE "id" IN ($1,$2)`, []any{3, 1}) {
+		t.Fatalf("unexpected query by ids calls: %#v", executor.execCalls)
+	}
+
+	if _, _, err = ormImpl.QueryByIDs(nil, []any{1}); err == nil || err.Code != cd.IllegalParam {
+		t.Fatalf("nil model should be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,143 @@
+package orm
+
+import (
+	"fmt"
+	"time"
+
+	cd "github.com/muidea/magicCommon/def"
+
+	"log/slog"
+
+	"github.com/muidea/magicOrm/metrics"
+	"github.com/muidea/magicOrm/models"
+)
+
+// getRelationFilterField 校验fieldName为vModel的切片关系字段，且vFilter绑定该关系的关联模型
+func (s *impl) getRelationFilterField(vModel models.Model, fieldName string, vFilter models.Filter) (ret models.Field, err *cd.Error) {
+	vField, fieldErr := getLoadRelationField(vModel, fieldName)
+	if fieldErr != nil {
+		err = fieldErr
+		return
+	}
+	if !models.IsSliceField(vField) {
+		err = cd.NewError(cd.IllegalParam, fmt.Sprintf("relation field is not a slice, model:%s, field:%s", vModel.GetPkgKey(), fieldName))
+		return
+	}
+	if vFilter == nil {
+		err = cd.NewError(cd.IllegalParam, "illegal filter value")
+		return
+	}
+
+	rModel, rErr := s.modelProvider.GetTypeModel(vField.GetType().Elem())
+	if rErr != nil {
+		err = rErr
+		return
+	}
+	if vFilter.MaskModel().GetPkgKey() != rModel.GetPkgKey() {
+		err = cd.NewError(cd.IllegalParam, fmt.Sprintf("filter model mismatch relation field, %s != %s", vFilter.MaskModel().GetPkgKey(), rModel.GetPkgKey()))
+		return
+	}
+
+	ret = vField
+	return
+}
+
+// QueryRelation 查询vModel的fieldName切片关系中满足vFilter的关联对象
+//
+// vFilter绑定关联模型，条件、排序与分页都作用于关联对象，关系表通过子查询限定，不会先加载全部关联对象；
+// 返回字段与opts的语义同BatchQuery，关联对象为顶层对象。
+func (s *impl) QueryRelation(vModel models.Model, fieldName string, vFilter models.Filter, opts ...LoadOption) (ret []models.Model, err *cd.Error) {
+	if len(s.interceptors) > 0 {
+		return s.interceptQueryRelation(vModel, fieldName, vFilter, opts)
+	}
+
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		if ormMetricCollector != nil {
+			var model models.Model
+			if vFilter != nil {
+				model = vFilter.MaskModel()
+			}
+			ormMetricCollector.RecordOperation(string(metrics.OperationBatch), model, duration, cd.ToStdError(err))
+		}
+	}()
+
+	if err = s.CheckContext(); err != nil {
+		return
+	}
+
+	restoreTimeout := s.applyCallTimeout()
+	defer restoreTimeout()
+
+	vField, fieldErr := s.getRelationFilterField(vModel, fieldName, vFilter)
+	if fieldErr != nil {
+		err = fieldErr
+		return
+	}
+
+	responseModel, responseByMask, responseErr := buildQueryResponseModel(nil, vFilter)
+	if responseErr != nil {
+		err = responseErr
+		slog.Error("QueryRelation buildQueryResponseModel failed", "error", err.Error())
+		return
+	}
+
+	queryMask, maskErr := buildQueryExecutionModel(responseModel, !responseByMask)
+	if maskErr != nil {
+		err = maskErr
+		slog.Error("QueryRelation buildQueryExecutionModel failed", "error", err.Error())
+		return
+	}
+
+	vQueryRunner := NewQueryRunner(s.context, queryMask, responseModel, responseByMask, s.executor, s.modelProvider, s.modelCodec, true, 0)
+	vQueryRunner.loadOptions = newLoadOptions(opts...)
+	vQueryRunner.ownerModel = vModel
+	vQueryRunner.ownerField = vField
+	ret, err = vQueryRunner.Query(vFilter)
+	if err != nil {
+		slog.Error("QueryRelation QueryRunner.Query failed", "pkgKey", vModel.GetPkgKey(), "field", fieldName, "error", err.Error())
+	}
+	return
+}
+
+// CountRelation 统计vModel的fieldName切片关系中满足vFilter的关联对象数量，与QueryRelation配合分页
+func (s *impl) CountRelation(vModel models.Model, fieldName string, vFilter models.Filter) (ret int64, err *cd.Error) {
+	if len(s.interceptors) > 0 {
+		return s.interceptCountRelation(vModel, fieldName, vFilter)
+	}
+
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		if ormMetricCollector != nil {
+			var model models.Model
+			if vFilter != nil {
+				model = vFilter.MaskModel()
+			}
+			ormMetricCollector.RecordOperation(string(metrics.OperationCount), model, duration, cd.ToStdError(err))
+		}
+	}()
+
+	if err = s.CheckContext(); err != nil {
+		return
+	}
+
+	restoreTimeout := s.applyCallTimeout()
+	defer restoreTimeout()
+
+	vField, fieldErr := s.getRelationFilterField(vModel, fieldName, vFilter)
+	if fieldErr != nil {
+		err = fieldErr
+		return
+	}
+
+	countRunner := NewCountRunner(s.context, vFilter.MaskModel(), s.executor, s.modelProvider, s.modelCodec)
+	ret, err = countRunner.CountRelation(vModel, vField, vFilter)
+	if err != nil {
+		slog.Error("CountRelation CountRunner.CountRelation failed", "pkgKey", vModel.GetPkgKey(), "field", fieldName, "error", err.Error())
+	}
+	return
+}
@@ -0,0 +1,115 @@
+package orm
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	cd "github.com/muidea/magicCommon/def"
+
+	"github.com/muidea/magicOrm/database/codec"
+	"github.com/muidea/magicOrm/models"
+	"github.com/muidea/magicOrm/provider"
+)
+
+type relationMember struct {
+	ID   int    `orm:"id key auto" view:"detail,lite"`
+	Name string `orm:"name" view:"detail,lite"`
+	Age  int    `orm:"age" view:"detail"`
+}
+
+type relationGroup struct {
+	ID      int               `orm:"id key auto"`
+	Name    string            `orm:"name"`
+	Members []*relationMember `orm:"members"`
+}
+
+func newQueryRelationTestOrm(t *testing.T, executor *fakeExecutor) (*impl, models.Model, models.Filter) {
+	t.Helper()
+
+	localProvider := provider.NewLocalProvider("tenant", nil)
+	for _, entity := range []any{&relationMember{}, &relationGroup{}} {
+		if _, err := localProvider.RegisterModel(entity); err != nil {
+			t.Fatalf("RegisterModel(%T) failed: %v", entity, err)
+		}
+	}
+
+	groupModel, err := localProvider.GetEntityModel(&relationGroup{ID: 1}, true)
+	if err != nil {
+		t.Fatalf("GetEntityModel(relationGroup) failed: %v", err)
+	}
+	memberModel, err := localProvider.GetEntityModel(&relationMember{}, true)
+	if err != nil {
+		t.Fatalf("GetEntityModel(relationMember) failed: %v", err)
+	}
+	memberFilter, err := localProvider.GetModelFilter(memberModel)
+	if err != nil {
+		t.Fatalf("GetModelFilter(relationMember) failed: %v", err)
+	}
+
+	ormImpl := &impl{
+		context:       context.Background(),
+		executor:      executor,
+		modelProvider: localProvider,
+		modelCodec:    codec.New(localProvider, "tenant"),
+	}
+	return ormImpl, groupModel, memberFilter
+}
+
+func TestQueryRelationPagesSliceRelation(t *testing.T) {
+	executor := &fakeExecutor{
+		responses: []fakeQueryResponse{
+			{
+				match: func(querySQL string, _ []any) bool { return strings.Contains(querySQL, `FROM "tenant_RelationMember"`) },
+				rows:  [][]any{{int64(12), "bob", int64(30)}, {int64(11), "alice", int64(25)}},
+			},
+		},
+	}
+	ormImpl, groupModel, memberFilter := newQueryRelationTestOrm(t, executor)
+	if err := memberFilter.Above("age", 18); err != nil {
+		t.Fatalf("filter.Above(age) failed: %v", err)
+	}
+	memberFilter.Sort("age", false)
+	memberFilter.Pagination(1, 2)
+
+	members, err := ormImpl.QueryRelation(groupModel, "members", memberFilter)
+	if err != nil {
+		t.Fatalf("QueryRelation(members) failed: %v", err)
+	}
+	if len(members) != 2 || members[0].Interface(true).(*relationMember).Name != "bob" {
+		t.Fatalf("unexpected relation members: %#v", members)
+	}
+	if len(executor.execCalls) != 1 || !containsSQLCall(executor.execCalls, "query", `FROM "tenant_RelationMember" WHERE "id" IN (SELECT "right" FROM "tenant_RelationGroupMembers4RelationMember" WHERE "left"= $1) AND "age" > $2 ORDER BY "age" DESC LIMIT $3 OFFSET $4`, nil) {
+		t.Fatalf("unexpected query relation calls: %#v", executor.execCalls)
+	}
+
+	if _, err = ormImpl.QueryRelation(groupModel, "name", memberFilter); err == nil || err.Code != cd.IllegalParam {
+		t.Fatalf("basic field should be rejected, got %v", err)
+	}
+	groupFilter, _ := ormImpl.modelProvider.GetModelFilter(groupModel)
+	if _, err = ormImpl.QueryRelation(groupModel, "members", groupFilter); err == nil || err.Code != cd.IllegalParam {
+		t.Fatalf("filter of another model should be rejected, got %v", err)
+	}
+}
+
+func TestCountRelation(t *testing.T) {
+	executor := &fakeExecutor{
+		responses: []fakeQueryResponse{
+			{
+				match: func(querySQL string, _ []any) bool { return strings.HasPrefix(querySQL, "SELECT COUNT(*)") },
+				rows:  [][]any{{sql.NullInt64{Int64: 3, Valid: true}}},
+			},
+		},
+	}
+	ormImpl, groupModel, memberFilter := newQueryRelationTestOrm(t, executor)
+	memberFilter.Pagination(1, 2)
+
+	count, err := ormImpl.CountRelation(groupModel, "members", memberFilter)
+	if err != nil || count != 3 {
+		t.Fatalf("CountRelation(members) failed, count:%d, err:%v", count, err)
+	}
+	if !containsSQLCall(executor.execCalls, "query", `SELECT COUNT(*) FROM "tenant_RelationMember" WHERE "id" IN (SELECT "right" FROM "tenant_RelationGroupMembers4RelationMember" WHERE "left"= $1)`, []any{1}) {
+		t.Fatalf("unexpected count relation calls: %#v", executor.execCalls)
+	}
+}
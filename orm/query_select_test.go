@@ -0,0 +1,52 @@
+package orm
+
+import (
+	"strings"
+	"testing"
+
+	cd "github.com/muidea/magicCommon/def"
+)
+
+func TestBatchQuerySelectFields(t *testing.T) {
+	executor := &fakeExecutor{
+		responses: []fakeQueryResponse{
+			{
+				match: func(querySQL string, _ []any) bool { return strings.Contains(querySQL, `FROM "tenant_RelationGroup"`) },
+				rows:  [][]any{{int64(1), "admin"}, {int64(2), "guest"}},
+			},
+		},
+	}
+	ormImpl, groupModel, _ := newQueryRelationTestOrm(t, executor)
+	groupFilter, err := ormImpl.modelProvider.GetModelFilter(groupModel)
+	if err != nil {
+		t.Fatalf("GetModelFilter(relationGroup) failed: %v", err)
+	}
+	if err = groupFilter.Select("name"); err != nil {
+		t.Fatalf("filter.Select(name) failed: %v", err)
+	}
+
+	groups, err := ormImpl.BatchQuery(groupFilter)
+	if err != nil {
+		t.Fatalf("BatchQuery failed: %v", err)
+	}
+	if len(groups) != 2 {
+		t.Fatalf("unexpected groups: %#v", groups)
+	}
+	groupVal := groups[1].Interface(true).(*relationGroup)
+	if groupVal.ID != 2 || groupVal.Name != "guest" || groupVal.Members != nil {
+		t.Fatalf("unexpected selected group: %#v", groupVal)
+	}
+	if len(executor.execCalls) != 1 || !containsSQLCall(executor.execCalls, "query", `SELECT "id","name" FROM "tenant_RelationGroup"`, nil) {
+		t.Fatalf("unselected relation should not be queried: %#v", executor.execCalls)
+	}
+
+	if err = groupFilter.Select("members.name"); err == nil || err.Code != cd.IllegalParam {
+		t.Fatalf("relation path should be rejected, got %v", err)
+	}
+	if err = groupFilter.Select("unknown"); err != nil {
+		t.Fatalf("filter.Select(unknown) failed: %v", err)
+	}
+	if _, err = ormImpl.BatchQuery(groupFilter); err == nil || err.Code != cd.IllegalParam {
+		t.Fatalf("unknown select field should be rejected, got %v", err)
+	}
+}
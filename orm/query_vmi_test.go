@@ -8,8 +8,10 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	cd "github.com/muidea/magicCommon/def"
+	"github.com/muidea/magicOrm/database"
 	"github.com/muidea/magicOrm/database/codec"
 	"github.com/muidea/magicOrm/database/mysql"
 	"github.com/muidea/magicOrm/models"
@@ -18,8 +20,11 @@ import (
 )
 
 type fakeQueryResponse struct {
-	match func(sql string, args []any) bool
-	rows  [][]any
+	match   func(sql string, args []any) bool
+	rows    [][]any
+	columns []string
+	// rowsFunc 非空时按查询参数动态生成结果行，优先于rows
+	rowsFunc func(args []any) [][]any
 }
 
 type fakeExecCall struct {
@@ -40,10 +45,34 @@ type fakeExecutor struct {
 	beginCalls    int
 	commitCalls   int
 	rollbackCalls int
+
+	statementTimeout  time.Duration
+	statementTimeouts []time.Duration
+
+	// executeContext 模拟executor执行SQL的context，已取消时Query/Execute返回错误
+	executeContext context.Context
+
+	schema string
+
+	rowsAffected int64
+
+	// onExecute 非空时在每次Execute后调用，用于模拟语句对数据的修改
+	onExecute func(sql string, args []any)
 }
 
 func (s *fakeExecutor) Release() {}
 
+func (s *fakeExecutor) SetStatementTimeout(timeout time.Duration) {
+	s.statementTimeout = timeout
+	s.statementTimeouts = append(s.statementTimeouts, timeout)
+}
+
+func (s *fakeExecutor) StatementTimeout() time.Duration { return s.statementTimeout }
+
+func (s *fakeExecutor) SetContext(ctx context.Context) { s.executeContext = ctx }
+
+func (s *fakeExecutor) Context() context.Context { return s.executeContext }
+
 func (s *fakeExecutor) BeginTransaction() *cd.Error {
 	s.beginCalls++
 	return nil
@@ -61,11 +90,17 @@ func (s *fakeExecutor) RollbackTransaction() *cd.Error {
 
 func (s *fakeExecutor) Query(sql string, _ bool, args ...any) ([]string, *cd.Error) {
 	s.execCalls = append(s.execCalls, fakeExecCall{kind: "query", sql: sql, args: append([]any(nil), args...)})
+	if s.executeContext != nil && s.executeContext.Err() != nil {
+		return nil, database.NewExecuteError(s.executeContext, s.executeContext.Err())
+	}
 	for _, response := range s.responses {
 		if response.match(sql, args) {
 			s.currentRows = response.rows
+			if response.rowsFunc != nil {
+				s.currentRows = response.rowsFunc(args)
+			}
 			s.index = -1
-			return nil, nil
+			return response.columns, nil
 		}
 	}
 
@@ -128,7 +163,13 @@ func (s *fakeExecutor) GetField(value ...any) *cd.Error {
 
 func (s *fakeExecutor) Execute(sql string, args ...any) (int64, *cd.Error) {
 	s.execCalls = append(s.execCalls, fakeExecCall{kind: "exec", sql: sql, args: append([]any(nil), args...)})
-	return 0, nil
+	if s.executeContext != nil && s.executeContext.Err() != nil {
+		return 0, database.NewExecuteError(s.executeContext, s.executeContext.Err())
+	}
+	if s.onExecute != nil {
+		s.onExecute(sql, args)
+	}
+	return s.rowsAffected, nil
 }
 
 func (s *fakeExecutor) ExecuteInsert(sql string, pkValOut any, args ...any) *cd.Error {
@@ -165,6 +206,11 @@ func (s *fakeExecutor) ExecuteInsert(sql string, pkValOut any, args ...any) *cd.
 
 func (s *fakeExecutor) CheckTableExist(string) (bool, *cd.Error) { return false, nil }
 
+func (s *fakeExecutor) SwitchSchema(schema string) *cd.Error {
+	s.schema = schema
+	return nil
+}
+
 func loadVMIObjectForORMTest(t *testing.T, relativePath string) *remote.Object {
 	t.Helper()
 
@@ -0,0 +1,198 @@
+package orm
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	cd "github.com/muidea/magicCommon/def"
+
+	"log/slog"
+
+	"github.com/muidea/magicOrm/database"
+	"github.com/muidea/magicOrm/database/codec"
+	"github.com/muidea/magicOrm/metrics"
+	"github.com/muidea/magicOrm/models"
+	"github.com/muidea/magicOrm/provider"
+)
+
+type RawQueryRunner struct {
+	baseRunner
+}
+
+func NewRawQueryRunner(
+	ctx context.Context,
+	vModel models.Model,
+	executor database.Executor,
+	provider provider.Provider,
+	modelCodec codec.Codec) *RawQueryRunner {
+	return &RawQueryRunner{
+		baseRunner: newBaseRunner(ctx, vModel, executor, provider, modelCodec, true, 0),
+	}
+}
+
+// rawColumnFields 按列名匹配vModel的基础字段，未匹配的列对应nil
+func rawColumnFields(vModel models.Model, columns []string) (ret []models.Field) {
+	ret = make([]models.Field, len(columns))
+	for idx, column := range columns {
+		field := vModel.GetField(column)
+		if field == nil || !models.IsBasicField(field) {
+			continue
+		}
+
+		ret[idx] = field
+	}
+	return
+}
+
+func (s *RawQueryRunner) Query(querySQL string, args []any) (ret []models.Model, err *cd.Error) {
+	if err = s.checkContext(); err != nil {
+		return
+	}
+
+	columns, queryErr := s.executor.Query(querySQL, true, args...)
+	if queryErr != nil {
+		err = queryErr
+		slog.Error("RawQueryRunner Query failed", "error", err.Error())
+		return
+	}
+	defer s.executor.Finish()
+
+	columnFields := rawColumnFields(s.vModel, columns)
+	rowValues := make([]any, len(columns))
+	referenceVal := make([]any, len(columns))
+	for idx := range rowValues {
+		referenceVal[idx] = &rowValues[idx]
+	}
+
+	queryList := resultItemsList{}
+	for s.executor.Next() {
+		err = s.executor.GetField(referenceVal...)
+		if err != nil {
+			slog.Error("RawQueryRunner GetField failed", "error", err.Error())
+			return
+		}
+
+		queryList = append(queryList, append(resultItems{}, rowValues...))
+	}
+
+	for _, rowVal := range queryList {
+		qModel, assignErr := s.assignRow(columns, columnFields, rowVal)
+		if assignErr != nil {
+			err = assignErr
+			return
+		}
+		if err = invokeHooks(s.context, qModel, AfterQuery); err != nil {
+			return
+		}
+
+		ret = append(ret, qModel)
+	}
+	return
+}
+
+// assignRow 以vModel为模板构造模型，只保留结果中出现的列，NULL列保持未赋值
+func (s *RawQueryRunner) assignRow(columns []string, columnFields []models.Field, rowVal resultItems) (ret models.Model, err *cd.Error) {
+	qModel := s.vModel.Copy(models.OriginView)
+	for _, field := range qModel.GetFields() {
+		field.Reset()
+	}
+
+	for idx, field := range columnFields {
+		if field == nil || rowVal[idx] == nil {
+			continue
+		}
+
+		fVal, fErr := s.modelCodec.ExtractBasicFieldValue(field, rowVal[idx])
+		if fErr != nil {
+			err = fErr
+			slog.Error("RawQueryRunner ExtractBasicFieldValue failed", "column", columns[idx], "error", err.Error())
+			return
+		}
+
+		if err = qModel.SetFieldValue(field.GetName(), fVal); err != nil {
+			slog.Error("RawQueryRunner SetFieldValue failed", "column", columns[idx], "error", err.Error())
+			return
+		}
+	}
+
+	ret = qModel
+	return
+}
+
+// RawQuery 执行原生查询SQL，结果列按列名映射到vModel的同名基础字段，返回vModel类型的模型列表
+//
+// 用于窗口函数、CTE等无法通过Filter表达的查询；vModel只作为类型模板，未出现在结果中的字段保持未赋值，
+// 与基础字段不同名的列被忽略，关系字段不会加载。SQL与参数原样交给数据库执行，占位符使用当前数据库的语法，
+// 在Orm的当前事务中执行，每个模型调用AfterQuery钩子。
+func (s *impl) RawQuery(vModel models.Model, querySQL string, args ...any) (ret []models.Model, err *cd.Error) {
+	if len(s.interceptors) > 0 {
+		return s.interceptRawQuery(vModel, querySQL, args)
+	}
+
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		if ormMetricCollector != nil {
+			ormMetricCollector.RecordOperation(string(metrics.OperationRaw), vModel, duration, cd.ToStdError(err))
+		}
+	}()
+
+	if err = s.CheckContext(); err != nil {
+		return
+	}
+
+	restoreTimeout := s.applyCallTimeout()
+	defer restoreTimeout()
+
+	if vModel == nil {
+		err = cd.NewError(cd.IllegalParam, "illegal model value")
+		return
+	}
+	if strings.TrimSpace(querySQL) == "" {
+		err = cd.NewError(cd.IllegalParam, "illegal raw sql")
+		return
+	}
+
+	rawRunner := NewRawQueryRunner(s.context, vModel, s.executor, s.modelProvider, s.modelCodec)
+	ret, err = rawRunner.Query(querySQL, args)
+	if err != nil {
+		slog.Error("RawQuery RawQueryRunner.Query failed", "pkgKey", vModel.GetPkgKey(), "error", err.Error())
+	}
+	return
+}
+
+// RawExec 执行原生SQL，返回受影响的行数，在Orm的当前事务中执行，不触发钩子与验证
+func (s *impl) RawExec(execSQL string, args ...any) (ret int64, err *cd.Error) {
+	if len(s.interceptors) > 0 {
+		return s.interceptRawExec(execSQL, args)
+	}
+
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		if ormMetricCollector != nil {
+			ormMetricCollector.RecordOperation(string(metrics.OperationRaw), nil, duration, cd.ToStdError(err))
+		}
+	}()
+
+	if err = s.CheckContext(); err != nil {
+		return
+	}
+
+	restoreTimeout := s.applyCallTimeout()
+	defer restoreTimeout()
+
+	if strings.TrimSpace(execSQL) == "" {
+		err = cd.NewError(cd.IllegalParam, "illegal raw sql")
+		return
+	}
+
+	ret, err = s.executor.Execute(execSQL, args...)
+	if err != nil {
+		slog.Error("RawExec Execute failed", "error", err.Error())
+	}
+	return
+}
@@ -0,0 +1,62 @@
+package orm
+
+import (
+	"strings"
+	"testing"
+
+	cd "github.com/muidea/magicCommon/def"
+)
+
+func TestRawQueryMapsColumnsByName(t *testing.T) {
+	executor := &fakeExecutor{
+		responses: []fakeQueryResponse{
+			{
+				match:   func(querySQL string, _ []any) bool { return strings.HasPrefix(querySQL, "WITH ranked") },
+				columns: []string{"id", "name", "rank"},
+				rows:    [][]any{{int64(2), "bob", int64(1)}, {int64(1), nil, int64(2)}},
+			},
+		},
+	}
+	ormImpl, userModel := newUpdateFieldsTestOrm(t, executor, &fieldsUser{ID: 9, Name: "template", Email: "template@example.com"})
+
+	rawSQL := `WITH ranked AS (SELECT "id","name",ROW_NUMBER() OVER (ORDER BY "id" DESC) AS "rank" FROM "tenant_FieldsUser" WHERE "email" LIKE $1) SELECT * FROM ranked`
+	userList, err := ormImpl.RawQuery(userModel, rawSQL, "%@example.com")
+	if err != nil {
+		t.Fatalf("RawQuery failed: %v", err)
+	}
+	if len(userList) != 2 {
+		t.Fatalf("unexpected raw query result size: %d", len(userList))
+	}
+
+	first := userList[0].Interface(true).(*fieldsUser)
+	second := userList[1].Interface(true).(*fieldsUser)
+	if first.ID != 2 || first.Name != "bob" || first.Email != "" || second.ID != 1 || second.Name != "" {
+		t.Fatalf("unexpected raw query models: %#v, %#v", first, second)
+	}
+	if !containsSQLCall(executor.execCalls, "query", rawSQL, []any{"%@example.com"}) {
+		t.Fatalf("raw sql should be executed as is, got %#v", executor.execCalls)
+	}
+
+	if _, err = ormImpl.RawQuery(userModel, " "); err == nil || err.Code != cd.IllegalParam {
+		t.Fatalf("empty raw sql should be rejected, got %v", err)
+	}
+}
+
+func TestRawExecInTransaction(t *testing.T) {
+	executor := &fakeExecutor{rowsAffected: 3}
+	ormImpl, _ := newUpdateFieldsTestOrm(t, executor, &fieldsUser{})
+
+	if err := ormImpl.BeginTransaction(); err != nil {
+		t.Fatalf("BeginTransaction failed: %v", err)
+	}
+	affected, err := ormImpl.RawExec(`UPDATE "tenant_FieldsUser" SET "name" = UPPER("name") WHERE "id" > $1`, 10)
+	if err != nil || affected != 3 {
+		t.Fatalf("RawExec failed, affected:%d, err:%v", affected, err)
+	}
+	if err = ormImpl.CommitTransaction(); err != nil {
+		t.Fatalf("CommitTransaction failed: %v", err)
+	}
+	if executor.beginCalls != 1 || executor.commitCalls != 1 || !containsSQLCall(executor.execCalls, "exec", `SET "name" = UPPER("name")`, []any{10}) {
+		t.Fatalf("unexpected raw exec calls: %#v", executor.execCalls)
+	}
+}
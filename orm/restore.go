@@ -0,0 +1,107 @@
+package orm
+
+import (
+	"fmt"
+	"time"
+
+	cd "github.com/muidea/magicCommon/def"
+
+	"log/slog"
+
+	"github.com/muidea/magicOrm/metrics"
+	"github.com/muidea/magicOrm/models"
+)
+
+// Restore 清空删除时间，前后调用 BeforeUpdate/AfterUpdate 钩子，返回恢复后存储的行
+func (s *UpdateRunner) Restore() (ret models.Model, err *cd.Error) {
+	if err = s.checkContext(); err != nil {
+		return
+	}
+
+	err = invokeHooks(s.context, s.vModel, BeforeUpdate)
+	if err != nil {
+		return
+	}
+
+	restoreResult, restoreErr := s.sqlBuilder.BuildRestore(s.vModel)
+	if restoreErr != nil {
+		err = restoreErr
+		slog.Error("UpdateRunner Restore BuildRestore failed", "pkgKey", s.vModel.GetPkgKey(), "error", err.Error())
+		return
+	}
+
+	rowsAffected, execErr := s.executor.Execute(restoreResult.SQL(), restoreResult.Args()...)
+	if execErr != nil {
+		err = execErr
+		slog.Error("UpdateRunner Restore Execute failed", "pkgKey", s.vModel.GetPkgKey(), "error", err.Error())
+		return
+	}
+	if rowsAffected == 0 {
+		err = cd.NewError(cd.NotFound, fmt.Sprintf("no soft deleted record matching the model, model pkgKey: %s", s.vModel.GetPkgKey()))
+		return
+	}
+
+	models.GetSoftDeleteField(s.vModel).Reset()
+	err = invokeHooks(s.context, s.vModel, AfterUpdate)
+	if err != nil {
+		return
+	}
+
+	ret, err = s.queryStoredResponseModel(s.vModel)
+	if err == nil {
+		return
+	}
+
+	slog.Warn("UpdateRunner Restore query stored response failed, fallback to projected working model", "pkgKey", s.vModel.GetPkgKey(), "error", err.Error())
+	ret, err = projectWriteResponseModel(s.vModel, s.modelProvider)
+	return
+}
+
+// Restore 在事务中恢复按主键指定的已软删除的行，清空删除时间并返回恢复后存储的行
+//
+// 模型未声明软删除字段时返回 IllegalParam，行不存在或未被删除时返回 NotFound。
+func (s *impl) Restore(vModel models.Model) (ret models.Model, err *cd.Error) {
+	if len(s.interceptors) > 0 {
+		return s.interceptModel(metrics.OperationUpdate, "Restore", vModel, (*impl).Restore)
+	}
+
+	startTime := time.Now()
+
+	defer func() {
+		duration := time.Since(startTime)
+		if ormMetricCollector != nil {
+			ormMetricCollector.RecordOperation(string(metrics.OperationUpdate), vModel, duration, cd.ToStdError(err))
+		}
+	}()
+
+	if err = s.CheckContext(); err != nil {
+		return
+	}
+
+	restoreTimeout := s.applyCallTimeout()
+	defer restoreTimeout()
+
+	if vModel == nil {
+		err = cd.NewError(cd.IllegalParam, "illegal model value")
+		return
+	}
+	if !models.IsAssignedField(vModel.GetPrimaryField()) {
+		err = cd.NewError(cd.IllegalParam, fmt.Sprintf("restore requires primary key value, model:%s", vModel.GetPkgKey()))
+		return
+	}
+
+	err = s.executor.BeginTransaction()
+	if err != nil {
+		return
+	}
+	defer func() {
+		s.finalTransaction(err)
+	}()
+
+	updateRunner := NewUpdateRunner(s.context, vModel, s.executor, s.modelProvider, s.modelCodec)
+	ret, err = updateRunner.Restore()
+	if err != nil {
+		slog.Error("Restore UpdateRunner.Restore failed", "pkgKey", vModel.GetPkgKey(), "error", err.Error())
+	}
+	return
+}
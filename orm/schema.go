@@ -0,0 +1,37 @@
+package orm
+
+import (
+	"context"
+
+	cd "github.com/muidea/magicCommon/def"
+
+	"github.com/muidea/magicOrm/provider"
+)
+
+type schemaContextKey struct{}
+
+// ContextWithSchema 返回携带schema的context，GetOrm获取的Orm会在该schema下执行
+//
+// PostgreSQL 对应 search_path，MySQL 对应当前数据库；schema为空表示使用连接配置的schema
+func ContextWithSchema(ctx context.Context, schema string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return context.WithValue(ctx, schemaContextKey{}, schema)
+}
+
+// SchemaFromContext 返回ContextWithSchema设置的schema
+func SchemaFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+
+	schema, _ := ctx.Value(schemaContextKey{}).(string)
+	return schema
+}
+
+// GetOrmForSchema 从连接池获取在指定schema下执行的Orm，多个schema共享同一个连接池
+func GetOrmForSchema(ctx context.Context, provider provider.Provider, prefix, schema string) (Orm, *cd.Error) {
+	return GetOrm(ContextWithSchema(ctx, schema), provider, prefix)
+}
@@ -0,0 +1,237 @@
+package orm
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	cd "github.com/muidea/magicCommon/def"
+
+	"github.com/muidea/magicOrm/database/codec"
+	"github.com/muidea/magicOrm/models"
+	"github.com/muidea/magicOrm/provider"
+	"github.com/muidea/magicOrm/provider/remote"
+)
+
+func testSoftDeleteNoteObject() *remote.Object {
+	return &remote.Object{
+		Name:    "note",
+		PkgPath: "/bench",
+		Fields: []*remote.Field{
+			{
+				Name: "id",
+				Type: &remote.TypeImpl{Name: "int64", Value: models.TypeBigIntegerValue},
+				Spec: &remote.SpecImpl{FieldName: "id", PrimaryKey: true, ViewDeclare: []models.ViewDeclare{models.DetailView, models.LiteView}},
+			},
+			{
+				Name: "title",
+				Type: &remote.TypeImpl{Name: "string", Value: models.TypeStringValue},
+				Spec: &remote.SpecImpl{FieldName: "title", ViewDeclare: []models.ViewDeclare{models.DetailView, models.LiteView}},
+			},
+			{
+				Name: "status",
+				Type: &remote.TypeImpl{Name: "status", PkgPath: "/bench", Value: models.TypeStructValue, IsPtr: true},
+				Spec: &remote.SpecImpl{FieldName: "status", ViewDeclare: []models.ViewDeclare{models.DetailView}},
+			},
+			{
+				Name: "deletedAt",
+				Type: &remote.TypeImpl{Name: "time.Time", Value: models.TypeDateTimeValue, IsPtr: true},
+				Spec: &remote.SpecImpl{FieldName: "deletedAt", ValueDeclare: models.SoftDelete, ViewDeclare: []models.ViewDeclare{models.DetailView}},
+			},
+		},
+	}
+}
+
+func newSoftDeleteTestOrm(t *testing.T, executor *fakeExecutor) (*impl, provider.Provider, models.Model) {
+	t.Helper()
+
+	remoteProvider := provider.NewRemoteProvider("tenant", nil)
+	registerMinimalRelationModels(t, remoteProvider)
+	if _, err := remoteProvider.RegisterModel(testSoftDeleteNoteObject()); err != nil {
+		t.Fatalf("RegisterModel(note) failed: %v", err)
+	}
+
+	noteModel, err := remoteProvider.GetEntityModel(&remote.ObjectValue{
+		Name:    "note",
+		PkgPath: "/bench",
+		Fields:  []*remote.FieldValue{{Name: "id", Value: int64(7)}},
+	}, true)
+	if err != nil {
+		t.Fatalf("GetEntityModel(note) failed: %v", err)
+	}
+
+	ormImpl := &impl{
+		context:       context.Background(),
+		executor:      executor,
+		modelProvider: remoteProvider,
+		modelCodec:    codec.New(remoteProvider, "tenant"),
+	}
+	return ormImpl, remoteProvider, noteModel
+}
+
+func TestSoftDeleteKeepsRelations(t *testing.T) {
+	executor := &fakeExecutor{rowsAffected: 1}
+	ormImpl, _, noteModel := newSoftDeleteTestOrm(t, executor)
+
+	if _, err := ormImpl.Delete(noteModel); err != nil {
+		t.Fatalf("impl.Delete(note) failed: %v", err)
+	}
+	if len(executor.execCalls) != 1 {
+		t.Fatalf("soft delete should issue a single statement, got %#v", executor.execCalls)
+	}
+	call := executor.execCalls[0]
+	if call.kind != "exec" || call.sql != `UPDATE "tenant_Note" SET "deletedAt" = $1 WHERE "id" = $2 AND "deletedAt" IS NULL` {
+		t.Fatalf("unexpected soft delete call: %#v", call)
+	}
+	if !models.IsAssignedField(noteModel.GetField("deletedAt")) {
+		t.Fatal("soft delete should assign deletedAt")
+	}
+}
+
+func TestHardDeleteRemovesRowAndRelations(t *testing.T) {
+	executor := &fakeExecutor{rowsAffected: 1}
+	ormImpl, _, noteModel := newSoftDeleteTestOrm(t, executor)
+
+	if _, err := ormImpl.HardDelete(noteModel); err != nil {
+		t.Fatalf("impl.HardDelete(note) failed: %v", err)
+	}
+	if !containsSQLCall(executor.execCalls, "exec", `DELETE FROM "tenant_Note" WHERE "id" = $1`, nil) {
+		t.Fatalf("hard delete should delete the host row, got %#v", executor.execCalls)
+	}
+	if !containsSQLCall(executor.execCalls, "exec", `DELETE FROM "tenant_NoteStatus3Status"`, nil) {
+		t.Fatalf("hard delete should delete the relation rows, got %#v", executor.execCalls)
+	}
+}
+
+func TestRestore(t *testing.T) {
+	executor := &fakeExecutor{}
+	ormImpl, remoteProvider, noteModel := newSoftDeleteTestOrm(t, executor)
+
+	_, err := ormImpl.Restore(noteModel)
+	if err == nil || err.Code != cd.NotFound {
+		t.Fatalf("restore without matching deleted row should be NotFound, got %v", err)
+	}
+
+	if executor.beginCalls != 1 || executor.rollbackCalls != 1 {
+		t.Fatalf("failed restore should roll back, begin:%d rollback:%d", executor.beginCalls, executor.rollbackCalls)
+	}
+
+	hookEvents := []string{}
+	RegisterHook(noteModel.GetPkgKey(), BeforeUpdate, func(context.Context, models.Model) error {
+		hookEvents = append(hookEvents, "before")
+		return nil
+	})
+	RegisterHook(noteModel.GetPkgKey(), AfterUpdate, func(context.Context, models.Model) error {
+		hookEvents = append(hookEvents, "after")
+		return nil
+	})
+	t.Cleanup(func() { UnregisterHooks(noteModel.GetPkgKey()) })
+
+	executor.rowsAffected = 1
+	executor.responses = append(executor.responses,
+		fakeQueryResponse{
+			match: func(querySQL string, _ []any) bool {
+				return strings.HasPrefix(querySQL, `SELECT "id","title","deletedAt" FROM "tenant_Note"`)
+			},
+			rows: [][]any{{int64(7), "stored title", nil}},
+		},
+		fakeQueryResponse{
+			match: func(querySQL string, _ []any) bool { return strings.Contains(querySQL, `"tenant_NoteStatus`) },
+		},
+	)
+	if err = noteModel.SetFieldValue("deletedAt", "2024-05-01 08:00:00"); err != nil {
+		t.Fatalf("SetFieldValue(deletedAt) failed: %v", err)
+	}
+	restoredModel, err := ormImpl.Restore(noteModel)
+	if err != nil {
+		t.Fatalf("impl.Restore(note) failed: %v", err)
+	}
+	if !containsSQLCall(executor.execCalls, "exec", `UPDATE "tenant_Note" SET "deletedAt" = NULL WHERE "id" = $1 AND "deletedAt" IS NOT NULL`, []any{int64(7)}) {
+		t.Fatalf("unexpected restore calls: %#v", executor.execCalls)
+	}
+	if models.IsAssignedField(noteModel.GetField("deletedAt")) {
+		t.Fatal("restore should reset deletedAt")
+	}
+	if restoredModel.GetField("title").GetValue().Get() != "stored title" {
+		t.Fatalf("restore should return the stored row, got %v", restoredModel.GetField("title").GetValue().Get())
+	}
+	if strings.Join(hookEvents, ",") != "before,after" {
+		t.Fatalf("restore should run update hooks, got %v", hookEvents)
+	}
+	if executor.beginCalls != 2 || executor.commitCalls != 1 {
+		t.Fatalf("restore should run in a transaction, begin:%d commit:%d", executor.beginCalls, executor.commitCalls)
+	}
+
+	emptyModel, emptyErr := remoteProvider.GetEntityModel(&remote.ObjectValue{Name: "note", PkgPath: "/bench"}, true)
+	if emptyErr != nil {
+		t.Fatalf("GetEntityModel(note) failed: %v", emptyErr)
+	}
+	if _, err = ormImpl.Restore(emptyModel); err == nil || err.Code != cd.IllegalParam {
+		t.Fatalf("restore without primary key should be IllegalParam, got %v", err)
+	}
+}
+
+func TestSoftDeleteByFilter(t *testing.T) {
+	executor := &fakeExecutor{rowsAffected: 3}
+	ormImpl, remoteProvider, noteModel := newSoftDeleteTestOrm(t, executor)
+
+	filter, err := remoteProvider.GetModelFilter(noteModel)
+	if err != nil {
+		t.Fatalf("GetModelFilter(note) failed: %v", err)
+	}
+	if err = filter.Equal("title", "draft"); err != nil {
+		t.Fatalf("filter.Equal(title) failed: %v", err)
+	}
+
+	affected, err := ormImpl.DeleteByFilter(filter)
+	if err != nil {
+		t.Fatalf("impl.DeleteByFilter(note) failed: %v", err)
+	}
+	if affected != 3 || len(executor.execCalls) != 1 {
+		t.Fatalf("unexpected soft delete by filter result: %d %#v", affected, executor.execCalls)
+	}
+	if executor.execCalls[0].sql != `UPDATE "tenant_Note" SET "deletedAt" = $1 WHERE "title" = $2 AND "deletedAt" IS NULL` {
+		t.Fatalf("unexpected soft delete by filter sql: %s", executor.execCalls[0].sql)
+	}
+
+	executor.execCalls = nil
+	filter.Deleted(models.OnlyDeleted)
+	if _, err = ormImpl.HardDeleteByFilter(filter); err != nil {
+		t.Fatalf("impl.HardDeleteByFilter(note) failed: %v", err)
+	}
+	lastCall := executor.execCalls[len(executor.execCalls)-1]
+	if lastCall.sql != `DELETE FROM "tenant_Note" WHERE "title" = $1 AND "deletedAt" IS NOT NULL` {
+		t.Fatalf("unexpected hard delete by filter sql: %s", lastCall.sql)
+	}
+	if !strings.Contains(executor.execCalls[0].sql, `"tenant_NoteStatus3Status"`) {
+		t.Fatalf("hard delete by filter should clean relations first, got %#v", executor.execCalls)
+	}
+}
+
+func TestSoftDeleteExcludedFromCount(t *testing.T) {
+	executor := &fakeExecutor{
+		responses: []fakeQueryResponse{
+			{
+				match: func(querySQL string, _ []any) bool { return strings.HasPrefix(querySQL, "SELECT COUNT(*)") },
+				rows:  [][]any{{sql.NullInt64{Int64: 4, Valid: true}}},
+			},
+		},
+	}
+	ormImpl, remoteProvider, noteModel := newSoftDeleteTestOrm(t, executor)
+
+	filter, err := remoteProvider.GetModelFilter(noteModel)
+	if err != nil {
+		t.Fatalf("GetModelFilter(note) failed: %v", err)
+	}
+	count, err := ormImpl.Count(filter)
+	if err != nil {
+		t.Fatalf("impl.Count(note) failed: %v", err)
+	}
+	if count != 4 {
+		t.Fatalf("expected count 4, got %d", count)
+	}
+	if !containsSQLCall(executor.execCalls, "query", `SELECT COUNT(*) FROM "tenant_Note" WHERE "deletedAt" IS NULL`, nil) {
+		t.Fatalf("count should exclude soft deleted rows, got %#v", executor.execCalls)
+	}
+}
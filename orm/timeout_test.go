@@ -0,0 +1,63 @@
+package orm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cd "github.com/muidea/magicCommon/def"
+	"github.com/muidea/magicOrm/database/codec"
+	"github.com/muidea/magicOrm/provider"
+)
+
+func TestWithTimeoutAppliesPerCallStatementTimeout(t *testing.T) {
+	remoteProvider := provider.NewRemoteProvider("tenant", nil)
+	registerVMIQueryModels(t, remoteProvider)
+
+	productModel := buildProductBasicUpdateModel(t, remoteProvider)
+	executor := &fakeExecutor{}
+	ormImpl := &impl{
+		context:       context.Background(),
+		executor:      executor,
+		modelProvider: remoteProvider,
+		modelCodec:    codec.New(remoteProvider, "tenant"),
+	}
+
+	ormImpl.SetStatementTimeout(5 * time.Second)
+	if _, err := ormImpl.WithTimeout(time.Second).Update(productModel); err != nil {
+		t.Fatalf("WithTimeout(...).Update failed: %v", err)
+	}
+
+	expectTimeouts := []time.Duration{5 * time.Second, time.Second, 5 * time.Second}
+	if len(executor.statementTimeouts) != len(expectTimeouts) {
+		t.Fatalf("unexpected statement timeouts: %v", executor.statementTimeouts)
+	}
+	for idx, val := range expectTimeouts {
+		if executor.statementTimeouts[idx] != val {
+			t.Fatalf("unexpected statement timeouts: %v", executor.statementTimeouts)
+		}
+	}
+
+	ormImpl.WithTimeout(time.Second).Release()
+	if ormImpl.executor == nil {
+		t.Fatal("Release on WithTimeout Orm must not release the shared executor")
+	}
+}
+
+func TestCheckContextReturnsTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	<-ctx.Done()
+
+	ormImpl := &impl{context: ctx, executor: &fakeExecutor{}}
+	if err := ormImpl.CheckContext(); err == nil || err.Code != cd.Timeout {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+
+	cancelCtx, cancelFunc := context.WithCancel(context.Background())
+	cancelFunc()
+	ormImpl.context = cancelCtx
+	if err := ormImpl.CheckContext(); err == nil || err.Code != cd.Unexpected {
+		t.Fatalf("expected unexpected error for cancelled context, got %v", err)
+	}
+}
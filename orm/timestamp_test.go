@@ -0,0 +1,140 @@
+package orm
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	cd "github.com/muidea/magicCommon/def"
+
+	"github.com/muidea/magicOrm/database/codec"
+	"github.com/muidea/magicOrm/models"
+	"github.com/muidea/magicOrm/provider"
+	"github.com/muidea/magicOrm/provider/remote"
+)
+
+func testTimestampPostObject() *remote.Object {
+	return &remote.Object{
+		Name:    "post",
+		PkgPath: "/bench",
+		Fields: []*remote.Field{
+			{
+				Name: "id",
+				Type: &remote.TypeImpl{Name: "int64", Value: models.TypeBigIntegerValue},
+				Spec: &remote.SpecImpl{FieldName: "id", PrimaryKey: true, ViewDeclare: []models.ViewDeclare{models.DetailView, models.LiteView}},
+			},
+			{
+				Name: "title",
+				Type: &remote.TypeImpl{Name: "string", Value: models.TypeStringValue},
+				Spec: &remote.SpecImpl{FieldName: "title", ViewDeclare: []models.ViewDeclare{models.DetailView, models.LiteView}},
+			},
+			{
+				Name: "createdAt",
+				Type: &remote.TypeImpl{Name: "time.Time", Value: models.TypeDateTimeValue},
+				Spec: &remote.SpecImpl{FieldName: "createdAt", ValueDeclare: models.CreatedAt, ViewDeclare: []models.ViewDeclare{models.DetailView}},
+			},
+			{
+				Name: "updatedAt",
+				Type: &remote.TypeImpl{Name: "time.Time", Value: models.TypeDateTimeValue},
+				Spec: &remote.SpecImpl{FieldName: "updatedAt", ValueDeclare: models.UpdatedAt, ViewDeclare: []models.ViewDeclare{models.DetailView}},
+			},
+		},
+	}
+}
+
+func newTimestampTestOrm(t *testing.T, executor *fakeExecutor, now time.Time, fields ...*remote.FieldValue) (*impl, provider.Provider, models.Model) {
+	t.Helper()
+
+	remoteProvider := provider.NewRemoteProvider("tenant", nil)
+	if _, err := remoteProvider.RegisterModel(testTimestampPostObject()); err != nil {
+		t.Fatalf("RegisterModel(post) failed: %v", err)
+	}
+
+	postModel, err := remoteProvider.GetEntityModel(&remote.ObjectValue{Name: "post", PkgPath: "/bench", Fields: fields}, true)
+	if err != nil {
+		t.Fatalf("GetEntityModel(post) failed: %v", err)
+	}
+
+	ormImpl := &impl{
+		context:       ContextWithClock(context.Background(), func() time.Time { return now }),
+		executor:      executor,
+		modelProvider: remoteProvider,
+		modelCodec:    codec.New(remoteProvider, "tenant"),
+	}
+	return ormImpl, remoteProvider, postModel
+}
+
+func assertFieldTime(t *testing.T, vModel models.Model, fieldName string, want time.Time) {
+	t.Helper()
+
+	fieldVal, ok := vModel.GetField(fieldName).GetValue().Get().(time.Time)
+	if !ok || !fieldVal.Equal(want) {
+		t.Fatalf("unexpected %s value, want %v, got %v", fieldName, want, vModel.GetField(fieldName).GetValue().Get())
+	}
+}
+
+func TestInsertFillsTimestamps(t *testing.T) {
+	now := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	executor := &fakeExecutor{}
+	ormImpl, _, postModel := newTimestampTestOrm(t, executor, now,
+		&remote.FieldValue{Name: "id", Value: int64(7)},
+		&remote.FieldValue{Name: "title", Value: "draft"},
+	)
+
+	if _, err := ormImpl.Insert(postModel); err != nil {
+		t.Fatalf("impl.Insert(post) failed: %v", err)
+	}
+	assertFieldTime(t, postModel, "createdAt", now)
+	assertFieldTime(t, postModel, "updatedAt", now)
+}
+
+func TestUpdateRefreshesUpdatedAt(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	now := createdAt.Add(time.Hour)
+	executor := &fakeExecutor{rowsAffected: 1}
+	ormImpl, _, postModel := newTimestampTestOrm(t, executor, now,
+		&remote.FieldValue{Name: "id", Value: int64(7)},
+		&remote.FieldValue{Name: "title", Value: "edited"},
+	)
+	if err := postModel.SetFieldValue("createdAt", createdAt.Add(time.Minute)); err != nil {
+		t.Fatalf("SetFieldValue(createdAt) failed: %v", err)
+	}
+
+	if _, err := ormImpl.Update(postModel); err != nil {
+		t.Fatalf("impl.Update(post) failed: %v", err)
+	}
+	if !containsSQLCall(executor.execCalls, "exec", `UPDATE "tenant_Post" SET "title" = $1,"updatedAt" = $2 WHERE "id" = $3`, nil) {
+		t.Fatalf("update should refresh updatedAt and keep createdAt, got %#v", executor.execCalls)
+	}
+	assertFieldTime(t, postModel, "updatedAt", now)
+}
+
+func TestUpdateByFilterRefreshesUpdatedAt(t *testing.T) {
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	executor := &fakeExecutor{rowsAffected: 2}
+	ormImpl, remoteProvider, postModel := newTimestampTestOrm(t, executor, now)
+
+	filter, err := remoteProvider.GetModelFilter(postModel)
+	if err != nil {
+		t.Fatalf("GetModelFilter(post) failed: %v", err)
+	}
+	if err = filter.Equal("title", "draft"); err != nil {
+		t.Fatalf("filter.Equal(title) failed: %v", err)
+	}
+
+	if _, err = ormImpl.UpdateByFilter(filter, map[string]any{"title": "published"}); err != nil {
+		t.Fatalf("impl.UpdateByFilter(post) failed: %v", err)
+	}
+	if len(executor.execCalls) != 1 || executor.execCalls[0].sql != `UPDATE "tenant_Post" SET "title" = $1,"updatedAt" = $2 WHERE "title" = $3` {
+		t.Fatalf("unexpected update by filter calls: %#v", executor.execCalls)
+	}
+	if updatedArg, ok := executor.execCalls[0].args[1].(string); !ok || !strings.HasPrefix(updatedArg, "2024-05-01 09:00:00") {
+		t.Fatalf("updatedAt should use the injected clock, got %#v", executor.execCalls[0].args[1])
+	}
+
+	_, err = ormImpl.UpdateByFilter(filter, map[string]any{"createdAt": now})
+	if err == nil || err.Code != cd.IllegalParam {
+		t.Fatalf("expected createdAt assignment to be rejected, got %v", err)
+	}
+}
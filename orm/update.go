@@ -2,6 +2,8 @@ package orm
 
 import (
 	"context"
+	"fmt"
+	"reflect"
 	"time"
 
 	cd "github.com/muidea/magicCommon/def"
@@ -36,6 +38,9 @@ type UpdateRunner struct {
 	InsertRunner
 	DeleteRunner
 
+	// relationSelection UpdateFields 通过点路径限定的包含关系字段，只更新子对象的指定字段
+	relationSelection fieldSelection
+
 	hostUpdateDuration     time.Duration
 	relationUpdateDuration time.Duration
 	relationUpdateCount    int
@@ -74,6 +79,14 @@ func (s *UpdateRunner) updateHost(vModel models.Model) (err *cd.Error) {
 		s.hostUpdateDuration += time.Since(startTime)
 	}()
 
+	if updatedField := models.GetUpdatedAtField(vModel); updatedField != nil {
+		err = updatedField.SetValue(currentDateTime(s.context))
+		if err != nil {
+			slog.Error("UpdateRunner updateHost set updatedAt failed", "field", updatedField.GetName(), "error", err.Error())
+			return
+		}
+	}
+
 	updateResult, updateErr := s.sqlBuilder.BuildUpdate(vModel)
 	if updateErr != nil {
 		err = updateErr
@@ -81,10 +94,59 @@ func (s *UpdateRunner) updateHost(vModel models.Model) (err *cd.Error) {
 		return
 	}
 
-	_, err = s.executor.Execute(updateResult.SQL(), updateResult.Args()...)
-	if err != nil {
+	rowsAffected, execErr := s.executor.Execute(updateResult.SQL(), updateResult.Args()...)
+	if execErr != nil {
+		err = execErr
 		slog.Error("UpdateRunner updateHost Execute failed", "error", err.Error())
+		return
+	}
+
+	versionField := models.GetVersionField(vModel)
+	if versionField == nil {
+		return
+	}
+	if rowsAffected == 0 {
+		err = cd.NewError(cd.VersionConflict, fmt.Sprintf("version conflict, the record has been modified or deleted, model:%s, version:%v", vModel.GetPkgKey(), versionField.GetValue().Get()))
+		return
+	}
+
+	err = increaseVersionValue(versionField)
+	if err != nil {
+		slog.Error("UpdateRunner updateHost increaseVersionValue failed", "field", versionField.GetName(), "error", err.Error())
+	}
+	return
+}
+
+// increaseVersionValue 把模型中的版本值加1并保持原有的数值类型，用于插入时的初始版本与更新成功后的同步
+func increaseVersionValue(versionField models.Field) (err *cd.Error) {
+	curVal := reflect.ValueOf(versionField.GetValue().Get())
+	if !curVal.IsValid() {
+		initVal, initErr := versionField.GetType().Interface(nil)
+		if initErr != nil {
+			err = initErr
+			return
+		}
+		curVal = reflect.ValueOf(initVal.Get())
+	}
+	if !curVal.IsValid() {
+		err = cd.NewError(cd.Unexpected, fmt.Sprintf("illegal version value, field:%s", versionField.GetName()))
+		return
 	}
+
+	nextVal := reflect.New(curVal.Type()).Elem()
+	switch {
+	case curVal.CanInt():
+		nextVal.SetInt(curVal.Int() + 1)
+	case curVal.CanUint():
+		nextVal.SetUint(curVal.Uint() + 1)
+	case curVal.CanFloat():
+		nextVal.SetFloat(curVal.Float() + 1)
+	default:
+		err = cd.NewError(cd.Unexpected, fmt.Sprintf("illegal version value, field:%s", versionField.GetName()))
+		return
+	}
+
+	err = versionField.SetValue(nextVal.Interface())
 	return
 }
 
@@ -97,10 +159,7 @@ func (s *UpdateRunner) updateRelation(vModel models.Model, vField models.Field)
 		}
 	}()
 
-	// 引用关系：单值指针（*T）或切片元素为指针（[]*T）；其余为包含关系
-	isReference := (models.IsSliceField(vField) && vField.GetType().Elem().IsPtrType()) ||
-		(!models.IsSliceField(vField) && models.IsPtrField(vField))
-	if isReference {
+	if isReferenceRelationField(vField) {
 		// 引用关系：只刷新关系（增删链接），不处理实体
 		err = s.updateReferenceRelation(vModel, vField)
 		if err != nil {
@@ -116,6 +175,12 @@ func (s *UpdateRunner) updateRelation(vModel models.Model, vField models.Field)
 	return
 }
 
+// isReferenceRelationField 引用关系：单值指针（*T）或切片元素为指针（[]*T）；其余为包含关系
+func isReferenceRelationField(vField models.Field) bool {
+	return (models.IsSliceField(vField) && vField.GetType().Elem().IsPtrType()) ||
+		(!models.IsSliceField(vField) && models.IsPtrField(vField))
+}
+
 func assignedReadOnlyBasicFields(vModel models.Model) (ret []string) {
 	if vModel == nil {
 		return nil
@@ -208,6 +273,11 @@ func (s *UpdateRunner) Update() (ret models.Model, err *cd.Error) {
 		return
 	}
 
+	err = invokeHooks(s.context, s.vModel, BeforeUpdate)
+	if err != nil {
+		return
+	}
+
 	readOnlyFieldNames := assignedReadOnlyBasicFields(s.vModel)
 	var storedModel models.Model
 	if len(readOnlyFieldNames) > 0 {
@@ -218,7 +288,8 @@ func (s *UpdateRunner) Update() (ret models.Model, err *cd.Error) {
 		}
 	}
 
-	if hasAssignedWritableBasicFields(s.vModel) {
+	// 声明了版本或更新时间字段时，只更新关系也要更新host
+	if hasAssignedWritableBasicFields(s.vModel) || hasAutoUpdateFields(s.vModel) {
 		err = s.updateHost(s.vModel)
 		if err != nil {
 			slog.Error("UpdateRunner Update updateHost failed", "error", err.Error())
@@ -232,6 +303,14 @@ func (s *UpdateRunner) Update() (ret models.Model, err *cd.Error) {
 		if models.IsBasicField(field) || !models.IsAssignedField(field) || isReadOnlyField(field) {
 			continue
 		}
+		if subSelection, ok := s.relationSelection[field.GetName()]; ok {
+			err = s.updateContainRelationFields(field, subSelection)
+			if err != nil {
+				slog.Error("UpdateRunner Update updateContainRelationFields failed", "field", field.GetName(), "error", err.Error())
+				return
+			}
+			continue
+		}
 
 		err = s.updateRelation(s.vModel, field)
 		if err != nil {
@@ -244,6 +323,11 @@ func (s *UpdateRunner) Update() (ret models.Model, err *cd.Error) {
 		restoreReadOnlyBasicFields(s.vModel, storedModel, readOnlyFieldNames)
 	}
 
+	err = invokeHooks(s.context, s.vModel, AfterUpdate)
+	if err != nil {
+		return
+	}
+
 	ret, err = s.queryStoredResponseModel(s.vModel)
 	if err == nil {
 		return
@@ -263,16 +347,34 @@ func hasAssignedWritableBasicFields(vModel models.Model) bool {
 		if !models.IsBasicField(field) || models.IsPrimaryField(field) || !models.IsAssignedField(field) || isReadOnlyField(field) {
 			continue
 		}
+		if isAutoUpdateField(field) {
+			continue
+		}
 		return true
 	}
 
 	return false
 }
 
+// isAutoUpdateField 版本、创建时间与更新时间字段由Orm维护，不接受调用方的赋值
+func isAutoUpdateField(field models.Field) bool {
+	valueDeclare := field.GetSpec().GetValueDeclare()
+	return models.IsVersionDeclare(valueDeclare) || models.IsCreatedAtDeclare(valueDeclare) || models.IsUpdatedAtDeclare(valueDeclare)
+}
+
+// hasAutoUpdateFields 声明了版本或更新时间字段的模型，每次Update都需要更新host
+func hasAutoUpdateFields(vModel models.Model) bool {
+	return models.GetVersionField(vModel) != nil || models.GetUpdatedAtField(vModel) != nil
+}
+
+// updateRequiresTransaction 更新关系或存在Update钩子时需要事务，保证钩子失败能回滚已执行的更新
 func updateRequiresTransaction(vModel models.Model) bool {
 	if vModel == nil {
 		return false
 	}
+	if hasHook(vModel, BeforeUpdate) || hasHook(vModel, AfterUpdate) {
+		return true
+	}
 
 	for _, field := range vModel.GetFields() {
 		if models.IsBasicField(field) || !models.IsAssignedField(field) || isReadOnlyField(field) {
@@ -285,6 +387,15 @@ func updateRequiresTransaction(vModel models.Model) bool {
 }
 
 func (s *impl) Update(vModel models.Model) (ret models.Model, err *cd.Error) {
+	if len(s.interceptors) > 0 {
+		return s.interceptModel(metrics.OperationUpdate, "Update", vModel, (*impl).Update)
+	}
+
+	return s.update(vModel, nil)
+}
+
+// update relationSelection 非空时，其中的包含关系字段只更新子对象的指定字段
+func (s *impl) update(vModel models.Model, relationSelection fieldSelection) (ret models.Model, err *cd.Error) {
 	startTime := time.Now()
 	var validationDuration time.Duration
 	var txBeginDuration time.Duration
@@ -302,6 +413,9 @@ func (s *impl) Update(vModel models.Model) (ret models.Model, err *cd.Error) {
 		return
 	}
 
+	restoreTimeout := s.applyCallTimeout()
+	defer restoreTimeout()
+
 	if vModel == nil {
 		err = cd.NewError(cd.IllegalParam, "illegal model value")
 		return
@@ -335,6 +449,7 @@ func (s *impl) Update(vModel models.Model) (ret models.Model, err *cd.Error) {
 	}
 
 	updateRunner := NewUpdateRunner(s.context, vModel, s.executor, s.modelProvider, s.modelCodec)
+	updateRunner.relationSelection = relationSelection
 	ret, err = updateRunner.Update()
 	if err != nil {
 		slog.Error("Update UpdateRunner.Update failed", "pkgKey", vModel.GetPkgKey(), "error", err.Error())
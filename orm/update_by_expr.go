@@ -0,0 +1,111 @@
+package orm
+
+import (
+	"fmt"
+	"time"
+
+	cd "github.com/muidea/magicCommon/def"
+
+	"log/slog"
+
+	"github.com/muidea/magicOrm/metrics"
+	"github.com/muidea/magicOrm/models"
+)
+
+// verifyUpdateExprs 校验表达式字段可更新，数值类型等其余约束由builder校验
+func verifyUpdateExprs(vModel models.Model, exprs []models.UpdateExpr) (err *cd.Error) {
+	for _, expr := range exprs {
+		vField := vModel.GetField(expr.Field)
+		if vField == nil {
+			err = cd.NewError(cd.IllegalParam, fmt.Sprintf("illegal update field, field:%s", expr.Field))
+			return
+		}
+		if isReadOnlyField(vField) {
+			err = cd.NewError(cd.IllegalParam, fmt.Sprintf("read-only field can't be updated, field:%s", expr.Field))
+			return
+		}
+		if isAutoUpdateField(vField) {
+			err = cd.NewError(cd.IllegalParam, fmt.Sprintf("field is maintained automatically, field:%s", expr.Field))
+			return
+		}
+	}
+	return
+}
+
+func (s *UpdateByFilterRunner) UpdateByExpr(vFilter models.Filter, exprs []models.UpdateExpr, guards []models.UpdateGuard) (ret int64, err *cd.Error) {
+	if err = s.checkContext(); err != nil {
+		return
+	}
+
+	updateResult, updateErr := s.sqlBuilder.BuildUpdateByExpr(s.vModel, vFilter, exprs, guards)
+	if updateErr != nil {
+		err = updateErr
+		slog.Error("UpdateByFilterRunner UpdateByExpr BuildUpdateByExpr failed", "error", err.Error())
+		return
+	}
+
+	ret, err = s.executor.Execute(updateResult.SQL(), updateResult.Args()...)
+	if err != nil {
+		slog.Error("UpdateByFilterRunner UpdateByExpr Execute failed", "error", err.Error())
+	}
+	return
+}
+
+// UpdateByExpr 对满足filter与guards的行原子执行exprs，如 SET qty = qty - ?，返回受影响的行数
+//
+// exprs只允许非主键、非只读的数值基础字段；guards与filter以AND组合，返回0表示没有行满足条件（如库存不足）。
+// 由数据库在当前值上计算，并发更新不会丢失；声明了版本与更新时间字段时同时维护。
+// 只生成一条UPDATE，不加载模型，也不触发钩子；filter的分页与排序被忽略。
+func (s *impl) UpdateByExpr(vFilter models.Filter, exprs []models.UpdateExpr, guards ...models.UpdateGuard) (ret int64, err *cd.Error) {
+	if len(s.interceptors) > 0 {
+		return s.interceptUpdateByExpr(vFilter, exprs, guards)
+	}
+
+	startTime := time.Now()
+	var vModel models.Model
+
+	defer func() {
+		duration := time.Since(startTime)
+		if ormMetricCollector != nil {
+			ormMetricCollector.RecordOperation(string(metrics.OperationUpdate), vModel, duration, cd.ToStdError(err))
+		}
+	}()
+
+	if err = s.CheckContext(); err != nil {
+		return
+	}
+
+	restoreTimeout := s.applyCallTimeout()
+	defer restoreTimeout()
+
+	if vFilter == nil {
+		err = cd.NewError(cd.IllegalParam, "illegal filter value")
+		return
+	}
+	if len(exprs) == 0 {
+		err = cd.NewError(cd.IllegalParam, "illegal update expressions")
+		return
+	}
+
+	vModel = vFilter.MaskModel().Copy(models.MetaView)
+	err = verifyUpdateExprs(vModel, exprs)
+	if err != nil {
+		slog.Error("UpdateByExpr verifyUpdateExprs failed", "pkgKey", vModel.GetPkgKey(), "error", err.Error())
+		return
+	}
+
+	if updatedField := models.GetUpdatedAtField(vModel); updatedField != nil {
+		err = updatedField.SetValue(currentDateTime(s.context))
+		if err != nil {
+			slog.Error("UpdateByExpr set updatedAt failed", "pkgKey", vModel.GetPkgKey(), "error", err.Error())
+			return
+		}
+	}
+
+	updateRunner := NewUpdateByFilterRunner(s.context, vModel, s.executor, s.modelProvider, s.modelCodec)
+	ret, err = updateRunner.UpdateByExpr(vFilter, exprs, guards)
+	if err != nil {
+		slog.Error("UpdateByExpr UpdateByFilterRunner.UpdateByExpr failed", "pkgKey", vModel.GetPkgKey(), "error", err.Error())
+	}
+	return
+}
@@ -0,0 +1,115 @@
+package orm
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	cd "github.com/muidea/magicCommon/def"
+
+	"github.com/muidea/magicOrm/database/codec"
+	"github.com/muidea/magicOrm/models"
+	"github.com/muidea/magicOrm/provider"
+	"github.com/muidea/magicOrm/provider/remote"
+)
+
+func newStockTestOrm(t *testing.T, executor *fakeExecutor, now time.Time) (*impl, models.Filter) {
+	t.Helper()
+
+	stockObject := &remote.Object{
+		Name:    "stock",
+		PkgPath: "/bench",
+		Fields: []*remote.Field{
+			{
+				Name: "id",
+				Type: &remote.TypeImpl{Name: "int64", Value: models.TypeBigIntegerValue},
+				Spec: &remote.SpecImpl{FieldName: "id", PrimaryKey: true, ViewDeclare: []models.ViewDeclare{models.DetailView}},
+			},
+			{
+				Name: "qty",
+				Type: &remote.TypeImpl{Name: "int64", Value: models.TypeBigIntegerValue},
+				Spec: &remote.SpecImpl{FieldName: "qty", ViewDeclare: []models.ViewDeclare{models.DetailView}},
+			},
+			{
+				Name: "price",
+				Type: &remote.TypeImpl{Name: "float64", Value: models.TypeDoubleValue},
+				Spec: &remote.SpecImpl{FieldName: "price", ViewDeclare: []models.ViewDeclare{models.DetailView}},
+			},
+			{
+				Name: "updatedAt",
+				Type: &remote.TypeImpl{Name: "time.Time", Value: models.TypeDateTimeValue},
+				Spec: &remote.SpecImpl{FieldName: "updatedAt", ValueDeclare: models.UpdatedAt, ViewDeclare: []models.ViewDeclare{models.DetailView}},
+			},
+		},
+	}
+
+	remoteProvider := provider.NewRemoteProvider("tenant", nil)
+	if _, err := remoteProvider.RegisterModel(stockObject); err != nil {
+		t.Fatalf("RegisterModel(stock) failed: %v", err)
+	}
+	stockModel, err := remoteProvider.GetEntityModel(&remote.ObjectValue{Name: "stock", PkgPath: "/bench"}, true)
+	if err != nil {
+		t.Fatalf("GetEntityModel(stock) failed: %v", err)
+	}
+	filter, err := remoteProvider.GetModelFilter(stockModel)
+	if err != nil {
+		t.Fatalf("GetModelFilter(stock) failed: %v", err)
+	}
+
+	ormImpl := &impl{
+		context:       ContextWithClock(context.Background(), func() time.Time { return now }),
+		executor:      executor,
+		modelProvider: remoteProvider,
+		modelCodec:    codec.New(remoteProvider, "tenant"),
+	}
+	return ormImpl, filter
+}
+
+func TestUpdateByExprGuardedDecrease(t *testing.T) {
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	executor := &fakeExecutor{rowsAffected: 1}
+	ormImpl, filter := newStockTestOrm(t, executor, now)
+	if err := filter.Equal("id", int64(7)); err != nil {
+		t.Fatalf("filter.Equal(id) failed: %v", err)
+	}
+
+	affected, err := ormImpl.UpdateByExpr(filter, []models.UpdateExpr{models.Decrease("qty", 3), models.Multiply("price", 0.9)}, models.Guard("qty", models.AboveEqualGuard, 3))
+	if err != nil || affected != 1 {
+		t.Fatalf("impl.UpdateByExpr(stock) failed, affected:%d, err:%v", affected, err)
+	}
+	if len(executor.execCalls) != 1 || executor.execCalls[0].sql != `UPDATE "tenant_Stock" SET "qty" = "qty" - $1,"price" = "price" * $2,"updatedAt" = $3 WHERE "id" = $4 AND "qty" >= $5` {
+		t.Fatalf("unexpected update by expr calls: %#v", executor.execCalls)
+	}
+	if updatedArg, ok := executor.execCalls[0].args[2].(string); !ok || !strings.HasPrefix(updatedArg, "2024-05-01 09:00:00") {
+		t.Fatalf("updatedAt should use the injected clock, got %#v", executor.execCalls[0].args[2])
+	}
+	if executor.beginCalls != 0 {
+		t.Fatalf("single statement update should not open transaction, got begin=%d", executor.beginCalls)
+	}
+
+	executor.rowsAffected = 0
+	affected, err = ormImpl.UpdateByExpr(filter, []models.UpdateExpr{models.Decrease("qty", 100)}, models.Guard("qty", models.AboveEqualGuard, 100))
+	if err != nil || affected != 0 {
+		t.Fatalf("unsatisfied guard should report no affected rows, affected:%d, err:%v", affected, err)
+	}
+}
+
+func TestUpdateByExprIllegalParams(t *testing.T) {
+	ormImpl, filter := newStockTestOrm(t, &fakeExecutor{}, time.Now())
+
+	if _, err := ormImpl.UpdateByExpr(nil, []models.UpdateExpr{models.Increase("qty", 1)}); err == nil || err.Code != cd.IllegalParam {
+		t.Fatalf("nil filter should be rejected, got %v", err)
+	}
+	for _, exprs := range [][]models.UpdateExpr{
+		nil,
+		{models.Increase("missing", 1)},
+		{models.Increase("id", 1)},
+		{models.Increase("updatedAt", 1)},
+	} {
+		_, err := ormImpl.UpdateByExpr(filter, exprs)
+		if err == nil || err.Code != cd.IllegalParam {
+			t.Fatalf("update expressions %#v should be rejected, got %v", exprs, err)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	cd "github.com/muidea/magicCommon/def"
+
+	"log/slog"
+
+	"github.com/muidea/magicOrm/database"
+	"github.com/muidea/magicOrm/database/codec"
+	"github.com/muidea/magicOrm/metrics"
+	"github.com/muidea/magicOrm/models"
+	"github.com/muidea/magicOrm/provider"
+)
+
+// UpdateByFilterRunner 按filter批量更新host表的基础字段，生成单条UPDATE
+type UpdateByFilterRunner struct {
+	baseRunner
+}
+
+func NewUpdateByFilterRunner(
+	ctx context.Context,
+	vModel models.Model,
+	executor database.Executor,
+	provider provider.Provider,
+	modelCodec codec.Codec) *UpdateByFilterRunner {
+	return &UpdateByFilterRunner{
+		baseRunner: newBaseRunner(ctx, vModel, executor, provider, modelCodec, false, 0),
+	}
+}
+
+// assignUpdateFields 把assignments赋值到vModel，返回按名称排序的待更新字段
+func assignUpdateFields(vModel models.Model, assignments map[string]any) (ret []string, err *cd.Error) {
+	for fieldName, fieldVal := range assignments {
+		vField := vModel.GetField(fieldName)
+		if vField == nil {
+			err = cd.NewError(cd.IllegalParam, fmt.Sprintf("illegal update field, field:%s", fieldName))
+			return
+		}
+		if models.IsPrimaryField(vField) || !models.IsBasicField(vField) {
+			err = cd.NewError(cd.IllegalParam, fmt.Sprintf("only basic fields can be updated by filter, field:%s", fieldName))
+			return
+		}
+		if constraints := vField.GetSpec().GetConstraints(); constraints != nil && constraints.Has(models.KeyReadOnly) {
+			err = cd.NewError(cd.IllegalParam, fmt.Sprintf("read-only field can't be updated, field:%s", fieldName))
+			return
+		}
+		if isAutoUpdateField(vField) {
+			err = cd.NewError(cd.IllegalParam, fmt.Sprintf("field is maintained automatically, field:%s", fieldName))
+			return
+		}
+
+		err = vModel.SetFieldValue(fieldName, fieldVal)
+		if err != nil {
+			return
+		}
+		ret = append(ret, fieldName)
+	}
+
+	slices.Sort(ret)
+	return
+}
+
+func (s *UpdateByFilterRunner) Update(vFilter models.Filter, fieldNames []string) (ret int64, err *cd.Error) {
+	if err = s.checkContext(); err != nil {
+		return
+	}
+
+	updateResult, updateErr := s.sqlBuilder.BuildUpdateByFilter(s.vModel, vFilter, fieldNames)
+	if updateErr != nil {
+		err = updateErr
+		slog.Error("UpdateByFilterRunner Update BuildUpdateByFilter failed", "error", err.Error())
+		return
+	}
+
+	ret, err = s.executor.Execute(updateResult.SQL(), updateResult.Args()...)
+	if err != nil {
+		slog.Error("UpdateByFilterRunner Update Execute failed", "error", err.Error())
+	}
+	return
+}
+
+// UpdateByFilter 把满足filter的行的基础字段更新为assignments中的值，返回受影响的行数
+//
+// assignments以字段名为key，只允许非主键、非只读的基础字段；值为nil时可选字段写入NULL。
+// 版本、创建时间与更新时间字段由Orm维护，不能出现在assignments中；声明了更新时间字段时同时刷新。
+// 只生成一条UPDATE，不加载模型，也不执行模型校验；filter的分页与排序被忽略。
+func (s *impl) UpdateByFilter(vFilter models.Filter, assignments map[string]any) (ret int64, err *cd.Error) {
+	if len(s.interceptors) > 0 {
+		return s.interceptFilter(metrics.OperationUpdate, "UpdateByFilter", vFilter, assignments, func(o *impl, inv *Invocation) (int64, *cd.Error) {
+			return o.UpdateByFilter(inv.Filter, inv.Assignments)
+		})
+	}
+
+	startTime := time.Now()
+	var vModel models.Model
+
+	defer func() {
+		duration := time.Since(startTime)
+		if ormMetricCollector != nil {
+			ormMetricCollector.RecordOperation(string(metrics.OperationUpdate), vModel, duration, cd.ToStdError(err))
+		}
+	}()
+
+	if err = s.CheckContext(); err != nil {
+		return
+	}
+
+	restoreTimeout := s.applyCallTimeout()
+	defer restoreTimeout()
+
+	if vFilter == nil {
+		err = cd.NewError(cd.IllegalParam, "illegal filter value")
+		return
+	}
+	if len(assignments) == 0 {
+		err = cd.NewError(cd.IllegalParam, "illegal update assignments")
+		return
+	}
+
+	vModel = vFilter.MaskModel().Copy(models.MetaView)
+	fieldNames, fieldErr := assignUpdateFields(vModel, assignments)
+	if fieldErr != nil {
+		err = fieldErr
+		slog.Error("UpdateByFilter assignUpdateFields failed", "pkgKey", vModel.GetPkgKey(), "error", err.Error())
+		return
+	}
+
+	if updatedField := models.GetUpdatedAtField(vModel); updatedField != nil {
+		err = updatedField.SetValue(currentDateTime(s.context))
+		if err != nil {
+			slog.Error("UpdateByFilter set updatedAt failed", "pkgKey", vModel.GetPkgKey(), "error", err.Error())
+			return
+		}
+		fieldNames = append(fieldNames, updatedField.GetName())
+	}
+
+	updateRunner := NewUpdateByFilterRunner(s.context, vModel, s.executor, s.modelProvider, s.modelCodec)
+	ret, err = updateRunner.Update(vFilter, fieldNames)
+	if err != nil {
+		slog.Error("UpdateByFilter UpdateByFilterRunner.Update failed", "pkgKey", vModel.GetPkgKey(), "error", err.Error())
+	}
+	return
+}
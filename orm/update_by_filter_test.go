@@ -0,0 +1,58 @@
+package orm
+
+import (
+	"context"
+	"testing"
+
+	cd "github.com/muidea/magicCommon/def"
+	"github.com/muidea/magicOrm/database/codec"
+	"github.com/muidea/magicOrm/provider"
+)
+
+func TestUpdateByFilterVMIRemote(t *testing.T) {
+	remoteProvider := provider.NewRemoteProvider("tenant", nil)
+	registerVMIQueryModels(t, remoteProvider)
+
+	filter, err := remoteProvider.GetModelFilter(buildProductQueryModel(t, remoteProvider))
+	if err != nil {
+		t.Fatalf("GetModelFilter(product) failed: %v", err)
+	}
+	if err = filter.Below("expire", 10); err != nil {
+		t.Fatalf("filter.Below(expire) failed: %v", err)
+	}
+
+	executor := &fakeExecutor{rowsAffected: 3}
+	ormImpl := &impl{
+		context:       context.Background(),
+		executor:      executor,
+		modelProvider: remoteProvider,
+		modelCodec:    codec.New(remoteProvider, "tenant"),
+	}
+
+	affected, err := ormImpl.UpdateByFilter(filter, map[string]any{"name": "expired", "description": "archived"})
+	if err != nil {
+		t.Fatalf("impl.UpdateByFilter(product) failed: %v", err)
+	}
+	if affected != 3 {
+		t.Fatalf("expected 3 affected rows, got %d", affected)
+	}
+	if len(executor.execCalls) != 1 || !containsSQLCall(executor.execCalls, "exec", `UPDATE "tenant_Product" SET "description" = $1,"name" = $2 WHERE "expire" < $3`, []any{"archived", "expired", 10}) {
+		t.Fatalf("unexpected update by filter calls: %#v", executor.execCalls)
+	}
+	if executor.beginCalls != 0 {
+		t.Fatalf("single statement update should not open transaction, got begin=%d", executor.beginCalls)
+	}
+
+	for _, assignments := range []map[string]any{
+		nil,
+		{"unknown": 1},
+		{"id": int64(1)},
+		{"createTime": int64(1)},
+		{"status": nil},
+	} {
+		_, err = ormImpl.UpdateByFilter(filter, assignments)
+		if err == nil || err.Code != cd.IllegalParam {
+			t.Fatalf("assignments %#v should be rejected, got %v", assignments, err)
+		}
+	}
+}
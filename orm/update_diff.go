@@ -779,3 +779,41 @@ func buildContainPrimaryEntryMapForUpdate(entries []containModelEntry) (ret map[
 	ok = true
 	return
 }
+
+// updateContainRelationFields 只更新包含关系子对象中selection指定的字段，子对象必须带主键，不比较也不变更关系本身
+func (s *UpdateRunner) updateContainRelationFields(vField models.Field, selection fieldSelection) (err *cd.Error) {
+	if !models.IsValidField(vField) {
+		return
+	}
+
+	relationType := vField.GetType()
+	rawValues := []models.Value{vField.GetValue()}
+	if models.IsSliceField(vField) {
+		relationType = relationType.Elem()
+		rawValues = vField.GetSliceValue()
+	}
+
+	for _, rawValue := range rawValues {
+		if rawValue == nil || !rawValue.IsValid() {
+			continue
+		}
+
+		childModel, modelErr := s.relationModelFromRaw(relationType, rawValue.Get())
+		if modelErr != nil {
+			err = modelErr
+			return
+		}
+		patchModel, patchErr := buildFieldsPatchModel(childModel, selection)
+		if patchErr != nil {
+			err = patchErr
+			return
+		}
+
+		childRunner := NewUpdateRunner(s.context, patchModel, s.executor, s.modelProvider, s.modelCodec)
+		childRunner.relationSelection = selection.relations()
+		if _, err = childRunner.Update(); err != nil {
+			return
+		}
+	}
+	return
+}
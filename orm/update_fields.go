@@ -0,0 +1,190 @@
+package orm
+
+import (
+	"fmt"
+	"strings"
+
+	cd "github.com/muidea/magicCommon/def"
+
+	"log/slog"
+
+	"github.com/muidea/magicOrm/models"
+)
+
+// fieldSelection UpdateFields 指定的字段，key为字段名，value为包含关系字段下继续限定的子字段，nil表示整个字段
+type fieldSelection map[string]fieldSelection
+
+// parseFieldSelection 解析字段名列表，点路径 "profile.email" 表示只更新包含关系子对象的 email 字段
+//
+// 同时指定整个字段与其子路径时，按整个字段处理。
+func parseFieldSelection(fieldNames []string) (ret fieldSelection, err *cd.Error) {
+	if len(fieldNames) == 0 {
+		err = cd.NewError(cd.IllegalParam, "update fields is empty")
+		return
+	}
+
+	ret = fieldSelection{}
+	for _, fieldName := range fieldNames {
+		items := strings.Split(fieldName, ".")
+		current := ret
+		for idx, item := range items {
+			if item == "" {
+				err = cd.NewError(cd.IllegalParam, fmt.Sprintf("illegal update field, field:%s", fieldName))
+				return
+			}
+
+			sub, ok := current[item]
+			if idx == len(items)-1 {
+				current[item] = nil
+				break
+			}
+			if ok && sub == nil {
+				// 已指定整个字段
+				break
+			}
+			if !ok {
+				sub = fieldSelection{}
+				current[item] = sub
+			}
+			current = sub
+		}
+	}
+	return
+}
+
+// relations 返回通过点路径限定了子字段的关系字段
+func (s fieldSelection) relations() (ret fieldSelection) {
+	for fieldName, sub := range s {
+		if sub == nil {
+			continue
+		}
+		if ret == nil {
+			ret = fieldSelection{}
+		}
+		ret[fieldName] = sub
+	}
+	return
+}
+
+// verifyFieldSelection 校验字段存在且可更新，点路径只允许用于包含关系字段
+func (s *impl) verifyFieldSelection(vModel models.Model, selection fieldSelection) (err *cd.Error) {
+	for fieldName, sub := range selection {
+		vField := vModel.GetField(fieldName)
+		if vField == nil {
+			err = cd.NewError(cd.IllegalParam, fmt.Sprintf("update field not found, model:%s, field:%s", vModel.GetPkgKey(), fieldName))
+			return
+		}
+		if models.IsPrimaryField(vField) || isReadOnlyField(vField) {
+			err = cd.NewError(cd.IllegalParam, fmt.Sprintf("field can't be updated, field:%s", fieldName))
+			return
+		}
+		if isAutoUpdateField(vField) {
+			err = cd.NewError(cd.IllegalParam, fmt.Sprintf("field is maintained automatically, field:%s", fieldName))
+			return
+		}
+		if sub == nil {
+			continue
+		}
+		if models.IsBasicField(vField) || isReferenceRelationField(vField) {
+			err = cd.NewError(cd.IllegalParam, fmt.Sprintf("field path only supports contain relation field, field:%s", fieldName))
+			return
+		}
+
+		relationModel, relationErr := s.modelProvider.GetTypeModel(vField.GetType().Elem())
+		if relationErr != nil {
+			err = relationErr
+			return
+		}
+		err = s.verifyFieldSelection(relationModel, sub)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// buildFieldsPatchModel 构造只包含主键、版本与selection字段的更新模型，selection字段即使为零值也视为已赋值
+func buildFieldsPatchModel(vModel models.Model, selection fieldSelection) (ret models.Model, err *cd.Error) {
+	pkField := vModel.GetPrimaryField()
+	if pkField == nil || pkField.GetValue() == nil || pkField.GetValue().IsZero() {
+		err = cd.NewError(cd.IllegalParam, fmt.Sprintf("update fields requires primary key, model:%s", vModel.GetPkgKey()))
+		return
+	}
+
+	patchModel := vModel.Copy(models.MetaView)
+	err = patchModel.SetPrimaryFieldValue(pkField.GetValue().Get())
+	if err != nil {
+		return
+	}
+	if versionField := models.GetVersionField(vModel); versionField != nil && !versionField.GetValue().IsZero() {
+		err = patchModel.SetFieldValue(versionField.GetName(), versionField.GetValue().Get())
+		if err != nil {
+			return
+		}
+	}
+
+	for fieldName := range selection {
+		err = patchModel.SetFieldValue(fieldName, vModel.GetField(fieldName).GetValue().Get())
+		if err != nil {
+			slog.Error("buildFieldsPatchModel SetFieldValue failed", "field", fieldName, "error", err.Error())
+			return
+		}
+	}
+
+	ret = patchModel
+	return
+}
+
+// syncAutoUpdateFields 把更新后的版本与更新时间同步回调用方的模型
+func syncAutoUpdateFields(vModel, patchModel models.Model) (err *cd.Error) {
+	for _, field := range []models.Field{models.GetVersionField(patchModel), models.GetUpdatedAtField(patchModel)} {
+		if field == nil || !models.IsAssignedField(field) {
+			continue
+		}
+
+		err = vModel.SetFieldValue(field.GetName(), field.GetValue().Get())
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// UpdateFields 只更新fieldNames指定的字段，未列出的字段即使已赋值也不更新，列出的字段即使为零值也会更新
+//
+// 点路径（如 "profile.email"）只更新包含关系子对象的指定字段，子对象必须带主键，关系本身保持不变。
+func (s *impl) UpdateFields(vModel models.Model, fieldNames ...string) (ret models.Model, err *cd.Error) {
+	if len(s.interceptors) > 0 {
+		return s.interceptUpdateFields(vModel, fieldNames)
+	}
+
+	if vModel == nil {
+		err = cd.NewError(cd.IllegalParam, "illegal model value")
+		return
+	}
+
+	selection, selectionErr := parseFieldSelection(fieldNames)
+	if selectionErr != nil {
+		err = selectionErr
+		return
+	}
+	err = s.verifyFieldSelection(vModel, selection)
+	if err != nil {
+		slog.Error("UpdateFields verifyFieldSelection failed", "pkgKey", vModel.GetPkgKey(), "error", err.Error())
+		return
+	}
+
+	patchModel, patchErr := buildFieldsPatchModel(vModel, selection)
+	if patchErr != nil {
+		err = patchErr
+		return
+	}
+
+	ret, err = s.update(patchModel, selection.relations())
+	if err != nil {
+		return
+	}
+
+	err = syncAutoUpdateFields(vModel, patchModel)
+	return
+}
@@ -0,0 +1,144 @@
+package orm
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	cd "github.com/muidea/magicCommon/def"
+
+	"github.com/muidea/magicOrm/database/codec"
+	"github.com/muidea/magicOrm/models"
+	"github.com/muidea/magicOrm/provider"
+	"github.com/muidea/magicOrm/provider/remote"
+)
+
+type fieldsProfile struct {
+	ID    int    `orm:"id key auto"`
+	Email string `orm:"email"`
+	Phone string `orm:"phone"`
+}
+
+type fieldsUser struct {
+	ID      int           `orm:"id key auto"`
+	Name    string        `orm:"name"`
+	Email   string        `orm:"email"`
+	Profile fieldsProfile `orm:"profile"`
+}
+
+func newUpdateFieldsTestOrm(t *testing.T, executor *fakeExecutor, user *fieldsUser) (*impl, models.Model) {
+	t.Helper()
+
+	localProvider := provider.NewLocalProvider("tenant", nil)
+	for _, entity := range []any{&fieldsProfile{}, &fieldsUser{}} {
+		if _, err := localProvider.RegisterModel(entity); err != nil {
+			t.Fatalf("RegisterModel(%T) failed: %v", entity, err)
+		}
+	}
+
+	userModel, err := localProvider.GetEntityModel(user, true)
+	if err != nil {
+		t.Fatalf("GetEntityModel(fieldsUser) failed: %v", err)
+	}
+
+	ormImpl := &impl{
+		context:       context.Background(),
+		executor:      executor,
+		modelProvider: localProvider,
+		modelCodec:    codec.New(localProvider, "tenant"),
+	}
+	return ormImpl, userModel
+}
+
+func TestUpdateFieldsRestrictsColumns(t *testing.T) {
+	executor := &fakeExecutor{rowsAffected: 1}
+	ormImpl, userModel := newUpdateFieldsTestOrm(t, executor, &fieldsUser{ID: 1, Name: "alice", Email: "alice@example.com"})
+
+	if _, err := ormImpl.UpdateFields(userModel, "email"); err != nil {
+		t.Fatalf("impl.UpdateFields(email) failed: %v", err)
+	}
+	if !containsSQLCall(executor.execCalls, "exec", `UPDATE "tenant_FieldsUser" SET "email" = $1 WHERE "id" = $2`, []any{"alice@example.com", 1}) {
+		t.Fatalf("only email should be updated, got %#v", executor.execCalls)
+	}
+
+	executor.execCalls = nil
+	ormImpl, userModel = newUpdateFieldsTestOrm(t, executor, &fieldsUser{ID: 1, Name: "alice"})
+	if _, err := ormImpl.UpdateFields(userModel, "email"); err != nil {
+		t.Fatalf("impl.UpdateFields(zero email) failed: %v", err)
+	}
+	if !containsSQLCall(executor.execCalls, "exec", `UPDATE "tenant_FieldsUser" SET "email" = $1 WHERE "id" = $2`, []any{"", 1}) {
+		t.Fatalf("named zero value should be written, got %#v", executor.execCalls)
+	}
+}
+
+func TestUpdateFieldsRelationPath(t *testing.T) {
+	executor := &fakeExecutor{rowsAffected: 1}
+	ormImpl, userModel := newUpdateFieldsTestOrm(t, executor, &fieldsUser{
+		ID:      1,
+		Name:    "alice",
+		Profile: fieldsProfile{ID: 5, Email: "profile@example.com", Phone: "123"},
+	})
+
+	if _, err := ormImpl.UpdateFields(userModel, "profile.email"); err != nil {
+		t.Fatalf("impl.UpdateFields(profile.email) failed: %v", err)
+	}
+	if !containsSQLCall(executor.execCalls, "exec", `UPDATE "tenant_FieldsProfile" SET "email" = $1 WHERE "id" = $2`, []any{"profile@example.com", 5}) {
+		t.Fatalf("only profile email should be updated, got %#v", executor.execCalls)
+	}
+	for _, call := range executor.execCalls {
+		if call.kind != "query" && (strings.Contains(call.sql, "tenant_FieldsUser") || !strings.HasPrefix(call.sql, "UPDATE")) {
+			t.Fatalf("relation path should not touch host or relation rows, got %#v", executor.execCalls)
+		}
+	}
+}
+
+func TestUpdateFieldsIllegalNames(t *testing.T) {
+	ormImpl, userModel := newUpdateFieldsTestOrm(t, &fakeExecutor{}, &fieldsUser{ID: 1, Name: "alice"})
+
+	for _, fieldNames := range [][]string{
+		nil,
+		{"missing"},
+		{"id"},
+		{"name."},
+		{"name.first"},
+		{"profile.missing"},
+		{"profile.id"},
+	} {
+		_, err := ormImpl.UpdateFields(userModel, fieldNames...)
+		if err == nil || err.Code != cd.IllegalParam {
+			t.Fatalf("UpdateFields(%v) should be IllegalParam, got %v", fieldNames, err)
+		}
+	}
+}
+
+func TestParseFieldSelection(t *testing.T) {
+	selection, err := parseFieldSelection([]string{"profile.email", "name", "profile.phone", "items.tag", "items"})
+	if err != nil {
+		t.Fatalf("parseFieldSelection failed: %v", err)
+	}
+	if len(selection) != 3 || selection["name"] != nil || selection["items"] != nil || len(selection["profile"]) != 2 {
+		t.Fatalf("unexpected selection: %#v", selection)
+	}
+	if relations := selection.relations(); len(relations) != 1 || relations["profile"] == nil {
+		t.Fatalf("unexpected relation selection: %#v", relations)
+	}
+}
+
+func TestUpdateFieldsSyncsVersion(t *testing.T) {
+	executor := &fakeExecutor{rowsAffected: 1}
+	ormImpl, docModel := newVersionTestOrm(t, executor,
+		&remote.FieldValue{Name: "id", Value: int64(7)},
+		&remote.FieldValue{Name: "title", Value: "edited"},
+		&remote.FieldValue{Name: "version", Value: int64(3)},
+	)
+
+	if _, err := ormImpl.UpdateFields(docModel, "title"); err != nil {
+		t.Fatalf("impl.UpdateFields(doc) failed: %v", err)
+	}
+	if !containsSQLCall(executor.execCalls, "exec", `UPDATE "tenant_Doc" SET "title" = $1,"version" = "version" + 1 WHERE "id" = $2 AND "version" = $3`, []any{"edited", int64(7), int64(3)}) {
+		t.Fatalf("unexpected versioned update calls: %#v", executor.execCalls)
+	}
+	if docModel.GetField("version").GetValue().Get() != int64(4) {
+		t.Fatalf("version should be synced to the source model, got %v", docModel.GetField("version").GetValue().Get())
+	}
+}
@@ -0,0 +1,110 @@
+package orm
+
+import (
+	"context"
+	"testing"
+
+	cd "github.com/muidea/magicCommon/def"
+
+	"github.com/muidea/magicOrm/database/codec"
+	"github.com/muidea/magicOrm/models"
+	"github.com/muidea/magicOrm/provider"
+	"github.com/muidea/magicOrm/provider/remote"
+)
+
+func testVersionDocObject() *remote.Object {
+	return &remote.Object{
+		Name:    "doc",
+		PkgPath: "/bench",
+		Fields: []*remote.Field{
+			{
+				Name: "id",
+				Type: &remote.TypeImpl{Name: "int64", Value: models.TypeBigIntegerValue},
+				Spec: &remote.SpecImpl{FieldName: "id", PrimaryKey: true, ViewDeclare: []models.ViewDeclare{models.DetailView, models.LiteView}},
+			},
+			{
+				Name: "title",
+				Type: &remote.TypeImpl{Name: "string", Value: models.TypeStringValue},
+				Spec: &remote.SpecImpl{FieldName: "title", ViewDeclare: []models.ViewDeclare{models.DetailView, models.LiteView}},
+			},
+			{
+				Name: "version",
+				Type: &remote.TypeImpl{Name: "int64", Value: models.TypeBigIntegerValue},
+				Spec: &remote.SpecImpl{FieldName: "version", ValueDeclare: models.Version, ViewDeclare: []models.ViewDeclare{models.DetailView, models.LiteView}},
+			},
+		},
+	}
+}
+
+func newVersionTestOrm(t *testing.T, executor *fakeExecutor, fields ...*remote.FieldValue) (*impl, models.Model) {
+	t.Helper()
+
+	remoteProvider := provider.NewRemoteProvider("tenant", nil)
+	if _, err := remoteProvider.RegisterModel(testVersionDocObject()); err != nil {
+		t.Fatalf("RegisterModel(doc) failed: %v", err)
+	}
+
+	docModel, err := remoteProvider.GetEntityModel(&remote.ObjectValue{Name: "doc", PkgPath: "/bench", Fields: fields}, true)
+	if err != nil {
+		t.Fatalf("GetEntityModel(doc) failed: %v", err)
+	}
+
+	ormImpl := &impl{
+		context:       context.Background(),
+		executor:      executor,
+		modelProvider: remoteProvider,
+		modelCodec:    codec.New(remoteProvider, "tenant"),
+	}
+	return ormImpl, docModel
+}
+
+func TestUpdateVersionConflict(t *testing.T) {
+	executor := &fakeExecutor{}
+	ormImpl, docModel := newVersionTestOrm(t, executor,
+		&remote.FieldValue{Name: "id", Value: int64(7)},
+		&remote.FieldValue{Name: "title", Value: "edited"},
+		&remote.FieldValue{Name: "version", Value: int64(3)},
+	)
+
+	_, err := ormImpl.Update(docModel)
+	if err == nil || err.Code != cd.VersionConflict {
+		t.Fatalf("expected VersionConflict, got %v", err)
+	}
+	if !containsSQLCall(executor.execCalls, "exec", `UPDATE "tenant_Doc" SET "title" = $1,"version" = "version" + 1 WHERE "id" = $2 AND "version" = $3`, []any{"edited", int64(7), int64(3)}) {
+		t.Fatalf("unexpected versioned update calls: %#v", executor.execCalls)
+	}
+	if docModel.GetField("version").GetValue().Get() != int64(3) {
+		t.Fatalf("version should be unchanged on conflict, got %v", docModel.GetField("version").GetValue().Get())
+	}
+}
+
+func TestUpdateVersionIncrease(t *testing.T) {
+	executor := &fakeExecutor{rowsAffected: 1}
+	ormImpl, docModel := newVersionTestOrm(t, executor,
+		&remote.FieldValue{Name: "id", Value: int64(7)},
+		&remote.FieldValue{Name: "title", Value: "edited"},
+		&remote.FieldValue{Name: "version", Value: int64(3)},
+	)
+
+	if _, err := ormImpl.Update(docModel); err != nil {
+		t.Fatalf("impl.Update(doc) failed: %v", err)
+	}
+	if docModel.GetField("version").GetValue().Get() != int64(4) {
+		t.Fatalf("expected version 4 after update, got %v", docModel.GetField("version").GetValue().Get())
+	}
+}
+
+func TestInsertInitializesVersion(t *testing.T) {
+	executor := &fakeExecutor{}
+	ormImpl, docModel := newVersionTestOrm(t, executor,
+		&remote.FieldValue{Name: "id", Value: int64(7)},
+		&remote.FieldValue{Name: "title", Value: "draft"},
+	)
+
+	if _, err := ormImpl.Insert(docModel); err != nil {
+		t.Fatalf("impl.Insert(doc) failed: %v", err)
+	}
+	if !containsSQLCall(executor.execCalls, "insert", `INSERT INTO "tenant_Doc"`, []any{int64(7), "draft", int64(1)}) {
+		t.Fatalf("insert should start version at 1, got %#v", executor.execCalls)
+	}
+}
@@ -119,6 +119,16 @@ func getOrmSpec(spec string) (ret remote.SpecImpl, err *cd.Error) {
 			ret.ValueDeclare = models.Snowflake
 		case models.DateTime:
 			ret.ValueDeclare = models.DateTime
+		case models.SoftDelete:
+			ret.ValueDeclare = models.SoftDelete
+		case models.Version:
+			ret.ValueDeclare = models.Version
+		case models.CreatedAt:
+			ret.ValueDeclare = models.CreatedAt
+		case models.UpdatedAt:
+			ret.ValueDeclare = models.UpdatedAt
+		case models.FullText:
+			ret.ValueDeclare = models.FullText
 		case models.KeyTag:
 			ret.PrimaryKey = true
 		}
@@ -83,6 +83,36 @@ func TestSpec(t *testing.T) {
 	}
 }
 
+type VersionedDoc struct {
+	ID      int64  `orm:"id key auto" view:"detail"`
+	Title   string `orm:"title" view:"detail"`
+	Version int64  `orm:"version version" view:"detail"`
+}
+
+func TestVersionObjectSpec(t *testing.T) {
+	info, err := GetObject(&VersionedDoc{})
+	if err != nil {
+		t.Fatalf("GetObject failed, err:%s", err.Error())
+	}
+
+	byteVal, byteErr := json.Marshal(info)
+	if byteErr != nil {
+		t.Fatalf("marshal info failed, err:%s", byteErr.Error())
+	}
+	info2 := &remote.Object{}
+	if byteErr = json.Unmarshal(byteVal, info2); byteErr != nil {
+		t.Fatalf("unmarshal info failed, err:%s", byteErr.Error())
+	}
+
+	versionField := models.GetVersionField(info2)
+	if versionField == nil || versionField.GetName() != "version" {
+		t.Fatalf("remote spec should carry version declare, got %v", versionField)
+	}
+	if err = info2.Verify(); err != nil {
+		t.Fatalf("verify versioned object failed, err:%s", err.Error())
+	}
+}
+
 func TestSimpleObjInfo(t *testing.T) {
 	desc := "obj_desc"
 	obj := Simple{Name: "obj", Desc: &desc, Age: 240}
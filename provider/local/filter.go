@@ -3,6 +3,7 @@ package local
 import (
 	"fmt"
 	"reflect"
+	"strings"
 
 	"log/slog"
 
@@ -24,17 +25,33 @@ func (s *filterItem) OprValue() models.Value {
 	return s.value
 }
 
+type existsItem struct {
+	notExists bool
+	subFilter models.Filter
+}
+
+func (s *existsItem) NotExists() bool {
+	return s.notExists
+}
+
+func (s *existsItem) SubFilter() models.Filter {
+	return s.subFilter
+}
+
 type filter struct {
 	bindValue  *ValueImpl
 	bindModel  models.Model
 	params     map[string]*filterItem
+	exists     map[string]*existsItem
 	maskValue  *ValueImpl
 	pageFilter *utils.Pagination
 	sortFilter *utils.SortFilter
+	deleted    models.DeletedScope
+	selects    []string
 }
 
 func newFilter(valuePtr *ValueImpl, bindModels ...models.Model) *filter {
-	ret := &filter{bindValue: valuePtr, params: map[string]*filterItem{}}
+	ret := &filter{bindValue: valuePtr, params: map[string]*filterItem{}, exists: map[string]*existsItem{}}
 	if len(bindModels) > 0 {
 		ret.bindModel = bindModels[0]
 	}
@@ -218,6 +235,56 @@ func (s *filter) Like(key string, val any) (err *cd.Error) {
 	return
 }
 
+func (s *filter) Match(key string, val any) (err *cd.Error) {
+	if val == nil {
+		err = cd.NewError(cd.IllegalParam, "illegal match value")
+		slog.Error("filter op failed", "error", err.Error())
+		return
+	}
+
+	qv := reflect.Indirect(reflect.ValueOf(val))
+	if qv.Kind() != reflect.String || qv.String() == "" {
+		err = cd.NewError(cd.Unexpected, "match failed, illegal value type")
+		slog.Error("filter op failed", "error", err.Error())
+		return
+	}
+
+	s.params[key] = &filterItem{oprCode: models.MatchOpr, value: NewValue(qv)}
+	return
+}
+
+func (s *filter) Exists(key string, val models.Filter) (err *cd.Error) {
+	return s.setExists(key, val, false)
+}
+
+func (s *filter) NotExists(key string, val models.Filter) (err *cd.Error) {
+	return s.setExists(key, val, true)
+}
+
+// setExists 校验key为关系字段，且val绑定该关系的关联模型
+func (s *filter) setExists(key string, val models.Filter, notExists bool) (err *cd.Error) {
+	bindModel := s.MaskModel()
+	if bindModel == nil {
+		err = cd.NewError(cd.Unexpected, "illegal filter bind model")
+		return
+	}
+
+	vField := bindModel.GetField(key)
+	if vField == nil || models.IsBasicField(vField) {
+		err = cd.NewError(cd.IllegalParam, fmt.Sprintf("illegal exists field, name:%s", key))
+		slog.Error("filter op failed", "error", err.Error())
+		return
+	}
+	if val != nil && val.MaskModel().GetPkgKey() != vField.GetType().Elem().GetPkgKey() {
+		err = cd.NewError(cd.IllegalParam, fmt.Sprintf("exists filter model mismatch relation field, %s != %s", val.MaskModel().GetPkgKey(), vField.GetType().Elem().GetPkgKey()))
+		slog.Error("filter op failed", "error", err.Error())
+		return
+	}
+
+	s.exists[key] = &existsItem{notExists: notExists, subFilter: val}
+	return
+}
+
 func (s *filter) Pagination(pageNum, pageSize int) {
 	s.pageFilter = &utils.Pagination{
 		PageNum:  pageNum,
@@ -260,6 +327,55 @@ func (s *filter) GetFilterItem(key string) models.FilterItem {
 	return nil
 }
 
+func (s *filter) GetPathFilterItems(fieldName string) (ret map[string]models.FilterItem) {
+	prefix := fieldName + models.FieldPathSeparator
+	for key, item := range s.params {
+		subPath, ok := strings.CutPrefix(key, prefix)
+		if !ok {
+			continue
+		}
+		if ret == nil {
+			ret = map[string]models.FilterItem{}
+		}
+		ret[subPath] = item
+	}
+	return
+}
+
+func (s *filter) GetExistsItem(key string) models.ExistsItem {
+	v, ok := s.exists[key]
+	if ok {
+		return v
+	}
+
+	return nil
+}
+
+func (s *filter) Deleted(scope models.DeletedScope) {
+	s.deleted = scope
+}
+
+func (s *filter) GetDeletedScope() models.DeletedScope {
+	return s.deleted
+}
+
+func (s *filter) Select(fieldNames ...string) (err *cd.Error) {
+	for _, name := range fieldNames {
+		if name == "" || strings.Contains(name, models.FieldPathSeparator) {
+			err = cd.NewError(cd.IllegalParam, fmt.Sprintf("illegal select field, name:%s", name))
+			slog.Error("filter op failed", "error", err.Error())
+			return
+		}
+	}
+
+	s.selects = fieldNames
+	return
+}
+
+func (s *filter) GetSelectFields() []string {
+	return s.selects
+}
+
 func (s *filter) Paginationer() models.Paginationer {
 	if s.pageFilter == nil {
 		return nil
@@ -549,3 +549,96 @@ func TestFilterWithTimeValues(t *testing.T) {
 		return
 	}
 }
+
+// TestFilterPathItems tests dotted relation paths
+func TestFilterPathItems(t *testing.T) {
+	valueImpl := NewValue(reflect.ValueOf(TestStruct{ID: 1}))
+	filter := newFilter(valueImpl)
+
+	if err := filter.Like("group.name", "ops%"); err != nil {
+		t.Fatalf("Like(group.name) failed: %v", err)
+	}
+	if err := filter.Equal("group.owner.id", 3); err != nil {
+		t.Fatalf("Equal(group.owner.id) failed: %v", err)
+	}
+	if err := filter.Equal("name", "alice"); err != nil {
+		t.Fatalf("Equal(name) failed: %v", err)
+	}
+
+	items := filter.GetPathFilterItems("group")
+	if len(items) != 2 || items["name"] == nil || items["owner.id"] == nil {
+		t.Fatalf("unexpected group path items: %#v", items)
+	}
+	if items["name"].OprCode() != models.LikeOpr || items["owner.id"].OprCode() != models.EqualOpr {
+		t.Fatalf("unexpected path item operators: %v %v", items["name"].OprCode(), items["owner.id"].OprCode())
+	}
+	if filter.GetPathFilterItems("name") != nil || filter.GetFilterItem("group") != nil {
+		t.Fatal("path items should only be reachable through their relation prefix")
+	}
+}
+
+type existsMember struct {
+	ID   int    `orm:"id key"`
+	Name string `orm:"name"`
+}
+
+type existsGroup struct {
+	ID      int             `orm:"id key"`
+	Name    string          `orm:"name"`
+	Members []*existsMember `orm:"members"`
+}
+
+// TestFilterExists tests Exists/NotExists on relation fields
+func TestFilterExists(t *testing.T) {
+	filter := newFilter(NewValue(reflect.ValueOf(existsGroup{ID: 1})))
+	memberFilter := newFilter(NewValue(reflect.ValueOf(existsMember{})))
+	if err := memberFilter.Equal("name", "alice"); err != nil {
+		t.Fatalf("Equal(name) failed: %v", err)
+	}
+
+	if err := filter.Exists("members", memberFilter); err != nil {
+		t.Fatalf("Exists(members) failed: %v", err)
+	}
+	existsItem := filter.GetExistsItem("members")
+	if existsItem == nil || existsItem.NotExists() || existsItem.SubFilter() != memberFilter {
+		t.Fatalf("unexpected exists item: %#v", existsItem)
+	}
+
+	if err := filter.NotExists("members", nil); err != nil {
+		t.Fatalf("NotExists(members) failed: %v", err)
+	}
+	existsItem = filter.GetExistsItem("members")
+	if existsItem == nil || !existsItem.NotExists() || existsItem.SubFilter() != nil {
+		t.Fatalf("unexpected not exists item: %#v", existsItem)
+	}
+
+	if err := filter.Exists("name", nil); err == nil {
+		t.Fatal("Exists should fail on basic field")
+	}
+	if err := filter.Exists("members", filter); err == nil {
+		t.Fatal("Exists should fail with filter of another model")
+	}
+	if filter.GetExistsItem("name") != nil {
+		t.Fatal("unexpected exists item on basic field")
+	}
+}
+
+// TestFilterMatch tests the full-text Match operator
+func TestFilterMatch(t *testing.T) {
+	filter := newFilter(NewValue(reflect.ValueOf(TestStruct{ID: 1})))
+
+	if err := filter.Match("name", "orm & go"); err != nil {
+		t.Fatalf("Match(name) failed: %v", err)
+	}
+	item := filter.GetFilterItem("name")
+	if item == nil || item.OprCode() != models.MatchOpr || item.OprValue().Get() != "orm & go" {
+		t.Fatalf("unexpected match item: %#v", item)
+	}
+
+	if err := filter.Match("name", 1); err == nil {
+		t.Fatal("Match should fail with non-string value")
+	}
+	if err := filter.Match("name", ""); err == nil {
+		t.Fatal("Match should fail with empty value")
+	}
+}
@@ -58,6 +58,16 @@ func getOrmSpec(spec string) (ret *SpecImpl, err *cd.Error) {
 			ret.valueDeclare = models.Snowflake
 		case models.DateTime:
 			ret.valueDeclare = models.DateTime
+		case models.SoftDelete:
+			ret.valueDeclare = models.SoftDelete
+		case models.Version:
+			ret.valueDeclare = models.Version
+		case models.CreatedAt:
+			ret.valueDeclare = models.CreatedAt
+		case models.UpdatedAt:
+			ret.valueDeclare = models.UpdatedAt
+		case models.FullText:
+			ret.valueDeclare = models.FullText
 		case models.KeyTag:
 			ret.primaryKey = true
 		}
@@ -2,6 +2,7 @@ package local
 
 import (
 	"testing"
+	"time"
 
 	"github.com/muidea/magicOrm/models"
 )
@@ -79,6 +80,10 @@ func TestSpecValueDeclares(t *testing.T) {
 		{"UUID", "field uuid", "field", models.UUID, false},
 		{"Snowflake", "field snowflake", "field", models.Snowflake, false},
 		{"DateTime", "field datetime", "field", models.DateTime, false},
+		{"SoftDelete", "field softdelete", "field", models.SoftDelete, false},
+		{"Version", "field version", "field", models.Version, false},
+		{"CreatedAt", "field createdAt", "field", models.CreatedAt, false},
+		{"UpdatedAt", "field updatedAt", "field", models.UpdatedAt, false},
 		{"PrimaryKey", "field key", "field", models.Customer, true},
 		{"Primary", "field key", "field", models.Customer, true},
 		{"PrimaryAndAuto", "field key auto", "field", models.AutoIncrement, true},
@@ -161,3 +166,98 @@ func TestInvalidSpecs(t *testing.T) {
 		}
 	}
 }
+
+type softDeleteSpecEntity struct {
+	ID        int64      `orm:"id key auto"`
+	Name      string     `orm:"name"`
+	DeletedAt *time.Time `orm:"deletedAt softdelete"`
+}
+
+type softDeleteValueEntity struct {
+	ID        int64     `orm:"id key auto"`
+	DeletedAt time.Time `orm:"deletedAt softdelete"`
+}
+
+type softDeleteTwiceEntity struct {
+	ID        int64      `orm:"id key auto"`
+	DeletedAt *time.Time `orm:"deletedAt softdelete"`
+	RemovedAt *time.Time `orm:"removedAt softdelete"`
+}
+
+func TestSoftDeleteSpec(t *testing.T) {
+	entityModel, err := GetEntityModel(&softDeleteSpecEntity{}, nil)
+	if err != nil {
+		t.Fatalf("GetEntityModel(softDeleteSpecEntity) failed: %s", err.Error())
+	}
+	deletedField := models.GetSoftDeleteField(entityModel)
+	if deletedField == nil || deletedField.GetName() != "deletedAt" {
+		t.Fatalf("unexpected soft delete field: %v", deletedField)
+	}
+
+	if _, err = GetEntityModel(&softDeleteValueEntity{}, nil); err == nil {
+		t.Fatal("non pointer soft delete field should be rejected")
+	}
+	if _, err = GetEntityModel(&softDeleteTwiceEntity{}, nil); err == nil {
+		t.Fatal("more than one soft delete field should be rejected")
+	}
+}
+
+type versionSpecEntity struct {
+	ID      int64  `orm:"id key auto"`
+	Name    string `orm:"name"`
+	Version int64  `orm:"version version"`
+}
+
+type versionPtrEntity struct {
+	ID      int64  `orm:"id key auto"`
+	Version *int64 `orm:"version version"`
+}
+
+type versionStringEntity struct {
+	ID      int64  `orm:"id key auto"`
+	Version string `orm:"version version"`
+}
+
+func TestVersionSpec(t *testing.T) {
+	entityModel, err := GetEntityModel(&versionSpecEntity{}, nil)
+	if err != nil {
+		t.Fatalf("GetEntityModel(versionSpecEntity) failed: %s", err.Error())
+	}
+	versionField := models.GetVersionField(entityModel)
+	if versionField == nil || versionField.GetName() != "version" {
+		t.Fatalf("unexpected version field: %v", versionField)
+	}
+
+	if _, err = GetEntityModel(&versionPtrEntity{}, nil); err == nil {
+		t.Fatal("optional version field should be rejected")
+	}
+	if _, err = GetEntityModel(&versionStringEntity{}, nil); err == nil {
+		t.Fatal("non integer version field should be rejected")
+	}
+}
+
+type timestampSpecEntity struct {
+	ID        int64      `orm:"id key auto"`
+	CreatedAt time.Time  `orm:"createdAt createdAt"`
+	UpdatedAt *time.Time `orm:"updatedAt updatedAt"`
+}
+
+type timestampStringEntity struct {
+	ID        int64  `orm:"id key auto"`
+	CreatedAt string `orm:"createdAt createdAt"`
+}
+
+func TestTimestampSpec(t *testing.T) {
+	entityModel, err := GetEntityModel(&timestampSpecEntity{}, nil)
+	if err != nil {
+		t.Fatalf("GetEntityModel(timestampSpecEntity) failed: %s", err.Error())
+	}
+	updatedField := models.GetUpdatedAtField(entityModel)
+	if updatedField == nil || updatedField.GetName() != "updatedAt" {
+		t.Fatalf("unexpected updatedAt field: %v", updatedField)
+	}
+
+	if _, err = GetEntityModel(&timestampStringEntity{}, nil); err == nil {
+		t.Fatal("non datetime createdAt field should be rejected")
+	}
+}
@@ -2,6 +2,8 @@ package remote
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 
 	"log/slog"
 
@@ -23,19 +25,42 @@ func (s *filterItem) OprValue() models.Value {
 	return s.value
 }
 
+// ExistsValue 关系字段的存在性条件，Filter绑定关联模型，为空时只要求存在关联对象
+type ExistsValue struct {
+	Name   string        `json:"name"`
+	Not    bool          `json:"not,omitempty"`
+	Filter *ObjectFilter `json:"filter,omitempty"`
+}
+
+func (s *ExistsValue) NotExists() bool {
+	return s.Not
+}
+
+func (s *ExistsValue) SubFilter() models.Filter {
+	if s.Filter == nil {
+		return nil
+	}
+
+	return s.Filter
+}
+
 type ObjectFilter struct {
-	Name           string            `json:"name"`
-	PkgPath        string            `json:"pkgPath"`
-	EqualFilter    []*FieldValue     `json:"equal"`
-	NotEqualFilter []*FieldValue     `json:"noEqual"`
-	BelowFilter    []*FieldValue     `json:"below"`
-	AboveFilter    []*FieldValue     `json:"above"`
-	InFilter       []*FieldValue     `json:"in"`
-	NotInFilter    []*FieldValue     `json:"notIn"`
-	LikeFilter     []*FieldValue     `json:"like"`
-	MaskValue      *ObjectValue      `json:"maskValue"`
-	PageFilter     *utils.Pagination `json:"page"`
-	SortFilter     *utils.SortFilter `json:"sort"`
+	Name           string              `json:"name"`
+	PkgPath        string              `json:"pkgPath"`
+	EqualFilter    []*FieldValue       `json:"equal"`
+	NotEqualFilter []*FieldValue       `json:"noEqual"`
+	BelowFilter    []*FieldValue       `json:"below"`
+	AboveFilter    []*FieldValue       `json:"above"`
+	InFilter       []*FieldValue       `json:"in"`
+	NotInFilter    []*FieldValue       `json:"notIn"`
+	LikeFilter     []*FieldValue       `json:"like"`
+	MatchFilter    []*FieldValue       `json:"match,omitempty"`
+	ExistsFilter   []*ExistsValue      `json:"exists,omitempty"`
+	MaskValue      *ObjectValue        `json:"maskValue"`
+	PageFilter     *utils.Pagination   `json:"page"`
+	SortFilter     *utils.SortFilter   `json:"sort"`
+	DeletedScope   models.DeletedScope `json:"deletedScope,omitempty"`
+	SelectFields   []string            `json:"select,omitempty"`
 
 	bindObject *Object `json:"-"`
 }
@@ -92,6 +117,11 @@ func (s *ObjectFilter) GetInt(key string) (ret int, ok bool) {
 }
 
 func (s *ObjectFilter) Equal(key string, val any) (err *cd.Error) {
+	if strings.Contains(key, models.FieldPathSeparator) {
+		s.EqualFilter = s.appendPathItem(s.EqualFilter, key, val)
+		return
+	}
+
 	vField := s.bindObject.GetField(key)
 	if vField == nil {
 		return
@@ -109,6 +139,11 @@ func (s *ObjectFilter) Equal(key string, val any) (err *cd.Error) {
 }
 
 func (s *ObjectFilter) NotEqual(key string, val any) (err *cd.Error) {
+	if strings.Contains(key, models.FieldPathSeparator) {
+		s.NotEqualFilter = s.appendPathItem(s.NotEqualFilter, key, val)
+		return
+	}
+
 	vField := s.bindObject.GetField(key)
 	if vField == nil {
 		return
@@ -127,6 +162,11 @@ func (s *ObjectFilter) NotEqual(key string, val any) (err *cd.Error) {
 }
 
 func (s *ObjectFilter) Below(key string, val any) (err *cd.Error) {
+	if strings.Contains(key, models.FieldPathSeparator) {
+		s.BelowFilter = s.appendPathItem(s.BelowFilter, key, val)
+		return
+	}
+
 	vField := s.bindObject.GetField(key)
 	if vField == nil {
 		return
@@ -144,6 +184,11 @@ func (s *ObjectFilter) Below(key string, val any) (err *cd.Error) {
 }
 
 func (s *ObjectFilter) Above(key string, val any) (err *cd.Error) {
+	if strings.Contains(key, models.FieldPathSeparator) {
+		s.AboveFilter = s.appendPathItem(s.AboveFilter, key, val)
+		return
+	}
+
 	vField := s.bindObject.GetField(key)
 	if vField == nil {
 		return
@@ -161,6 +206,11 @@ func (s *ObjectFilter) Above(key string, val any) (err *cd.Error) {
 }
 
 func (s *ObjectFilter) In(key string, val any) (err *cd.Error) {
+	if strings.Contains(key, models.FieldPathSeparator) {
+		s.InFilter = s.appendPathItem(s.InFilter, key, val)
+		return
+	}
+
 	vField := s.bindObject.GetField(key)
 	if vField == nil {
 		return
@@ -183,6 +233,11 @@ func (s *ObjectFilter) In(key string, val any) (err *cd.Error) {
 }
 
 func (s *ObjectFilter) NotIn(key string, val any) (err *cd.Error) {
+	if strings.Contains(key, models.FieldPathSeparator) {
+		s.NotInFilter = s.appendPathItem(s.NotInFilter, key, val)
+		return
+	}
+
 	vField := s.bindObject.GetField(key)
 	if vField == nil {
 		return
@@ -220,6 +275,11 @@ func convertFilterCollectionValue(vType models.Type, val any) (ret any, err *cd.
 }
 
 func (s *ObjectFilter) Like(key string, val any) (err *cd.Error) {
+	if strings.Contains(key, models.FieldPathSeparator) {
+		s.LikeFilter = s.appendPathItem(s.LikeFilter, key, val)
+		return
+	}
+
 	vField := s.bindObject.GetField(key)
 	if vField == nil {
 		return
@@ -236,6 +296,90 @@ func (s *ObjectFilter) Like(key string, val any) (err *cd.Error) {
 	return
 }
 
+func (s *ObjectFilter) Match(key string, val any) (err *cd.Error) {
+	if strings.Contains(key, models.FieldPathSeparator) {
+		s.MatchFilter = s.appendPathItem(s.MatchFilter, key, val)
+		return
+	}
+
+	vField := s.bindObject.GetField(key)
+	if vField == nil {
+		return
+	}
+
+	vVal, vErr := convertValue(vField.GetType(), val)
+	if vErr != nil || vVal == nil {
+		return
+	}
+
+	item := &FieldValue{Name: key}
+	item.Set(vVal)
+	s.MatchFilter = append(s.MatchFilter, item)
+	return
+}
+
+func (s *ObjectFilter) Exists(key string, val models.Filter) (err *cd.Error) {
+	return s.setExists(key, val, false)
+}
+
+func (s *ObjectFilter) NotExists(key string, val models.Filter) (err *cd.Error) {
+	return s.setExists(key, val, true)
+}
+
+// setExists 校验key为关系字段，且val为绑定该关系关联模型的ObjectFilter
+func (s *ObjectFilter) setExists(key string, val models.Filter, notExists bool) (err *cd.Error) {
+	vField := s.bindObject.GetField(key)
+	if vField == nil || models.IsBasicField(vField) {
+		err = cd.NewError(cd.IllegalParam, fmt.Sprintf("illegal exists field, name:%s", key))
+		return
+	}
+
+	item := &ExistsValue{Name: key, Not: notExists}
+	if val != nil {
+		subFilter, ok := val.(*ObjectFilter)
+		if !ok {
+			err = cd.NewError(cd.IllegalParam, fmt.Sprintf("illegal exists filter, field:%s", key))
+			return
+		}
+		elemType := vField.GetType().Elem()
+		if subFilter.GetName() != elemType.GetName() || subFilter.GetPkgPath() != elemType.GetPkgPath() {
+			err = cd.NewError(cd.IllegalParam, fmt.Sprintf("exists filter model mismatch relation field, %s != %s", subFilter.GetName(), elemType.GetName()))
+			return
+		}
+		item.Filter = subFilter
+	}
+
+	for idx, existsVal := range s.ExistsFilter {
+		if existsVal.Name == key {
+			s.ExistsFilter[idx] = item
+			return
+		}
+	}
+	s.ExistsFilter = append(s.ExistsFilter, item)
+	return
+}
+
+func (s *ObjectFilter) GetExistsItem(key string) models.ExistsItem {
+	for _, existsVal := range s.ExistsFilter {
+		if existsVal.Name == key {
+			return existsVal
+		}
+	}
+
+	return nil
+}
+
+// appendPathItem 点路径过滤项只校验首段为关系字段，关联模型不在filter中，值在构造SQL时按目标字段类型转换
+func (s *ObjectFilter) appendPathItem(items []*FieldValue, key string, val any) []*FieldValue {
+	fieldName, _, _ := strings.Cut(key, models.FieldPathSeparator)
+	vField := s.bindObject.GetField(fieldName)
+	if vField == nil || models.IsBasic(vField.GetType()) || val == nil {
+		return items
+	}
+
+	return append(items, &FieldValue{Name: key, Value: val})
+}
+
 func (s *ObjectFilter) Pagination(pageNum, pageSize int) {
 	s.PageFilter = &utils.Pagination{
 		PageNum:  pageNum,
@@ -345,9 +489,49 @@ func (s *ObjectFilter) GetFilterItem(key string) models.FilterItem {
 		return &filterItem{oprCode: models.LikeOpr, value: NewValue(itemVal.Get())}
 	}
 
+	itemVal, itemErr = s.getFilterValue(key, s.MatchFilter)
+	if itemErr != nil {
+		return nil
+	}
+	if itemVal != nil {
+		return &filterItem{oprCode: models.MatchOpr, value: NewValue(itemVal.Get())}
+	}
+
 	return nil
 }
 
+func (s *ObjectFilter) GetPathFilterItems(fieldName string) (ret map[string]models.FilterItem) {
+	prefix := fieldName + models.FieldPathSeparator
+	for _, filterItems := range []struct {
+		oprCode models.OprCode
+		items   []*FieldValue
+	}{
+		{models.EqualOpr, s.EqualFilter},
+		{models.NotEqualOpr, s.NotEqualFilter},
+		{models.BelowOpr, s.BelowFilter},
+		{models.AboveOpr, s.AboveFilter},
+		{models.InOpr, s.InFilter},
+		{models.NotInOpr, s.NotInFilter},
+		{models.LikeOpr, s.LikeFilter},
+		{models.MatchOpr, s.MatchFilter},
+	} {
+		for _, item := range filterItems.items {
+			subPath, ok := strings.CutPrefix(item.Name, prefix)
+			if !ok {
+				continue
+			}
+			if _, exist := ret[subPath]; exist {
+				continue
+			}
+			if ret == nil {
+				ret = map[string]models.FilterItem{}
+			}
+			ret[subPath] = &filterItem{oprCode: filterItems.oprCode, value: NewValue(item.Get())}
+		}
+	}
+	return
+}
+
 func (s *ObjectFilter) getFilterValue(key string, items []*FieldValue) (ret *FieldValue, err *cd.Error) {
 	for _, val := range items {
 		if key == val.Name {
@@ -362,6 +546,30 @@ func (s *ObjectFilter) getFilterValue(key string, items []*FieldValue) (ret *Fie
 	return
 }
 
+func (s *ObjectFilter) Deleted(scope models.DeletedScope) {
+	s.DeletedScope = scope
+}
+
+func (s *ObjectFilter) GetDeletedScope() models.DeletedScope {
+	return s.DeletedScope
+}
+
+func (s *ObjectFilter) Select(fieldNames ...string) (err *cd.Error) {
+	for _, name := range fieldNames {
+		if name == "" || strings.Contains(name, models.FieldPathSeparator) {
+			err = cd.NewError(cd.IllegalParam, fmt.Sprintf("illegal select field, name:%s", name))
+			return
+		}
+	}
+
+	s.SelectFields = fieldNames
+	return
+}
+
+func (s *ObjectFilter) GetSelectFields() []string {
+	return s.SelectFields
+}
+
 func (s *ObjectFilter) Paginationer() models.Paginationer {
 	if s.PageFilter == nil {
 		return nil
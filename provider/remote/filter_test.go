@@ -402,3 +402,40 @@ func TestObjectFilterValueMaskAdditionalBranches(t *testing.T) {
 		t.Fatal("ValueMask(nil) should fail")
 	}
 }
+
+func TestObjectFilterPathItems(t *testing.T) {
+	filter := NewFilter(testRemoteFilterObject())
+
+	if err := filter.Like("group.name", "ops%"); err != nil {
+		t.Fatalf("Like(group.name) failed: %v", err)
+	}
+	if err := filter.In("group.owner.id", []int64{1, 2}); err != nil {
+		t.Fatalf("In(group.owner.id) failed: %v", err)
+	}
+	if err := filter.Equal("name.first", "alice"); err != nil {
+		t.Fatalf("Equal(name.first) failed: %v", err)
+	}
+
+	data, err := json.Marshal(filter)
+	if err != nil {
+		t.Fatalf("marshal filter failed: %v", err)
+	}
+	decoded := NewFilter(testRemoteFilterObject())
+	if err = json.Unmarshal(data, decoded); err != nil {
+		t.Fatalf("unmarshal filter failed: %v", err)
+	}
+
+	items := decoded.GetPathFilterItems("group")
+	if len(items) != 2 || items["name"] == nil || items["owner.id"] == nil {
+		t.Fatalf("unexpected group path items: %#v", items)
+	}
+	if items["name"].OprCode() != models.LikeOpr || items["name"].OprValue().Get() != "ops%" {
+		t.Fatalf("unexpected group.name item: %v %v", items["name"].OprCode(), items["name"].OprValue().Get())
+	}
+	if items["owner.id"].OprCode() != models.InOpr || len(items["owner.id"].OprValue().UnpackValue()) != 2 {
+		t.Fatalf("unexpected group.owner.id item: %#v", items["owner.id"].OprValue().Get())
+	}
+	if decoded.GetFilterItem("group") != nil || decoded.GetPathFilterItems("name") != nil {
+		t.Fatal("path items should only be reachable through relation fields")
+	}
+}
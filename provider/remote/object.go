@@ -336,6 +336,18 @@ func (s *Object) Verify() (err *cd.Error) {
 			return
 		}
 	}
+
+	err = models.VerifySoftDelete(s)
+	if err != nil {
+		return
+	}
+
+	err = models.VerifyVersion(s)
+	if err != nil {
+		return
+	}
+
+	err = models.VerifyTimestamp(s)
 	return
 }
 